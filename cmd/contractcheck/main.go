@@ -0,0 +1,40 @@
+// contractcheck exercita os métodos do gowa.Client contra um stub gerado a
+// partir do OpenAPI e falha se o client divergir do spec. O mesmo teste roda
+// no go test (TestContract em pkg/gowatest); use este comando para checar
+// outro arquivo de spec.
+//
+//	go run ./cmd/contractcheck -spec doc/openapi.yaml
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+func main() {
+	specPath := flag.String("spec", "doc/openapi.yaml", "caminho do OpenAPI")
+	flag.Parse()
+
+	spec, err := gowatest.LoadSpec(*specPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %v\n", err)
+		os.Exit(2)
+	}
+	violations, err := gowatest.CheckClient(context.Background(), spec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[ERROR] %v\n", err)
+		os.Exit(2)
+	}
+	for _, v := range violations {
+		fmt.Println("[DRIFT]", v)
+	}
+	if len(violations) > 0 {
+		fmt.Printf("[FAIL] %d divergência(s) entre o client e %s\n", len(violations), *specPath)
+		os.Exit(1)
+	}
+	fmt.Println("[OK] client em conformidade com", *specPath)
+}
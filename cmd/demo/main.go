@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"time"
 
@@ -21,6 +22,7 @@ func main() {
 		Username: os.Getenv("GOWA_USER"),
 		Password: os.Getenv("GOWA_PASS"),
 		Timeout:  20 * time.Second,
+		Logger:   demoLogger(),
 	})
 	if err != nil {
 		panic(err)
@@ -34,4 +36,29 @@ func main() {
 		b, _ := json.MarshalIndent(login, "", "  ")
 		fmt.Printf("[RESPONSE] Login: %s\n", b)
 	}
+
+	if os.Getenv("GOWA_LOGIN_FLOW") != "" {
+		fmt.Println("[INFO] Iniciando LoginFlow (QR no terminal; GOWA_PHONE usa código de pareamento)")
+		flow, err := gowa.NewLoginFlow(cli, gowa.LoginFlowConfig{
+			Phone:   os.Getenv("GOWA_PHONE"),
+			OnEvent: gowa.TerminalLoginHandler(os.Stdout, false),
+		})
+		if err != nil {
+			panic(err)
+		}
+		lctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+		defer cancel()
+		if _, err := flow.Run(lctx); err != nil {
+			fmt.Printf("[ERROR] LoginFlow: %v\n", err)
+		}
+	}
+}
+
+// demoLogger liga os logs do client quando GOWA_LOG=debug|info|warn|error.
+func demoLogger() *slog.Logger {
+	var level slog.Level
+	if v := os.Getenv("GOWA_LOG"); v == "" || level.UnmarshalText([]byte(v)) != nil {
+		return nil
+	}
+	return slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level}))
 }
@@ -0,0 +1,401 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+)
+
+type generator struct {
+	spec     *spec
+	existing map[string]bool // tipos escritos à mão
+	queued   map[string]bool // componentes já enfileirados para geração
+	queue    []string
+	imports  map[string]bool
+}
+
+func generate(s *spec, pkg, specPath string, existing map[string]bool) ([]byte, error) {
+	g := &generator{spec: s, existing: existing, queued: map[string]bool{}, imports: map[string]bool{"context": true}}
+
+	var methods, requests bytes.Buffer
+	for _, p := range s.Paths.Keys {
+		verbs := make([]string, 0, len(s.Paths.M[p]))
+		for v := range s.Paths.M[p] {
+			verbs = append(verbs, v)
+		}
+		sort.Strings(verbs)
+		for _, verb := range verbs {
+			if err := g.operation(&methods, &requests, strings.ToUpper(verb), p, s.Paths.M[p][verb]); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", strings.ToUpper(verb), p, err)
+			}
+		}
+	}
+
+	var types bytes.Buffer
+	for i := 0; i < len(g.queue); i++ {
+		name := g.queue[i]
+		sc := s.Components.Schemas.M[name]
+		if d := firstLine(sc.Description); d != "" {
+			fmt.Fprintf(&types, "// %s: %s\n", name, d)
+		}
+		if res := envelopeResults(sc); res != nil {
+			g.envelope(&types, name, res)
+			continue
+		}
+		fmt.Fprintf(&types, "type %s %s\n\n", name, g.goType(sc, false))
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by gowagen from %s. DO NOT EDIT.\n\npackage %s\n\n", specPath, pkg)
+	out.WriteString("import (\n")
+	imps := make([]string, 0, len(g.imports))
+	for imp := range g.imports {
+		imps = append(imps, imp)
+	}
+	sort.Strings(imps)
+	for _, imp := range imps {
+		fmt.Fprintf(&out, "%q\n", imp)
+	}
+	out.WriteString(")\n\n")
+	out.Write(requests.Bytes())
+	out.Write(types.Bytes())
+	out.Write(methods.Bytes())
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format: %w\n%s", err, out.Bytes())
+	}
+	return src, nil
+}
+
+// field é um campo do struct de requisição de uma operação.
+type field struct {
+	Key, Name, Type, Comment string
+	In                       string // path, query, body ou file
+	Required                 bool
+}
+
+func (g *generator) operation(methods, requests *bytes.Buffer, verb, path string, op *operation) error {
+	if op.OperationID == "" {
+		return fmt.Errorf("missing operationId")
+	}
+	name := goName(op.OperationID)
+	reqType := name + "Request"
+	if strings.HasSuffix(name, "Request") {
+		reqType = name // approveGroupParticipantRequest
+	}
+	if g.existing[reqType] {
+		return fmt.Errorf("type %s already declared by hand", reqType)
+	}
+
+	var fields []field
+	for _, p := range op.Parameters {
+		t := g.goType(p.Schema, false)
+		if p.In == "query" && t == "bool" && !p.Required {
+			t = "*bool"
+		}
+		fields = append(fields, field{Key: p.Name, Name: goName(p.Name), Type: t, Comment: firstLine(p.Description), In: p.In, Required: p.Required})
+	}
+
+	contentType := ""
+	if op.RequestBody != nil {
+		for _, ct := range []string{"application/json", "multipart/form-data"} {
+			if c, ok := op.RequestBody.Content[ct]; ok {
+				contentType = ct
+				body := g.spec.resolve(c.Schema)
+				if body == nil || len(body.Properties.Keys) == 0 {
+					return fmt.Errorf("request body without properties")
+				}
+				for _, k := range body.Properties.Keys {
+					ps := g.spec.resolve(body.Properties.M[k])
+					f := field{Key: k, Name: goName(k), Comment: firstLine(ps.Description), In: "body", Required: body.isRequired(k)}
+					if ps.Type == "string" && ps.Format == "binary" {
+						f.In, f.Type, f.Comment = "file", "string", "caminho do arquivo local"
+					} else {
+						f.Type = g.goType(ps, false)
+					}
+					fields = append(fields, f)
+				}
+				break
+			}
+		}
+		if contentType == "" {
+			return fmt.Errorf("unsupported request content type")
+		}
+	}
+	seen := map[string]bool{}
+	files := 0
+	for _, f := range fields {
+		if seen[f.Name] {
+			return fmt.Errorf("duplicate field %s", f.Name)
+		}
+		seen[f.Name] = true
+		if f.In == "file" {
+			files++
+		}
+		if contentType == "multipart/form-data" && f.In == "body" && strings.ContainsAny(f.Type, "[{*") {
+			return fmt.Errorf("multipart field %s: unsupported type %s", f.Key, f.Type)
+		}
+	}
+	if files > 1 {
+		return fmt.Errorf("more than one file part")
+	}
+
+	resp, ok := op.Responses["200"]
+	if !ok || resp.Content["application/json"].Schema == nil || resp.Content["application/json"].Schema.Ref == "" {
+		return fmt.Errorf("200 response must reference a component schema")
+	}
+	respType := resp.Content["application/json"].Schema.refName()
+	g.enqueue(respType)
+
+	if len(fields) > 0 {
+		fmt.Fprintf(requests, "// %s são os parâmetros de %s %s.\n", reqType, verb, path)
+		fmt.Fprintf(requests, "type %s struct {\n", reqType)
+		for _, f := range fields {
+			tag := "-"
+			if f.In == "body" {
+				tag = f.Key
+				if !f.Required {
+					tag += ",omitempty"
+				}
+			}
+			comment := f.Comment
+			if f.In != "body" {
+				comment = strings.TrimSpace(f.In + "; " + comment)
+				comment = strings.TrimSuffix(comment, ";")
+			}
+			fmt.Fprintf(requests, "%s %s `json:%q`", f.Name, f.Type, tag)
+			if comment != "" {
+				fmt.Fprintf(requests, " // %s", comment)
+			}
+			requests.WriteString("\n")
+		}
+		requests.WriteString("}\n\n")
+	}
+
+	fmt.Fprintf(methods, "// %s chama %s %s: %s\n", name, verb, path, strings.TrimSpace(op.Summary))
+	if len(fields) > 0 {
+		fmt.Fprintf(methods, "func (a *RawAPI) %s(ctx context.Context, req %s) (*%s, error) {\n", name, reqType, respType)
+	} else {
+		fmt.Fprintf(methods, "func (a *RawAPI) %s(ctx context.Context) (*%s, error) {\n", name, respType)
+	}
+	g.writePath(methods, path)
+	hasQuery := g.writeValues(methods, "q", "url.Values{}", "q.Set", fields, "query")
+	if hasQuery {
+		g.imports["net/url"] = true
+	}
+	fmt.Fprintf(methods, "var out %s\n", respType)
+	reqArg := "nil"
+	if len(fields) > 0 {
+		reqArg = "req"
+	}
+	switch {
+	case verb == "GET":
+		q := "nil"
+		if hasQuery {
+			q = "q"
+		}
+		fmt.Fprintf(methods, "if err := a.c.getJSON(ctx, %q, %q, p, %s, %s, &out); err != nil {\nreturn nil, err\n}\n", op.OperationID, path, q, reqArg)
+	case verb == "POST":
+		if hasQuery {
+			methods.WriteString("if len(q) > 0 {\np += \"?\" + q.Encode()\n}\n")
+		}
+		switch contentType {
+		case "multipart/form-data":
+			if !g.writeValues(methods, "fields", "map[string]string{}", "", fields, "body") {
+				methods.WriteString("var fields map[string]string\n")
+			}
+			fileKey, fileExpr := `""`, `""`
+			for _, f := range fields {
+				if f.In == "file" {
+					fileKey, fileExpr = fmt.Sprintf("%q", f.Key), "req."+f.Name
+				}
+			}
+			fmt.Fprintf(methods, "if err := a.c.postFormFile(ctx, %q, %q, p, %s, fields, %s, %s, &out); err != nil {\nreturn nil, err\n}\n", op.OperationID, path, reqArg, fileKey, fileExpr)
+		case "application/json":
+			fmt.Fprintf(methods, "if err := a.c.postJSON(ctx, %q, %q, p, req, req, &out); err != nil {\nreturn nil, err\n}\n", op.OperationID, path)
+		default:
+			fmt.Fprintf(methods, "if err := a.c.postJSON(ctx, %q, %q, p, %s, nil, &out); err != nil {\nreturn nil, err\n}\n", op.OperationID, path, reqArg)
+		}
+	default:
+		return fmt.Errorf("unsupported method")
+	}
+	methods.WriteString("return &out, nil\n}\n\n")
+	return nil
+}
+
+// writePath monta o path substituindo os parâmetros {x} por req.X escapado.
+func (g *generator) writePath(w *bytes.Buffer, path string) {
+	if !strings.Contains(path, "{") {
+		fmt.Fprintf(w, "p := %q\n", path)
+		return
+	}
+	g.imports["net/url"] = true
+	var parts []string
+	rest := path
+	for {
+		i := strings.Index(rest, "{")
+		if i < 0 {
+			break
+		}
+		j := strings.Index(rest, "}")
+		if i > 0 {
+			parts = append(parts, fmt.Sprintf("%q", rest[:i]))
+		}
+		parts = append(parts, "url.PathEscape(req."+goName(rest[i+1:j])+")")
+		rest = rest[j+1:]
+	}
+	if rest != "" {
+		parts = append(parts, fmt.Sprintf("%q", rest))
+	}
+	fmt.Fprintf(w, "p := %s\n", strings.Join(parts, " + "))
+}
+
+// writeValues gera a serialização textual (query ou campos multipart) dos
+// campos em in, omitindo valores zero.
+func (g *generator) writeValues(w *bytes.Buffer, v, init, set string, fields []field, in string) bool {
+	any := false
+	for _, f := range fields {
+		if f.In != in {
+			continue
+		}
+		if !any {
+			fmt.Fprintf(w, "%s := %s\n", v, init)
+			any = true
+		}
+		assign := func(expr string) string {
+			if set != "" {
+				return fmt.Sprintf("%s(%q, %s)", set, f.Key, expr)
+			}
+			return fmt.Sprintf("%s[%q] = %s", v, f.Key, expr)
+		}
+		ref := "req." + f.Name
+		switch f.Type {
+		case "string":
+			fmt.Fprintf(w, "if %s != \"\" {\n%s\n}\n", ref, assign(ref))
+		case "int":
+			g.imports["strconv"] = true
+			fmt.Fprintf(w, "if %s != 0 {\n%s\n}\n", ref, assign("strconv.Itoa("+ref+")"))
+		case "float64":
+			g.imports["strconv"] = true
+			fmt.Fprintf(w, "if %s != 0 {\n%s\n}\n", ref, assign("strconv.FormatFloat("+ref+", 'f', -1, 64)"))
+		case "bool":
+			g.imports["strconv"] = true
+			if f.Required {
+				fmt.Fprintf(w, "%s\n", assign("strconv.FormatBool("+ref+")"))
+			} else {
+				fmt.Fprintf(w, "if %s {\n%s\n}\n", ref, assign(`"true"`))
+			}
+		case "*bool":
+			g.imports["strconv"] = true
+			fmt.Fprintf(w, "if %s != nil {\n%s\n}\n", ref, assign("strconv.FormatBool(*"+ref+")"))
+		}
+	}
+	return any
+}
+
+// envelopeResults retorna o schema de results quando o componente tem o
+// envelope {code, message, results}; nil caso contrário.
+func envelopeResults(sc *schema) *schema {
+	for _, k := range []string{"code", "message", "results"} {
+		if _, ok := sc.Properties.M[k]; !ok {
+			return nil
+		}
+	}
+	return sc.Properties.M["results"]
+}
+
+// envelope declara name como alias de Response[T] ou, se o schema tiver campos
+// além do envelope, como struct que embute Response[T]. Structs inline de
+// results ganham o nome <Base>Result, como os tipos escritos à mão.
+func (g *generator) envelope(w *bytes.Buffer, name string, res *schema) {
+	result := strings.TrimSuffix(name, "Response") + "Result"
+	t := g.goType(res, false)
+	switch {
+	case strings.HasPrefix(t, "struct {"):
+		if !g.existing[result] {
+			fmt.Fprintf(w, "type %s %s\n\n", result, t)
+		}
+		t = result
+	case strings.HasPrefix(t, "[]struct {"):
+		if !g.existing[result] {
+			fmt.Fprintf(w, "type %s %s\n\n", result, t[2:])
+		}
+		t = "[]" + result
+	}
+	sc := g.spec.Components.Schemas.M[name]
+	if len(sc.Properties.Keys) == 3 {
+		fmt.Fprintf(w, "type %s = Response[%s]\n\n", name, t)
+		return
+	}
+	fmt.Fprintf(w, "type %s struct {\nResponse[%s]\n", name, t)
+	for _, k := range sc.Properties.Keys {
+		if k == "code" || k == "message" || k == "results" {
+			continue
+		}
+		fmt.Fprintf(w, "%s %s `json:%q`\n", goName(k), g.goType(sc.Properties.M[k], true), k)
+	}
+	w.WriteString("}\n\n")
+}
+
+func (g *generator) enqueue(name string) {
+	if g.existing[name] || g.queued[name] {
+		return
+	}
+	g.queued[name] = true
+	g.queue = append(g.queue, name)
+}
+
+// goType traduz um schema em tipo Go; objetos inline viram structs anônimos,
+// como nos tipos de resposta escritos à mão.
+func (g *generator) goType(sc *schema, optional bool) string {
+	if sc == nil {
+		return "any"
+	}
+	if sc.Ref != "" {
+		name := sc.refName()
+		g.enqueue(name)
+		return name
+	}
+	var t string
+	switch sc.Type {
+	case "string":
+		t = "string"
+	case "integer":
+		t = "int"
+	case "number":
+		t = "float64"
+	case "boolean":
+		t = "bool"
+	case "array":
+		return "[]" + g.goType(sc.Items, false)
+	case "object", "":
+		if len(sc.Properties.Keys) == 0 {
+			return "any"
+		}
+		var b strings.Builder
+		b.WriteString("struct {\n")
+		for _, k := range sc.Properties.Keys {
+			p := sc.Properties.M[k]
+			fmt.Fprintf(&b, "%s %s `json:%q`", goName(k), g.goType(p, true), k)
+			if d := firstLine(p.Description); d != "" {
+				fmt.Fprintf(&b, " // %s", d)
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString("}")
+		return b.String()
+	default:
+		return "any"
+	}
+	if sc.Nullable && optional {
+		return "*" + t
+	}
+	return t
+}
+
+func firstLine(s string) string {
+	s, _, _ = strings.Cut(strings.TrimSpace(s), "\n")
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+type spec struct {
+	Paths      ordered[map[string]*operation] `yaml:"paths"`
+	Components struct {
+		Schemas ordered[*schema] `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+type operation struct {
+	OperationID string      `yaml:"operationId"`
+	Summary     string      `yaml:"summary"`
+	Parameters  []parameter `yaml:"parameters"`
+	RequestBody *struct {
+		Content map[string]struct {
+			Schema *schema `yaml:"schema"`
+		} `yaml:"content"`
+	} `yaml:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]struct {
+			Schema *schema `yaml:"schema"`
+		} `yaml:"content"`
+	} `yaml:"responses"`
+}
+
+type parameter struct {
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Required    bool    `yaml:"required"`
+	Description string  `yaml:"description"`
+	Schema      *schema `yaml:"schema"`
+}
+
+type schema struct {
+	Ref         string           `yaml:"$ref"`
+	Type        string           `yaml:"type"`
+	Format      string           `yaml:"format"`
+	Description string           `yaml:"description"`
+	Properties  ordered[*schema] `yaml:"properties"`
+	Items       *schema          `yaml:"items"`
+	Required    []string         `yaml:"required"`
+	Nullable    bool             `yaml:"nullable"`
+}
+
+func (s *schema) isRequired(name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *schema) refName() string {
+	return strings.TrimPrefix(s.Ref, "#/components/schemas/")
+}
+
+// ordered é um map YAML que preserva a ordem das chaves do spec, para que o
+// código gerado siga a mesma ordem e seja estável entre execuções.
+type ordered[V any] struct {
+	Keys []string
+	M    map[string]V
+}
+
+func (o *ordered[V]) UnmarshalYAML(n *yaml.Node) error {
+	if n.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: expected mapping", n.Line)
+	}
+	o.M = map[string]V{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		var v V
+		if err := n.Content[i+1].Decode(&v); err != nil {
+			return err
+		}
+		k := n.Content[i].Value
+		o.Keys = append(o.Keys, k)
+		o.M[k] = v
+	}
+	return nil
+}
+
+func loadSpec(path string) (*spec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s spec
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &s, nil
+}
+
+func (s *spec) resolve(sc *schema) *schema {
+	for i := 0; sc != nil && sc.Ref != "" && i < 16; i++ {
+		sc = s.Components.Schemas.M[sc.refName()]
+	}
+	return sc
+}
+
+// declaredTypes lista os tipos declarados à mão no diretório do pacote,
+// ignorando o próprio arquivo gerado e os testes.
+func declaredTypes(dir, generated string) (map[string]bool, error) {
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]bool{}
+	fset := token.NewFileSet()
+	for _, m := range matches {
+		if filepath.Base(m) == generated || strings.HasSuffix(m, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, m, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range f.Decls {
+			gd, ok := d.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, sp := range gd.Specs {
+				out[sp.(*ast.TypeSpec).Name.Name] = true
+			}
+		}
+	}
+	return out, nil
+}
@@ -2,9 +2,18 @@ module github.com/drksbr/gowa-client
 
 go 1.24.0
 
-require github.com/hashicorp/go-retryablehttp v0.7.7
+require (
+	github.com/hashicorp/go-retryablehttp v0.7.7
+	go.opentelemetry.io/otel v1.38.0
+	go.opentelemetry.io/otel/metric v1.38.0
+	go.opentelemetry.io/otel/trace v1.38.0
+	gopkg.in/yaml.v3 v3.0.1
+)
 
 require (
+	github.com/go-logr/logr v1.4.3 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
 	github.com/hashicorp/go-cleanhttp v0.5.2 // indirect
+	go.opentelemetry.io/auto/sdk v1.1.0 // indirect
 	golang.org/x/sys v0.36.0 // indirect
 )
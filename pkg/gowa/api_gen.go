@@ -0,0 +1,1246 @@
+// Code generated by gowagen from openapi.yaml. DO NOT EDIT.
+
+package gowa
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// AppLoginWithCodeRequest são os parâmetros de GET /app/login-with-code.
+type AppLoginWithCodeRequest struct {
+	Phone string `json:"-"` // query; Your phone number
+}
+
+// UserInfoRequest são os parâmetros de GET /user/info.
+type UserInfoRequest struct {
+	Phone string `json:"-"` // query; Phone number with country code
+}
+
+// UserAvatarRequest são os parâmetros de GET /user/avatar.
+type UserAvatarRequest struct {
+	Phone       string `json:"-"` // query; Phone number with country code
+	IsPreview   *bool  `json:"-"` // query; Whether to fetch a preview of the avatar
+	IsCommunity *bool  `json:"-"` // query; Whether to fetch a community avatar
+}
+
+// UserChangeAvatarRequest são os parâmetros de POST /user/avatar.
+type UserChangeAvatarRequest struct {
+	Avatar string `json:"-"` // file; caminho do arquivo local
+}
+
+// UserChangePushNameRequest são os parâmetros de POST /user/pushname.
+type UserChangePushNameRequest struct {
+	PushName string `json:"push_name"` // The new display name to set
+}
+
+// UserCheckRequest são os parâmetros de GET /user/check.
+type UserCheckRequest struct {
+	Phone string `json:"-"` // query; Phone number with country code
+}
+
+// UserBusinessProfileRequest são os parâmetros de GET /user/business-profile.
+type UserBusinessProfileRequest struct {
+	Phone string `json:"-"` // query; Phone number with country code of the business account
+}
+
+// SendMessageRequest são os parâmetros de POST /send/message.
+type SendMessageRequest struct {
+	Phone          string `json:"phone,omitempty"`            // Phone number with country code
+	Message        string `json:"message,omitempty"`          // Message to send
+	ReplyMessageID string `json:"reply_message_id,omitempty"` // Message ID that you want reply
+	IsForwarded    bool   `json:"is_forwarded,omitempty"`     // Whether this is a forwarded message
+	Duration       int    `json:"duration,omitempty"`         // Disappearing message duration in seconds (optional)
+}
+
+// SendImageRequest são os parâmetros de POST /send/image.
+type SendImageRequest struct {
+	Phone       string `json:"phone,omitempty"`        // Phone number with country code
+	Caption     string `json:"caption,omitempty"`      // Caption to send
+	ViewOnce    bool   `json:"view_once,omitempty"`    // View once
+	Image       string `json:"-"`                      // file; caminho do arquivo local
+	ImageURL    string `json:"image_url,omitempty"`    // Image URL to send
+	Compress    bool   `json:"compress,omitempty"`     // Compress image
+	Duration    int    `json:"duration,omitempty"`     // Disappearing message duration in seconds (optional)
+	IsForwarded bool   `json:"is_forwarded,omitempty"` // Whether this is a forwarded message
+}
+
+// SendAudioRequest são os parâmetros de POST /send/audio.
+type SendAudioRequest struct {
+	Phone       string `json:"phone,omitempty"`        // Phone number with country code
+	Audio       string `json:"-"`                      // file; caminho do arquivo local
+	AudioURL    string `json:"audio_url,omitempty"`    // Audio URL to send
+	IsForwarded bool   `json:"is_forwarded,omitempty"` // Whether this is a forwarded message
+	Duration    int    `json:"duration,omitempty"`     // Disappearing message duration in seconds (optional)
+}
+
+// SendFileRequest são os parâmetros de POST /send/file.
+type SendFileRequest struct {
+	Phone       string `json:"phone,omitempty"`        // Phone number with country code
+	Caption     string `json:"caption,omitempty"`      // Caption to send
+	File        string `json:"-"`                      // file; caminho do arquivo local
+	IsForwarded bool   `json:"is_forwarded,omitempty"` // Whether this is a forwarded message
+	Duration    int    `json:"duration,omitempty"`     // Disappearing message duration in seconds (optional)
+}
+
+// SendVideoRequest são os parâmetros de POST /send/video.
+type SendVideoRequest struct {
+	Phone       string `json:"phone,omitempty"`        // Phone number with country code
+	Caption     string `json:"caption,omitempty"`      // Caption to send
+	ViewOnce    bool   `json:"view_once,omitempty"`    // View once
+	Video       string `json:"-"`                      // file; caminho do arquivo local
+	VideoURL    string `json:"video_url,omitempty"`    // Video URL to send
+	Compress    bool   `json:"compress,omitempty"`     // Compress video
+	Duration    int    `json:"duration,omitempty"`     // Disappearing message duration in seconds (optional)
+	IsForwarded bool   `json:"is_forwarded,omitempty"` // Whether this is a forwarded message
+}
+
+// SendContactRequest são os parâmetros de POST /send/contact.
+type SendContactRequest struct {
+	Phone        string `json:"phone,omitempty"`         // Phone number with country code
+	ContactName  string `json:"contact_name,omitempty"`  // Contact name
+	ContactPhone string `json:"contact_phone,omitempty"` // Contact phone number
+	IsForwarded  bool   `json:"is_forwarded,omitempty"`  // Whether this is a forwarded message
+	Duration     int    `json:"duration,omitempty"`      // Disappearing message duration in seconds (optional)
+}
+
+// SendLinkRequest são os parâmetros de POST /send/link.
+type SendLinkRequest struct {
+	Phone       string `json:"phone,omitempty"`        // Phone number with country code
+	Link        string `json:"link,omitempty"`         // Link to send
+	Caption     string `json:"caption,omitempty"`      // Caption to send
+	IsForwarded bool   `json:"is_forwarded,omitempty"` // Whether this is a forwarded message
+	Duration    int    `json:"duration,omitempty"`     // Disappearing message duration in seconds (optional)
+}
+
+// SendLocationRequest são os parâmetros de POST /send/location.
+type SendLocationRequest struct {
+	Phone       string `json:"phone,omitempty"`        // Phone number with country code
+	Latitude    string `json:"latitude,omitempty"`     // Latitude coordinate
+	Longitude   string `json:"longitude,omitempty"`    // Longitude coordinate
+	IsForwarded bool   `json:"is_forwarded,omitempty"` // Whether this is a forwarded message
+	Duration    int    `json:"duration,omitempty"`     // Disappearing message duration in seconds (optional)
+}
+
+// SendPollRequest são os parâmetros de POST /send/poll.
+type SendPollRequest struct {
+	Phone     string   `json:"phone"`              // The WhatsApp phone number to send the poll to, including the '@s.whatsapp.net' suffix.
+	Question  string   `json:"question"`           // The question for the poll.
+	Options   []string `json:"options"`            // The options for the poll.
+	MaxAnswer int      `json:"max_answer"`         // The maximum number of answers allowed for the poll.
+	Duration  int      `json:"duration,omitempty"` // Disappearing message duration in seconds (optional)
+}
+
+// SendPresenceRequest são os parâmetros de POST /send/presence.
+type SendPresenceRequest struct {
+	Type        string `json:"type"`                   // The presence type to send
+	IsForwarded bool   `json:"is_forwarded,omitempty"` // Whether this is a forwarded message
+}
+
+// SendChatPresenceRequest são os parâmetros de POST /send/chat-presence.
+type SendChatPresenceRequest struct {
+	Phone  string `json:"phone"`  // Phone number with country code
+	Action string `json:"action"` // Action to perform - "start" to begin typing indicator, "stop" to end typing indicator
+}
+
+// RevokeMessageRequest são os parâmetros de POST /message/{message_id}/revoke.
+type RevokeMessageRequest struct {
+	MessageID string `json:"-"`               // path; Message ID
+	Phone     string `json:"phone,omitempty"` // Phone number with country code
+}
+
+// DeleteMessageRequest são os parâmetros de POST /message/{message_id}/delete.
+type DeleteMessageRequest struct {
+	MessageID string `json:"-"`               // path; Message ID
+	Phone     string `json:"phone,omitempty"` // Phone number with country code
+}
+
+// ReactMessageRequest são os parâmetros de POST /message/{message_id}/reaction.
+type ReactMessageRequest struct {
+	MessageID string `json:"-"`               // path; Message ID
+	Phone     string `json:"phone,omitempty"` // Phone number with country code
+	Emoji     string `json:"emoji,omitempty"` // Emoji to react
+}
+
+// UpdateMessageRequest são os parâmetros de POST /message/{message_id}/update.
+type UpdateMessageRequest struct {
+	MessageID string `json:"-"`       // path; Message ID
+	Phone     string `json:"phone"`   // Phone number with country code
+	Message   string `json:"message"` // New message to send
+}
+
+// ReadMessageRequest são os parâmetros de POST /message/{message_id}/read.
+type ReadMessageRequest struct {
+	MessageID string `json:"-"`     // path; Message ID
+	Phone     string `json:"phone"` // Phone number with country code
+}
+
+// StarMessageRequest são os parâmetros de POST /message/{message_id}/star.
+type StarMessageRequest struct {
+	MessageID string `json:"-"`     // path; Message ID
+	Phone     string `json:"phone"` // Phone number with country code
+}
+
+// UnstarMessageRequest são os parâmetros de POST /message/{message_id}/unstar.
+type UnstarMessageRequest struct {
+	MessageID string `json:"-"`     // path; Message ID
+	Phone     string `json:"phone"` // Phone number with country code
+}
+
+// ListChatsRequest são os parâmetros de GET /chats.
+type ListChatsRequest struct {
+	Limit    int    `json:"-"` // query; Maximum number of chats to return
+	Offset   int    `json:"-"` // query; Number of chats to skip (for pagination)
+	Search   string `json:"-"` // query; Search chats by name
+	HasMedia *bool  `json:"-"` // query; Filter chats that contain media messages
+}
+
+// GetChatMessagesRequest são os parâmetros de GET /chat/{chat_jid}/messages.
+type GetChatMessagesRequest struct {
+	ChatJID   string `json:"-"` // path; Chat JID (e.g., phone@s.whatsapp.net for individual or groupid@g.us for group)
+	Limit     int    `json:"-"` // query; Maximum number of messages to return
+	Offset    int    `json:"-"` // query; Number of messages to skip (for pagination)
+	StartTime string `json:"-"` // query; Filter messages from this timestamp (ISO 8601 format)
+	EndTime   string `json:"-"` // query; Filter messages until this timestamp (ISO 8601 format)
+	MediaOnly *bool  `json:"-"` // query; Only return messages with media content
+	IsFromMe  *bool  `json:"-"` // query; Filter messages by sender (true for messages sent by you, false for received messages). When both media_only=true and isFromMe=false are provided, media_only takes precedence and will return all media messages regardless of sender.
+	Search    string `json:"-"` // query; Search messages by content text
+}
+
+// LabelChatRequest são os parâmetros de POST /chat/{chat_jid}/label.
+type LabelChatRequest struct {
+	ChatJID   string `json:"-"`          // path; Chat JID (e.g., phone@s.whatsapp.net for individual or groupid@g.us for group)
+	LabelID   string `json:"label_id"`   // Unique identifier for the label
+	LabelName string `json:"label_name"` // Display name for the label
+	Labeled   bool   `json:"labeled"`    // Whether to apply (true) or remove (false) the label
+}
+
+// PinChatRequest são os parâmetros de POST /chat/{chat_jid}/pin.
+type PinChatRequest struct {
+	ChatJID string `json:"-"`      // path; Chat JID (e.g., phone@s.whatsapp.net for individual or groupid@g.us for group)
+	Pinned  bool   `json:"pinned"` // Whether to pin (true) or unpin (false) the chat
+}
+
+// GroupInfoRequest são os parâmetros de GET /group/info.
+type GroupInfoRequest struct {
+	GroupID string `json:"-"` // query; WhatsApp Group ID
+}
+
+// CreateGroupRequest são os parâmetros de POST /group.
+type CreateGroupRequest struct {
+	Title        string   `json:"title,omitempty"`
+	Participants []string `json:"participants,omitempty"`
+}
+
+// AddParticipantToGroupRequest são os parâmetros de POST /group/participants.
+type AddParticipantToGroupRequest struct {
+	GroupID      string   `json:"group_id,omitempty"`
+	Participants []string `json:"participants,omitempty"`
+}
+
+// RemoveParticipantFromGroupRequest são os parâmetros de POST /group/participants/remove.
+type RemoveParticipantFromGroupRequest struct {
+	GroupID      string   `json:"group_id,omitempty"`
+	Participants []string `json:"participants,omitempty"`
+}
+
+// PromoteParticipantToAdminRequest são os parâmetros de POST /group/participants/promote.
+type PromoteParticipantToAdminRequest struct {
+	GroupID      string   `json:"group_id,omitempty"`
+	Participants []string `json:"participants,omitempty"`
+}
+
+// DemoteParticipantToMemberRequest são os parâmetros de POST /group/participants/demote.
+type DemoteParticipantToMemberRequest struct {
+	GroupID      string   `json:"group_id,omitempty"`
+	Participants []string `json:"participants,omitempty"`
+}
+
+// JoinGroupWithLinkRequest são os parâmetros de POST /group/join-with-link.
+type JoinGroupWithLinkRequest struct {
+	Link string `json:"link,omitempty"`
+}
+
+// GetGroupInfoFromLinkRequest são os parâmetros de GET /group/info-from-link.
+type GetGroupInfoFromLinkRequest struct {
+	Link string `json:"-"` // query; WhatsApp group invitation link
+}
+
+// GetGroupParticipantRequestsRequest são os parâmetros de GET /group/participant-requests.
+type GetGroupParticipantRequestsRequest struct {
+	GroupID string `json:"-"` // query; The group ID to get participant requests for
+}
+
+// ApproveGroupParticipantRequest são os parâmetros de POST /group/participant-requests/approve.
+type ApproveGroupParticipantRequest struct {
+	GroupID      string   `json:"group_id"`     // The group ID
+	Participants []string `json:"participants"` // Array of participant WhatsApp IDs to approve
+}
+
+// RejectGroupParticipantRequest são os parâmetros de POST /group/participant-requests/reject.
+type RejectGroupParticipantRequest struct {
+	GroupID      string   `json:"group_id"`     // The group ID
+	Participants []string `json:"participants"` // Array of participant WhatsApp IDs to reject
+}
+
+// LeaveGroupRequest são os parâmetros de POST /group/leave.
+type LeaveGroupRequest struct {
+	GroupID string `json:"group_id,omitempty"`
+}
+
+// SetGroupPhotoRequest são os parâmetros de POST /group/photo.
+type SetGroupPhotoRequest struct {
+	GroupID string `json:"group_id"` // The group ID
+	Photo   string `json:"-"`        // file; caminho do arquivo local
+}
+
+// SetGroupNameRequest são os parâmetros de POST /group/name.
+type SetGroupNameRequest struct {
+	GroupID string `json:"group_id"` // The group ID
+	Name    string `json:"name"`     // The new group name (max 25 characters)
+}
+
+// SetGroupLockedRequest são os parâmetros de POST /group/locked.
+type SetGroupLockedRequest struct {
+	GroupID string `json:"group_id"` // The group ID
+	Locked  bool   `json:"locked"`   // Whether to lock the group (true) or unlock it (false)
+}
+
+// SetGroupAnnounceRequest são os parâmetros de POST /group/announce.
+type SetGroupAnnounceRequest struct {
+	GroupID  string `json:"group_id"` // The group ID
+	Announce bool   `json:"announce"` // Whether to enable announce mode (true) or disable it (false)
+}
+
+// SetGroupTopicRequest são os parâmetros de POST /group/topic.
+type SetGroupTopicRequest struct {
+	GroupID string `json:"group_id"`        // The group ID
+	Topic   string `json:"topic,omitempty"` // The group topic/description. Leave empty to remove the topic.
+}
+
+// GroupInviteLinkRequest são os parâmetros de GET /group/invite-link.
+type GroupInviteLinkRequest struct {
+	GroupID string `json:"-"` // query; WhatsApp Group ID
+	Reset   *bool  `json:"-"` // query; Reset existing invite link
+}
+
+// UnfollowNewsletterRequest são os parâmetros de POST /newsletter/unfollow.
+type UnfollowNewsletterRequest struct {
+	NewsletterID string `json:"newsletter_id,omitempty"`
+}
+
+type UserAvatarResult struct {
+	URL  string `json:"url"`
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+type UserAvatarResponse = Response[UserAvatarResult]
+
+type UserPrivacyResult struct {
+	GroupAdd     string `json:"group_add"`
+	LastSeen     string `json:"last_seen"`
+	Status       string `json:"status"`
+	Profile      string `json:"profile"`
+	ReadReceipts string `json:"read_receipts"`
+}
+
+type UserPrivacyResponse = Response[UserPrivacyResult]
+
+type UserGroupResult struct {
+	Data []struct {
+		JID                  string `json:"JID"`
+		OwnerJID             string `json:"OwnerJID"`
+		Name                 string `json:"Name"`
+		NameSetAt            string `json:"NameSetAt"`
+		NameSetBy            string `json:"NameSetBy"`
+		GroupCreated         string `json:"GroupCreated"`
+		ParticipantVersionID string `json:"ParticipantVersionID"`
+		Participants         []struct {
+			JID          string  `json:"JID"`
+			IsAdmin      bool    `json:"IsAdmin"`
+			IsSuperAdmin bool    `json:"IsSuperAdmin"`
+			Error        float64 `json:"Error"`
+		} `json:"Participants"`
+	} `json:"data"`
+}
+
+type UserGroupResponse = Response[UserGroupResult]
+
+type NewsletterResult struct {
+	Data []Newsletter `json:"data"`
+}
+
+type NewsletterResponse = Response[NewsletterResult]
+
+type MyListContactsResult struct {
+	Data []MyListContacts `json:"data"`
+}
+
+type MyListContactsResponse = Response[MyListContactsResult]
+
+type UserCheckResult struct {
+	IsOnWhatsapp bool `json:"is_on_whatsapp"`
+}
+
+type UserCheckResponse = Response[UserCheckResult]
+
+type BusinessProfileResult struct {
+	JID        string `json:"jid"`     // Business account JID
+	Email      string `json:"email"`   // Business email address
+	Address    string `json:"address"` // Business physical address
+	Categories []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"categories"` // Business categories
+	ProfileOptions        any    `json:"profile_options"`         // Additional profile options
+	BusinessHoursTimezone string `json:"business_hours_timezone"` // Business hours timezone
+	BusinessHours         []struct {
+		DayOfWeek string `json:"day_of_week"`
+		Mode      string `json:"mode"`
+		OpenTime  string `json:"open_time"`
+		CloseTime string `json:"close_time"`
+	} `json:"business_hours"` // Business operating hours
+}
+
+type BusinessProfileResponse = Response[BusinessProfileResult]
+
+type LabelChatResult struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	ChatJID string `json:"chat_jid"`
+	LabelID string `json:"label_id"`
+	Labeled bool   `json:"labeled"`
+}
+
+type LabelChatResponse = Response[LabelChatResult]
+
+type PinChatResult struct {
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	ChatJID string `json:"chat_jid"`
+	Pinned  bool   `json:"pinned"`
+}
+
+type PinChatResponse = Response[PinChatResult]
+
+type GroupInfoResponse struct {
+	Response[any]
+	Status int `json:"status"`
+}
+
+type CreateGroupResult struct {
+	GroupID string `json:"group_id"`
+}
+
+type CreateGroupResponse = Response[CreateGroupResult]
+
+type ManageParticipantResult struct {
+	Participant string `json:"participant"`
+	Status      string `json:"status"`
+	Message     string `json:"message"`
+}
+
+type ManageParticipantResponse = Response[[]ManageParticipantResult]
+
+type GroupInfoFromLinkResult struct {
+	GroupID          string `json:"group_id"`          // The group ID
+	Name             string `json:"name"`              // The group name
+	Topic            string `json:"topic"`             // The group topic/description
+	CreatedAt        string `json:"created_at"`        // When the group was created
+	ParticipantCount int    `json:"participant_count"` // Number of participants in the group
+	IsLocked         bool   `json:"is_locked"`         // Whether the group is locked (only admins can modify group info)
+	IsAnnounce       bool   `json:"is_announce"`       // Whether the group is in announce mode (only admins can send messages)
+	IsEphemeral      bool   `json:"is_ephemeral"`      // Whether the group has disappearing messages enabled
+	Description      string `json:"description"`       // Additional description of the group
+}
+
+type GroupInfoFromLinkResponse = Response[GroupInfoFromLinkResult]
+
+type GroupParticipantRequestListResult struct {
+	Data []struct {
+		JID         string `json:"jid"`
+		RequestedAt string `json:"requested_at"`
+	} `json:"data"`
+}
+
+type GroupParticipantRequestListResponse = Response[GroupParticipantRequestListResult]
+
+type SetGroupPhotoResult struct {
+	PictureID string `json:"picture_id"` // The ID of the uploaded picture, or 'remove' if photo was removed
+	Message   string `json:"message"`
+}
+
+type SetGroupPhotoResponse = Response[SetGroupPhotoResult]
+
+type GetGroupInviteLinkResult struct {
+	InviteLink string `json:"invite_link"` // The group invite link
+	GroupID    string `json:"group_id"`    // The group ID
+}
+
+type GetGroupInviteLinkResponse = Response[GetGroupInviteLinkResult]
+
+type Newsletter struct {
+	ID    string `json:"id"`
+	State struct {
+		Type string `json:"type"`
+	} `json:"state"`
+	ThreadMetadata struct {
+		CreationTime string `json:"creation_time"`
+		Invite       string `json:"invite"`
+		Name         struct {
+			Text       string `json:"text"`
+			ID         string `json:"id"`
+			UpdateTime string `json:"update_time"`
+		} `json:"name"`
+		Description struct {
+			Text       string `json:"text"`
+			ID         string `json:"id"`
+			UpdateTime string `json:"update_time"`
+		} `json:"description"`
+		SubscribersCount string `json:"subscribers_count"`
+		Verification     string `json:"verification"`
+		Picture          struct {
+			URL        string `json:"url"`
+			ID         string `json:"id"`
+			Type       string `json:"type"`
+			DirectPath string `json:"direct_path"`
+		} `json:"picture"`
+		Preview struct {
+			URL        string `json:"url"`
+			ID         string `json:"id"`
+			Type       string `json:"type"`
+			DirectPath string `json:"direct_path"`
+		} `json:"preview"`
+		Settings struct {
+			ReactionCodes struct {
+				Value string `json:"value"`
+			} `json:"reaction_codes"`
+		} `json:"settings"`
+	} `json:"thread_metadata"`
+	ViewerMetadata struct {
+		Mute string `json:"mute"`
+		Role string `json:"role"`
+	} `json:"viewer_metadata"`
+}
+
+type MyListContacts struct {
+	JID  string `json:"jid"`
+	Name string `json:"name"`
+}
+
+// AppLogin chama GET /app/login: Login to whatsapp server
+func (a *RawAPI) AppLogin(ctx context.Context) (*LoginResponse, error) {
+	p := "/app/login"
+	var out LoginResponse
+	if err := a.c.getJSON(ctx, "appLogin", "/app/login", p, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AppLoginWithCode chama GET /app/login-with-code: Login with pairing code
+func (a *RawAPI) AppLoginWithCode(ctx context.Context, req AppLoginWithCodeRequest) (*LoginWithCodeResponse, error) {
+	p := "/app/login-with-code"
+	q := url.Values{}
+	if req.Phone != "" {
+		q.Set("phone", req.Phone)
+	}
+	var out LoginWithCodeResponse
+	if err := a.c.getJSON(ctx, "appLoginWithCode", "/app/login-with-code", p, q, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AppLogout chama GET /app/logout: Remove database and logout
+func (a *RawAPI) AppLogout(ctx context.Context) (*GenericResponse, error) {
+	p := "/app/logout"
+	var out GenericResponse
+	if err := a.c.getJSON(ctx, "appLogout", "/app/logout", p, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AppReconnect chama GET /app/reconnect: Reconnecting to whatsapp server
+func (a *RawAPI) AppReconnect(ctx context.Context) (*GenericResponse, error) {
+	p := "/app/reconnect"
+	var out GenericResponse
+	if err := a.c.getJSON(ctx, "appReconnect", "/app/reconnect", p, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AppDevices chama GET /app/devices: Get list connected devices
+func (a *RawAPI) AppDevices(ctx context.Context) (*DeviceResponse, error) {
+	p := "/app/devices"
+	var out DeviceResponse
+	if err := a.c.getJSON(ctx, "appDevices", "/app/devices", p, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserInfo chama GET /user/info: User Info
+func (a *RawAPI) UserInfo(ctx context.Context, req UserInfoRequest) (*UserInfoResponse, error) {
+	p := "/user/info"
+	q := url.Values{}
+	if req.Phone != "" {
+		q.Set("phone", req.Phone)
+	}
+	var out UserInfoResponse
+	if err := a.c.getJSON(ctx, "userInfo", "/user/info", p, q, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserAvatar chama GET /user/avatar: User Avatar
+func (a *RawAPI) UserAvatar(ctx context.Context, req UserAvatarRequest) (*UserAvatarResponse, error) {
+	p := "/user/avatar"
+	q := url.Values{}
+	if req.Phone != "" {
+		q.Set("phone", req.Phone)
+	}
+	if req.IsPreview != nil {
+		q.Set("is_preview", strconv.FormatBool(*req.IsPreview))
+	}
+	if req.IsCommunity != nil {
+		q.Set("is_community", strconv.FormatBool(*req.IsCommunity))
+	}
+	var out UserAvatarResponse
+	if err := a.c.getJSON(ctx, "userAvatar", "/user/avatar", p, q, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserChangeAvatar chama POST /user/avatar: User Change Avatar
+func (a *RawAPI) UserChangeAvatar(ctx context.Context, req UserChangeAvatarRequest) (*GenericResponse, error) {
+	p := "/user/avatar"
+	var out GenericResponse
+	var fields map[string]string
+	if err := a.c.postFormFile(ctx, "userChangeAvatar", "/user/avatar", p, req, fields, "avatar", req.Avatar, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserChangePushName chama POST /user/pushname: User Change Push Name
+func (a *RawAPI) UserChangePushName(ctx context.Context, req UserChangePushNameRequest) (*GenericResponse, error) {
+	p := "/user/pushname"
+	var out GenericResponse
+	if err := a.c.postJSON(ctx, "userChangePushName", "/user/pushname", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserMyPrivacy chama GET /user/my/privacy: User My Privacy Setting
+func (a *RawAPI) UserMyPrivacy(ctx context.Context) (*UserPrivacyResponse, error) {
+	p := "/user/my/privacy"
+	var out UserPrivacyResponse
+	if err := a.c.getJSON(ctx, "userMyPrivacy", "/user/my/privacy", p, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserMyGroups chama GET /user/my/groups: User My List Groups
+func (a *RawAPI) UserMyGroups(ctx context.Context) (*UserGroupResponse, error) {
+	p := "/user/my/groups"
+	var out UserGroupResponse
+	if err := a.c.getJSON(ctx, "userMyGroups", "/user/my/groups", p, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserMyNewsletter chama GET /user/my/newsletters: User My List Groups
+func (a *RawAPI) UserMyNewsletter(ctx context.Context) (*NewsletterResponse, error) {
+	p := "/user/my/newsletters"
+	var out NewsletterResponse
+	if err := a.c.getJSON(ctx, "userMyNewsletter", "/user/my/newsletters", p, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserMyContacts chama GET /user/my/contacts: Get list of user contacts
+func (a *RawAPI) UserMyContacts(ctx context.Context) (*MyListContactsResponse, error) {
+	p := "/user/my/contacts"
+	var out MyListContactsResponse
+	if err := a.c.getJSON(ctx, "userMyContacts", "/user/my/contacts", p, nil, nil, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserCheck chama GET /user/check: Check if user is on WhatsApp
+func (a *RawAPI) UserCheck(ctx context.Context, req UserCheckRequest) (*UserCheckResponse, error) {
+	p := "/user/check"
+	q := url.Values{}
+	if req.Phone != "" {
+		q.Set("phone", req.Phone)
+	}
+	var out UserCheckResponse
+	if err := a.c.getJSON(ctx, "userCheck", "/user/check", p, q, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UserBusinessProfile chama GET /user/business-profile: Get Business Profile Information
+func (a *RawAPI) UserBusinessProfile(ctx context.Context, req UserBusinessProfileRequest) (*BusinessProfileResponse, error) {
+	p := "/user/business-profile"
+	q := url.Values{}
+	if req.Phone != "" {
+		q.Set("phone", req.Phone)
+	}
+	var out BusinessProfileResponse
+	if err := a.c.getJSON(ctx, "userBusinessProfile", "/user/business-profile", p, q, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SendMessage chama POST /send/message: Send Message
+func (a *RawAPI) SendMessage(ctx context.Context, req SendMessageRequest) (*SendResponse, error) {
+	p := "/send/message"
+	var out SendResponse
+	if err := a.c.postJSON(ctx, "sendMessage", "/send/message", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SendImage chama POST /send/image: Send Image
+func (a *RawAPI) SendImage(ctx context.Context, req SendImageRequest) (*SendResponse, error) {
+	p := "/send/image"
+	var out SendResponse
+	fields := map[string]string{}
+	if req.Phone != "" {
+		fields["phone"] = req.Phone
+	}
+	if req.Caption != "" {
+		fields["caption"] = req.Caption
+	}
+	if req.ViewOnce {
+		fields["view_once"] = "true"
+	}
+	if req.ImageURL != "" {
+		fields["image_url"] = req.ImageURL
+	}
+	if req.Compress {
+		fields["compress"] = "true"
+	}
+	if req.Duration != 0 {
+		fields["duration"] = strconv.Itoa(req.Duration)
+	}
+	if req.IsForwarded {
+		fields["is_forwarded"] = "true"
+	}
+	if err := a.c.postFormFile(ctx, "sendImage", "/send/image", p, req, fields, "image", req.Image, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SendAudio chama POST /send/audio: Send Audio
+func (a *RawAPI) SendAudio(ctx context.Context, req SendAudioRequest) (*SendResponse, error) {
+	p := "/send/audio"
+	var out SendResponse
+	fields := map[string]string{}
+	if req.Phone != "" {
+		fields["phone"] = req.Phone
+	}
+	if req.AudioURL != "" {
+		fields["audio_url"] = req.AudioURL
+	}
+	if req.IsForwarded {
+		fields["is_forwarded"] = "true"
+	}
+	if req.Duration != 0 {
+		fields["duration"] = strconv.Itoa(req.Duration)
+	}
+	if err := a.c.postFormFile(ctx, "sendAudio", "/send/audio", p, req, fields, "audio", req.Audio, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SendFile chama POST /send/file: Send File
+func (a *RawAPI) SendFile(ctx context.Context, req SendFileRequest) (*SendResponse, error) {
+	p := "/send/file"
+	var out SendResponse
+	fields := map[string]string{}
+	if req.Phone != "" {
+		fields["phone"] = req.Phone
+	}
+	if req.Caption != "" {
+		fields["caption"] = req.Caption
+	}
+	if req.IsForwarded {
+		fields["is_forwarded"] = "true"
+	}
+	if req.Duration != 0 {
+		fields["duration"] = strconv.Itoa(req.Duration)
+	}
+	if err := a.c.postFormFile(ctx, "sendFile", "/send/file", p, req, fields, "file", req.File, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SendVideo chama POST /send/video: Send Video
+func (a *RawAPI) SendVideo(ctx context.Context, req SendVideoRequest) (*SendResponse, error) {
+	p := "/send/video"
+	var out SendResponse
+	fields := map[string]string{}
+	if req.Phone != "" {
+		fields["phone"] = req.Phone
+	}
+	if req.Caption != "" {
+		fields["caption"] = req.Caption
+	}
+	if req.ViewOnce {
+		fields["view_once"] = "true"
+	}
+	if req.VideoURL != "" {
+		fields["video_url"] = req.VideoURL
+	}
+	if req.Compress {
+		fields["compress"] = "true"
+	}
+	if req.Duration != 0 {
+		fields["duration"] = strconv.Itoa(req.Duration)
+	}
+	if req.IsForwarded {
+		fields["is_forwarded"] = "true"
+	}
+	if err := a.c.postFormFile(ctx, "sendVideo", "/send/video", p, req, fields, "video", req.Video, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SendContact chama POST /send/contact: Send Contact
+func (a *RawAPI) SendContact(ctx context.Context, req SendContactRequest) (*SendResponse, error) {
+	p := "/send/contact"
+	var out SendResponse
+	if err := a.c.postJSON(ctx, "sendContact", "/send/contact", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SendLink chama POST /send/link: Send Link
+func (a *RawAPI) SendLink(ctx context.Context, req SendLinkRequest) (*SendResponse, error) {
+	p := "/send/link"
+	var out SendResponse
+	if err := a.c.postJSON(ctx, "sendLink", "/send/link", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SendLocation chama POST /send/location: Send Location
+func (a *RawAPI) SendLocation(ctx context.Context, req SendLocationRequest) (*SendResponse, error) {
+	p := "/send/location"
+	var out SendResponse
+	if err := a.c.postJSON(ctx, "sendLocation", "/send/location", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SendPoll chama POST /send/poll: Send Poll / Vote
+func (a *RawAPI) SendPoll(ctx context.Context, req SendPollRequest) (*SendResponse, error) {
+	p := "/send/poll"
+	var out SendResponse
+	if err := a.c.postJSON(ctx, "sendPoll", "/send/poll", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SendPresence chama POST /send/presence: Send presence status
+func (a *RawAPI) SendPresence(ctx context.Context, req SendPresenceRequest) (*SendResponse, error) {
+	p := "/send/presence"
+	var out SendResponse
+	if err := a.c.postJSON(ctx, "sendPresence", "/send/presence", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SendChatPresence chama POST /send/chat-presence: Send chat presence (typing indicator)
+func (a *RawAPI) SendChatPresence(ctx context.Context, req SendChatPresenceRequest) (*SendResponse, error) {
+	p := "/send/chat-presence"
+	var out SendResponse
+	if err := a.c.postJSON(ctx, "sendChatPresence", "/send/chat-presence", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RevokeMessage chama POST /message/{message_id}/revoke: Revoke Message
+func (a *RawAPI) RevokeMessage(ctx context.Context, req RevokeMessageRequest) (*SendResponse, error) {
+	p := "/message/" + url.PathEscape(req.MessageID) + "/revoke"
+	var out SendResponse
+	if err := a.c.postJSON(ctx, "revokeMessage", "/message/{message_id}/revoke", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DeleteMessage chama POST /message/{message_id}/delete: Delete Message
+func (a *RawAPI) DeleteMessage(ctx context.Context, req DeleteMessageRequest) (*SendResponse, error) {
+	p := "/message/" + url.PathEscape(req.MessageID) + "/delete"
+	var out SendResponse
+	if err := a.c.postJSON(ctx, "deleteMessage", "/message/{message_id}/delete", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReactMessage chama POST /message/{message_id}/reaction: Send reaction to message
+func (a *RawAPI) ReactMessage(ctx context.Context, req ReactMessageRequest) (*SendResponse, error) {
+	p := "/message/" + url.PathEscape(req.MessageID) + "/reaction"
+	var out SendResponse
+	if err := a.c.postJSON(ctx, "reactMessage", "/message/{message_id}/reaction", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UpdateMessage chama POST /message/{message_id}/update: Edit message by message ID before 15 minutes
+func (a *RawAPI) UpdateMessage(ctx context.Context, req UpdateMessageRequest) (*SendResponse, error) {
+	p := "/message/" + url.PathEscape(req.MessageID) + "/update"
+	var out SendResponse
+	if err := a.c.postJSON(ctx, "updateMessage", "/message/{message_id}/update", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ReadMessage chama POST /message/{message_id}/read: Mark as read message
+func (a *RawAPI) ReadMessage(ctx context.Context, req ReadMessageRequest) (*SendResponse, error) {
+	p := "/message/" + url.PathEscape(req.MessageID) + "/read"
+	var out SendResponse
+	if err := a.c.postJSON(ctx, "readMessage", "/message/{message_id}/read", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// StarMessage chama POST /message/{message_id}/star: Star message
+func (a *RawAPI) StarMessage(ctx context.Context, req StarMessageRequest) (*GenericResponse, error) {
+	p := "/message/" + url.PathEscape(req.MessageID) + "/star"
+	var out GenericResponse
+	if err := a.c.postJSON(ctx, "starMessage", "/message/{message_id}/star", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UnstarMessage chama POST /message/{message_id}/unstar: Unstar message
+func (a *RawAPI) UnstarMessage(ctx context.Context, req UnstarMessageRequest) (*GenericResponse, error) {
+	p := "/message/" + url.PathEscape(req.MessageID) + "/unstar"
+	var out GenericResponse
+	if err := a.c.postJSON(ctx, "unstarMessage", "/message/{message_id}/unstar", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ListChats chama GET /chats: Get list of chats
+func (a *RawAPI) ListChats(ctx context.Context, req ListChatsRequest) (*ChatListResponse, error) {
+	p := "/chats"
+	q := url.Values{}
+	if req.Limit != 0 {
+		q.Set("limit", strconv.Itoa(req.Limit))
+	}
+	if req.Offset != 0 {
+		q.Set("offset", strconv.Itoa(req.Offset))
+	}
+	if req.Search != "" {
+		q.Set("search", req.Search)
+	}
+	if req.HasMedia != nil {
+		q.Set("has_media", strconv.FormatBool(*req.HasMedia))
+	}
+	var out ChatListResponse
+	if err := a.c.getJSON(ctx, "listChats", "/chats", p, q, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetChatMessages chama GET /chat/{chat_jid}/messages: Get messages from a specific chat
+func (a *RawAPI) GetChatMessages(ctx context.Context, req GetChatMessagesRequest) (*ChatMessagesResponse, error) {
+	p := "/chat/" + url.PathEscape(req.ChatJID) + "/messages"
+	q := url.Values{}
+	if req.Limit != 0 {
+		q.Set("limit", strconv.Itoa(req.Limit))
+	}
+	if req.Offset != 0 {
+		q.Set("offset", strconv.Itoa(req.Offset))
+	}
+	if req.StartTime != "" {
+		q.Set("start_time", req.StartTime)
+	}
+	if req.EndTime != "" {
+		q.Set("end_time", req.EndTime)
+	}
+	if req.MediaOnly != nil {
+		q.Set("media_only", strconv.FormatBool(*req.MediaOnly))
+	}
+	if req.IsFromMe != nil {
+		q.Set("is_from_me", strconv.FormatBool(*req.IsFromMe))
+	}
+	if req.Search != "" {
+		q.Set("search", req.Search)
+	}
+	var out ChatMessagesResponse
+	if err := a.c.getJSON(ctx, "getChatMessages", "/chat/{chat_jid}/messages", p, q, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// LabelChat chama POST /chat/{chat_jid}/label: Label or unlabel a chat
+func (a *RawAPI) LabelChat(ctx context.Context, req LabelChatRequest) (*LabelChatResponse, error) {
+	p := "/chat/" + url.PathEscape(req.ChatJID) + "/label"
+	var out LabelChatResponse
+	if err := a.c.postJSON(ctx, "labelChat", "/chat/{chat_jid}/label", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PinChat chama POST /chat/{chat_jid}/pin: Pin or unpin a chat
+func (a *RawAPI) PinChat(ctx context.Context, req PinChatRequest) (*PinChatResponse, error) {
+	p := "/chat/" + url.PathEscape(req.ChatJID) + "/pin"
+	var out PinChatResponse
+	if err := a.c.postJSON(ctx, "pinChat", "/chat/{chat_jid}/pin", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GroupInfo chama GET /group/info: Group Info
+func (a *RawAPI) GroupInfo(ctx context.Context, req GroupInfoRequest) (*GroupInfoResponse, error) {
+	p := "/group/info"
+	q := url.Values{}
+	if req.GroupID != "" {
+		q.Set("group_id", req.GroupID)
+	}
+	var out GroupInfoResponse
+	if err := a.c.getJSON(ctx, "groupInfo", "/group/info", p, q, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// CreateGroup chama POST /group: Create group and add participant
+func (a *RawAPI) CreateGroup(ctx context.Context, req CreateGroupRequest) (*CreateGroupResponse, error) {
+	p := "/group"
+	var out CreateGroupResponse
+	if err := a.c.postJSON(ctx, "createGroup", "/group", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// AddParticipantToGroup chama POST /group/participants: Adding more participants to group
+func (a *RawAPI) AddParticipantToGroup(ctx context.Context, req AddParticipantToGroupRequest) (*ManageParticipantResponse, error) {
+	p := "/group/participants"
+	var out ManageParticipantResponse
+	if err := a.c.postJSON(ctx, "addParticipantToGroup", "/group/participants", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RemoveParticipantFromGroup chama POST /group/participants/remove: Remove participants from group
+func (a *RawAPI) RemoveParticipantFromGroup(ctx context.Context, req RemoveParticipantFromGroupRequest) (*ManageParticipantResponse, error) {
+	p := "/group/participants/remove"
+	var out ManageParticipantResponse
+	if err := a.c.postJSON(ctx, "removeParticipantFromGroup", "/group/participants/remove", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// PromoteParticipantToAdmin chama POST /group/participants/promote: Promote participants to admin
+func (a *RawAPI) PromoteParticipantToAdmin(ctx context.Context, req PromoteParticipantToAdminRequest) (*ManageParticipantResponse, error) {
+	p := "/group/participants/promote"
+	var out ManageParticipantResponse
+	if err := a.c.postJSON(ctx, "promoteParticipantToAdmin", "/group/participants/promote", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// DemoteParticipantToMember chama POST /group/participants/demote: Demote participants to member
+func (a *RawAPI) DemoteParticipantToMember(ctx context.Context, req DemoteParticipantToMemberRequest) (*ManageParticipantResponse, error) {
+	p := "/group/participants/demote"
+	var out ManageParticipantResponse
+	if err := a.c.postJSON(ctx, "demoteParticipantToMember", "/group/participants/demote", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// JoinGroupWithLink chama POST /group/join-with-link: Join group with link
+func (a *RawAPI) JoinGroupWithLink(ctx context.Context, req JoinGroupWithLinkRequest) (*GenericResponse, error) {
+	p := "/group/join-with-link"
+	var out GenericResponse
+	if err := a.c.postJSON(ctx, "joinGroupWithLink", "/group/join-with-link", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetGroupInfoFromLink chama GET /group/info-from-link: Get group information from invitation link
+func (a *RawAPI) GetGroupInfoFromLink(ctx context.Context, req GetGroupInfoFromLinkRequest) (*GroupInfoFromLinkResponse, error) {
+	p := "/group/info-from-link"
+	q := url.Values{}
+	if req.Link != "" {
+		q.Set("link", req.Link)
+	}
+	var out GroupInfoFromLinkResponse
+	if err := a.c.getJSON(ctx, "getGroupInfoFromLink", "/group/info-from-link", p, q, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GetGroupParticipantRequests chama GET /group/participant-requests: Get list of participant requests to join group
+func (a *RawAPI) GetGroupParticipantRequests(ctx context.Context, req GetGroupParticipantRequestsRequest) (*GroupParticipantRequestListResponse, error) {
+	p := "/group/participant-requests"
+	q := url.Values{}
+	if req.GroupID != "" {
+		q.Set("group_id", req.GroupID)
+	}
+	var out GroupParticipantRequestListResponse
+	if err := a.c.getJSON(ctx, "getGroupParticipantRequests", "/group/participant-requests", p, q, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// ApproveGroupParticipantRequest chama POST /group/participant-requests/approve: Approve participant request to join group
+func (a *RawAPI) ApproveGroupParticipantRequest(ctx context.Context, req ApproveGroupParticipantRequest) (*GenericResponse, error) {
+	p := "/group/participant-requests/approve"
+	var out GenericResponse
+	if err := a.c.postJSON(ctx, "approveGroupParticipantRequest", "/group/participant-requests/approve", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// RejectGroupParticipantRequest chama POST /group/participant-requests/reject: Reject participant request to join group
+func (a *RawAPI) RejectGroupParticipantRequest(ctx context.Context, req RejectGroupParticipantRequest) (*GenericResponse, error) {
+	p := "/group/participant-requests/reject"
+	var out GenericResponse
+	if err := a.c.postJSON(ctx, "rejectGroupParticipantRequest", "/group/participant-requests/reject", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// LeaveGroup chama POST /group/leave: Leave group
+func (a *RawAPI) LeaveGroup(ctx context.Context, req LeaveGroupRequest) (*GenericResponse, error) {
+	p := "/group/leave"
+	var out GenericResponse
+	if err := a.c.postJSON(ctx, "leaveGroup", "/group/leave", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetGroupPhoto chama POST /group/photo: Set group photo
+func (a *RawAPI) SetGroupPhoto(ctx context.Context, req SetGroupPhotoRequest) (*SetGroupPhotoResponse, error) {
+	p := "/group/photo"
+	var out SetGroupPhotoResponse
+	fields := map[string]string{}
+	if req.GroupID != "" {
+		fields["group_id"] = req.GroupID
+	}
+	if err := a.c.postFormFile(ctx, "setGroupPhoto", "/group/photo", p, req, fields, "photo", req.Photo, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetGroupName chama POST /group/name: Set group name
+func (a *RawAPI) SetGroupName(ctx context.Context, req SetGroupNameRequest) (*GenericResponse, error) {
+	p := "/group/name"
+	var out GenericResponse
+	if err := a.c.postJSON(ctx, "setGroupName", "/group/name", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetGroupLocked chama POST /group/locked: Set group locked status
+func (a *RawAPI) SetGroupLocked(ctx context.Context, req SetGroupLockedRequest) (*GenericResponse, error) {
+	p := "/group/locked"
+	var out GenericResponse
+	if err := a.c.postJSON(ctx, "setGroupLocked", "/group/locked", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetGroupAnnounce chama POST /group/announce: Set group announce mode
+func (a *RawAPI) SetGroupAnnounce(ctx context.Context, req SetGroupAnnounceRequest) (*GenericResponse, error) {
+	p := "/group/announce"
+	var out GenericResponse
+	if err := a.c.postJSON(ctx, "setGroupAnnounce", "/group/announce", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// SetGroupTopic chama POST /group/topic: Set group topic
+func (a *RawAPI) SetGroupTopic(ctx context.Context, req SetGroupTopicRequest) (*GenericResponse, error) {
+	p := "/group/topic"
+	var out GenericResponse
+	if err := a.c.postJSON(ctx, "setGroupTopic", "/group/topic", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// GroupInviteLink chama GET /group/invite-link: Group Invite Link
+func (a *RawAPI) GroupInviteLink(ctx context.Context, req GroupInviteLinkRequest) (*GetGroupInviteLinkResponse, error) {
+	p := "/group/invite-link"
+	q := url.Values{}
+	if req.GroupID != "" {
+		q.Set("group_id", req.GroupID)
+	}
+	if req.Reset != nil {
+		q.Set("reset", strconv.FormatBool(*req.Reset))
+	}
+	var out GetGroupInviteLinkResponse
+	if err := a.c.getJSON(ctx, "groupInviteLink", "/group/invite-link", p, q, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// UnfollowNewsletter chama POST /newsletter/unfollow: Unfollow newsletter
+func (a *RawAPI) UnfollowNewsletter(ctx context.Context, req UnfollowNewsletterRequest) (*GenericResponse, error) {
+	p := "/newsletter/unfollow"
+	var out GenericResponse
+	if err := a.c.postJSON(ctx, "unfollowNewsletter", "/newsletter/unfollow", p, req, req, &out); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
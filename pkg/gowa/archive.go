@@ -0,0 +1,381 @@
+package gowa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ArchivedMessage é a cópia local de uma ChatMessage, com histórico de edições
+// e marcação de revogação.
+type ArchivedMessage struct {
+	ChatMessage
+	Time      time.Time     `json:"time"`
+	Edits     []MessageEdit `json:"edits,omitempty"`
+	Revoked   bool          `json:"revoked,omitempty"`
+	RevokedAt time.Time     `json:"revoked_at,omitempty"`
+	SyncedAt  time.Time     `json:"synced_at"`
+}
+
+// MessageEdit guarda o conteúdo anterior de uma mensagem editada.
+type MessageEdit struct {
+	Content    string    `json:"content"`
+	UpdatedAt  string    `json:"updated_at,omitempty"`
+	ReplacedAt time.Time `json:"replaced_at"`
+}
+
+// ChatCursor marca até onde um chat já foi sincronizado.
+type ChatCursor struct {
+	ChatJID       string    `json:"chat_jid"`
+	LastTimestamp time.Time `json:"last_timestamp"`
+	LastSyncAt    time.Time `json:"last_sync_at"`
+}
+
+// ArchiveQuery filtra mensagens do arquivo local. Campos vazios não filtram.
+type ArchiveQuery struct {
+	ChatJID        string
+	SenderJID      string
+	Since          time.Time // inclusivo
+	Until          time.Time // exclusivo
+	Text           string    // busca case-insensitive no conteúdo e nome do arquivo
+	IncludeRevoked bool
+	Limit          int
+}
+
+// Store persiste o arquivo local de mensagens. Put substitui mensagens já
+// existentes com o mesmo (ChatJID, ID). Cursor retorna um ChatCursor zerado
+// quando o chat nunca foi sincronizado.
+type Store interface {
+	Cursor(ctx context.Context, chatJID string) (ChatCursor, error)
+	SetCursor(ctx context.Context, cur ChatCursor) error
+	Put(ctx context.Context, msgs ...ArchivedMessage) error
+	Query(ctx context.Context, q ArchiveQuery) ([]ArchivedMessage, error)
+}
+
+type ArchiverConfig struct {
+	Store    Store
+	PageSize int           // default 100 (máximo aceito pela API)
+	Overlap  time.Duration // janela relida a cada sync para detectar edições e revogações; default 24h
+	Chats    []string      // opcional: restringe o sync a esses JIDs
+}
+
+// Archiver sincroniza incrementalmente ListChats/GetChatMessages em um Store.
+type Archiver struct {
+	c   *Client
+	cfg ArchiverConfig
+	now func() time.Time
+}
+
+type SyncStats struct {
+	Chats   int
+	Added   int
+	Edited  int
+	Revoked int
+	// Restored conta as mensagens marcadas como revogadas que voltaram a
+	// aparecer no servidor e perderam a marcação.
+	Restored int
+}
+
+func (s *SyncStats) add(o SyncStats) {
+	s.Chats += o.Chats
+	s.Added += o.Added
+	s.Edited += o.Edited
+	s.Revoked += o.Revoked
+	s.Restored += o.Restored
+}
+
+func NewArchiver(c *Client, cfg ArchiverConfig) (*Archiver, error) {
+	if c == nil || cfg.Store == nil {
+		return nil, errors.New("client and store are required")
+	}
+	if cfg.PageSize <= 0 || cfg.PageSize > 100 {
+		cfg.PageSize = 100
+	}
+	if cfg.Overlap == 0 {
+		cfg.Overlap = 24 * time.Hour
+	}
+	return &Archiver{c: c, cfg: cfg, now: time.Now}, nil
+}
+
+// Sync percorre todos os chats (ou cfg.Chats) e sincroniza cada um. Erros de um
+// chat não interrompem os demais; são agregados no erro retornado.
+func (a *Archiver) Sync(ctx context.Context) (SyncStats, error) {
+	var stats SyncStats
+	jids := a.cfg.Chats
+	if len(jids) == 0 {
+		chats, err := a.c.allChats(ctx, a.cfg.PageSize)
+		if err != nil {
+			return stats, err
+		}
+		for _, ch := range chats {
+			jids = append(jids, ch.JID)
+		}
+	}
+	var errs []error
+	for _, jid := range jids {
+		if err := ctx.Err(); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		st, err := a.SyncChat(ctx, jid)
+		stats.add(st)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sync %s: %w", jid, err))
+		}
+	}
+	return stats, errors.Join(errs...)
+}
+
+// SyncChat busca as mensagens do chat a partir do cursor (menos cfg.Overlap),
+// grava as novas, registra edições e marca como revogadas as mensagens locais
+// da janela que não existem mais no servidor. Uma mensagem marcada que volta
+// a aparecer perde a marcação (SyncStats.Restored).
+func (a *Archiver) SyncChat(ctx context.Context, chatJID string) (SyncStats, error) {
+	stats := SyncStats{Chats: 1}
+	cur, err := a.cfg.Store.Cursor(ctx, chatJID)
+	if err != nil {
+		return stats, err
+	}
+	var start time.Time
+	if !cur.LastTimestamp.IsZero() {
+		start = cur.LastTimestamp.Add(-a.cfg.Overlap)
+	}
+	p := GetChatMessagesParams{Limit: a.cfg.PageSize}
+	if !start.IsZero() {
+		p.StartTime = start.UTC().Format(time.RFC3339)
+	}
+	remote, err := a.c.allChatMessages(ctx, chatJID, p)
+	if err != nil {
+		return stats, err
+	}
+	local, err := a.cfg.Store.Query(ctx, ArchiveQuery{ChatJID: chatJID, Since: start, IncludeRevoked: true})
+	if err != nil {
+		return stats, err
+	}
+	localByID := make(map[string]ArchivedMessage, len(local))
+	for _, m := range local {
+		localByID[m.ID] = m
+	}
+
+	now := a.now()
+	last := cur.LastTimestamp
+	var puts []ArchivedMessage
+	seen := make(map[string]bool, len(remote))
+	for _, m := range remote {
+		seen[m.ID] = true
+		ts := parseTimestamp(m.Timestamp)
+		if ts.After(last) {
+			last = ts
+		}
+		old, ok := localByID[m.ID]
+		if !ok {
+			puts = append(puts, ArchivedMessage{ChatMessage: m, Time: ts, SyncedAt: now})
+			stats.Added++
+			continue
+		}
+		changed := false
+		if old.Revoked {
+			// sumiu numa sincronização anterior mas existe: não foi revogada
+			old.Revoked, old.RevokedAt = false, time.Time{}
+			stats.Restored++
+			changed = true
+		}
+		if old.Content != m.Content {
+			old.Edits = append(old.Edits, MessageEdit{Content: old.Content, UpdatedAt: old.UpdatedAt, ReplacedAt: now})
+			old.ChatMessage = m
+			old.Time = ts
+			stats.Edited++
+			changed = true
+		}
+		if changed {
+			old.SyncedAt = now
+			puts = append(puts, old)
+		}
+	}
+	// Sem cursor não há janela conhecida: nada a reconciliar.
+	if !start.IsZero() {
+		for _, m := range local {
+			if m.Revoked || seen[m.ID] {
+				continue
+			}
+			m.Revoked = true
+			m.RevokedAt = now
+			puts = append(puts, m)
+			stats.Revoked++
+		}
+	}
+	if len(puts) > 0 {
+		if err := a.cfg.Store.Put(ctx, puts...); err != nil {
+			return stats, err
+		}
+	}
+	return stats, a.cfg.Store.SetCursor(ctx, ChatCursor{ChatJID: chatJID, LastTimestamp: last, LastSyncAt: now})
+}
+
+// Query consulta apenas o Store local, sem chamadas ao servidor.
+func (a *Archiver) Query(ctx context.Context, q ArchiveQuery) ([]ArchivedMessage, error) {
+	return a.cfg.Store.Query(ctx, q)
+}
+
+func parseTimestamp(s string) time.Time {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02 15:04:05Z07:00", "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// FileStore é um Store em arquivo JSON Lines append-only. O estado é mantido
+// em memória e reconstruído a partir do arquivo em OpenFileStore; uma linha
+// final truncada (queda do processo no meio da escrita) é descartada e uma
+// linha corrompida no meio do arquivo faz OpenFileStore falhar.
+type FileStore struct {
+	mu      sync.RWMutex
+	j       *journal[fileRecord]
+	msgs    map[string]map[string]ArchivedMessage
+	cursors map[string]ChatCursor
+}
+
+type fileRecord struct {
+	Message *ArchivedMessage `json:"message,omitempty"`
+	Cursor  *ChatCursor      `json:"cursor,omitempty"`
+}
+
+func OpenFileStore(path string) (*FileStore, error) {
+	s := &FileStore{
+		msgs:    map[string]map[string]ArchivedMessage{},
+		cursors: map[string]ChatCursor{},
+	}
+	j, err := openJournal(path, s.apply)
+	if err != nil {
+		return nil, err
+	}
+	s.j = j
+	return s, nil
+}
+
+func (s *FileStore) apply(rec fileRecord) {
+	if m := rec.Message; m != nil {
+		chat := s.msgs[m.ChatJID]
+		if chat == nil {
+			chat = map[string]ArchivedMessage{}
+			s.msgs[m.ChatJID] = chat
+		}
+		chat[m.ID] = *m
+	}
+	if c := rec.Cursor; c != nil {
+		s.cursors[c.ChatJID] = *c
+	}
+}
+
+func (s *FileStore) append(recs ...fileRecord) error {
+	if err := s.j.append(recs...); err != nil {
+		return err
+	}
+	for _, rec := range recs {
+		s.apply(rec)
+	}
+	return nil
+}
+
+func (s *FileStore) Cursor(_ context.Context, chatJID string) (ChatCursor, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cursors[chatJID], nil
+}
+
+func (s *FileStore) SetCursor(_ context.Context, cur ChatCursor) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.append(fileRecord{Cursor: &cur})
+}
+
+func (s *FileStore) Put(_ context.Context, msgs ...ArchivedMessage) error {
+	recs := make([]fileRecord, len(msgs))
+	for i := range msgs {
+		recs[i] = fileRecord{Message: &msgs[i]}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.append(recs...)
+}
+
+func (s *FileStore) Query(_ context.Context, q ArchiveQuery) ([]ArchivedMessage, error) {
+	text := strings.ToLower(q.Text)
+	s.mu.RLock()
+	var out []ArchivedMessage
+	for jid, chat := range s.msgs {
+		if q.ChatJID != "" && jid != q.ChatJID {
+			continue
+		}
+		for _, m := range chat {
+			if matchArchiveQuery(m, q, text) {
+				out = append(out, m)
+			}
+		}
+	}
+	s.mu.RUnlock()
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].Time.Equal(out[j].Time) {
+			return out[i].Time.Before(out[j].Time)
+		}
+		return out[i].ID < out[j].ID
+	})
+	if q.Limit > 0 && len(out) > q.Limit {
+		out = out[:q.Limit]
+	}
+	return out, nil
+}
+
+func matchArchiveQuery(m ArchivedMessage, q ArchiveQuery, text string) bool {
+	if m.Revoked && !q.IncludeRevoked {
+		return false
+	}
+	if q.SenderJID != "" && m.SenderJID != q.SenderJID {
+		return false
+	}
+	if !q.Since.IsZero() && m.Time.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && !m.Time.Before(q.Until) {
+		return false
+	}
+	if text != "" {
+		hay := strings.ToLower(m.Content)
+		if m.Filename != nil {
+			hay += "\n" + strings.ToLower(*m.Filename)
+		}
+		if !strings.Contains(hay, text) {
+			return false
+		}
+	}
+	return true
+}
+
+// Compact reescreve o arquivo apenas com o estado atual, descartando as
+// versões antigas acumuladas pelo modo append-only.
+func (s *FileStore) Compact() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var recs []fileRecord
+	for _, chat := range s.msgs {
+		for _, m := range chat {
+			recs = append(recs, fileRecord{Message: &m})
+		}
+	}
+	for _, c := range s.cursors {
+		recs = append(recs, fileRecord{Cursor: &c})
+	}
+	return s.j.rewrite(recs)
+}
+
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.j.close()
+}
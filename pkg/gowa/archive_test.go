@@ -0,0 +1,150 @@
+package gowa_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+func TestFileStoreReload(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "archive.jsonl")
+	t0 := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	msg := func(id string, at time.Time, content string) gowa.ArchivedMessage {
+		return gowa.ArchivedMessage{ChatMessage: gowa.ChatMessage{ID: id, ChatJID: "c@s.whatsapp.net", Content: content}, Time: at}
+	}
+
+	s, err := gowa.OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(ctx, msg("1", t0, "oi"), msg("2", t0.Add(time.Minute), "tudo bem?")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(ctx, msg("1", t0, "oi, editado")); err != nil {
+		t.Fatal(err)
+	}
+	cur := gowa.ChatCursor{ChatJID: "c@s.whatsapp.net", LastTimestamp: t0.Add(time.Minute)}
+	if err := s.SetCursor(ctx, cur); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	// queda no meio de uma escrita: o fragmento final é descartado
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"message":{"id":"3"`)
+	f.Close()
+
+	check := func(s *gowa.FileStore) {
+		t.Helper()
+		got, err := s.Query(ctx, gowa.ArchiveQuery{ChatJID: "c@s.whatsapp.net"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != 2 || got[0].Content != "oi, editado" || got[1].ID != "2" {
+			t.Errorf("messages = %+v", got)
+		}
+		if c, _ := s.Cursor(ctx, "c@s.whatsapp.net"); !c.LastTimestamp.Equal(cur.LastTimestamp) {
+			t.Errorf("cursor = %+v", c)
+		}
+	}
+	s, err = gowa.OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check(s)
+	if err := s.Compact(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Put(ctx, msg("4", t0.Add(time.Hour), "depois do compact")); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = gowa.OpenFileStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if got, _ := s.Query(ctx, gowa.ArchiveQuery{Text: "COMPACT"}); len(got) != 1 || got[0].ID != "4" {
+		t.Errorf("after compact: %+v", got)
+	}
+}
+
+func TestFileStoreCorruption(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "archive.jsonl")
+	data := "{\"cursor\":{\"chat_jid\":\"c\"}}\nnot json\n{\"cursor\":{\"chat_jid\":\"d\"}}\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := gowa.OpenFileStore(path); err == nil {
+		t.Fatal("corrupted line in the middle accepted")
+	}
+	if b, _ := os.ReadFile(path); string(b) != data {
+		t.Error("corrupted file was modified")
+	}
+}
+
+func TestArchiverSync(t *testing.T) {
+	ctx := context.Background()
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	chat := "5511999990000@s.whatsapp.net"
+	srv.AddChat(gowa.Chat{JID: chat, Name: "Ana"})
+	t0 := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	a := srv.AddMessage(gowa.ChatMessage{ChatJID: chat, Content: "primeira", Timestamp: t0.Format(time.RFC3339)})
+	srv.AddMessage(gowa.ChatMessage{ChatJID: chat, Content: "segunda", Timestamp: t0.Add(time.Minute).Format(time.RFC3339)})
+
+	store, err := gowa.OpenFileStore(filepath.Join(t.TempDir(), "archive.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	ar, err := gowa.NewArchiver(srv.GowaClient(), gowa.ArchiverConfig{Store: store})
+	if err != nil {
+		t.Fatal(err)
+	}
+	st, err := ar.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Chats != 1 || st.Added != 2 {
+		t.Errorf("first sync = %+v", st)
+	}
+	st, err = ar.Sync(ctx)
+	if err != nil || st.Added != 0 || st.Edited != 0 || st.Revoked != 0 {
+		t.Errorf("second sync = %+v, %v", st, err)
+	}
+	if got, _ := ar.Query(ctx, gowa.ArchiveQuery{Text: "PRIMEIRA"}); len(got) != 1 || got[0].ID != a.ID {
+		t.Errorf("query = %+v", got)
+	}
+
+	// some do servidor (revogada) e depois reaparece
+	if _, err := srv.GowaClient().RevokeMessage(ctx, gowa.MessageActionParams{MessageID: a.ID, Phone: chat}); err != nil {
+		t.Fatal(err)
+	}
+	if st, err = ar.Sync(ctx); err != nil || st.Revoked != 1 {
+		t.Fatalf("sync after revoke = %+v, %v", st, err)
+	}
+	srv.AddMessage(a)
+	if st, err = ar.Sync(ctx); err != nil || st.Restored != 1 || st.Revoked != 0 {
+		t.Fatalf("sync after reappearance = %+v, %v", st, err)
+	}
+	got, err := ar.Query(ctx, gowa.ArchiveQuery{ChatJID: chat, IncludeRevoked: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range got {
+		if m.Revoked || !m.RevokedAt.IsZero() {
+			t.Errorf("%s still revoked after reappearing", m.ID)
+		}
+	}
+}
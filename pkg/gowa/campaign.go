@@ -0,0 +1,330 @@
+package gowa
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Recipient é um destinatário de campanha: o número e as variáveis usadas no
+// template (colunas do CSV).
+type Recipient struct {
+	Phone string
+	Vars  map[string]string
+}
+
+// ReadRecipientsCSV lê destinatários de um CSV com cabeçalho. A coluna
+// phoneColumn (default "phone", sem diferenciar maiúsculas) é o número; todas
+// as colunas, inclusive ela, viram Vars. Linhas com número vazio são mantidas
+// e saem como inválidas no relatório.
+func ReadRecipientsCSV(r io.Reader, phoneColumn string) ([]Recipient, error) {
+	if phoneColumn == "" {
+		phoneColumn = "phone"
+	}
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("campaign: csv header: %w", err)
+	}
+	phoneIdx := -1
+	for i, h := range header {
+		header[i] = strings.TrimSpace(strings.TrimPrefix(h, "\ufeff"))
+		if strings.EqualFold(header[i], phoneColumn) {
+			phoneIdx = i
+		}
+	}
+	if phoneIdx < 0 {
+		return nil, fmt.Errorf("campaign: csv has no %q column", phoneColumn)
+	}
+	var out []Recipient
+	for {
+		row, err := cr.Read()
+		if err == io.EOF {
+			return out, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("campaign: csv: %w", err)
+		}
+		rec := Recipient{Vars: make(map[string]string, len(header))}
+		for i, h := range header {
+			if i < len(row) {
+				rec.Vars[h] = strings.TrimSpace(row[i])
+			}
+		}
+		rec.Phone = rec.Vars[header[phoneIdx]]
+		out = append(out, rec)
+	}
+}
+
+// CampaignStatus é o resultado do envio para um destinatário.
+type CampaignStatus string
+
+const (
+	CampaignSent      CampaignStatus = "sent"
+	CampaignInvalid   CampaignStatus = "invalid"   // número vazio ou fora do WhatsApp
+	CampaignFailed    CampaignStatus = "failed"    // erro no template, na verificação ou no envio
+	CampaignDuplicate CampaignStatus = "duplicate" // número repetido na lista; enviado só uma vez
+)
+
+type CampaignConfig struct {
+	// Message é um text/template executado com as Vars do destinatário e
+	// .Phone, por exemplo "Olá {{.nome}}, seu pedido {{.pedido}} saiu".
+	// Variável ausente é erro (o destinatário sai como failed, código
+	// "template"). Com mídia, vira a legenda.
+	Message string
+
+	// Mídia opcional, no máximo uma. Image e Video aceitam caminho local ou
+	// URL http(s); Document só caminho local.
+	Image    string
+	Video    string
+	Document string
+
+	// Throttle limita o ritmo dos envios da campanha, somando-se ao
+	// Config.RateLimit do client; default PerMinute(20, 1). Jitter soma um
+	// atraso aleatório em [0, Jitter) a cada envio.
+	Throttle RateLimit
+	Jitter   time.Duration
+
+	// SkipCheck desliga a verificação por /user/check antes de cada envio.
+	SkipCheck bool
+
+	// Options são aplicadas a todos os envios (ex.: WithDisappearingDuration).
+	Options []SendOption
+
+	// OnResult é chamado a cada destinatário processado, para progresso.
+	OnResult func(CampaignResult)
+}
+
+// CampaignResult é a linha do relatório de um destinatário.
+type CampaignResult struct {
+	Phone     string         `json:"phone"`
+	Status    CampaignStatus `json:"status"`
+	MessageID string         `json:"message_id,omitempty"`
+	ErrorCode string         `json:"error_code,omitempty"` // APIError.Code ou um código de errorCode
+	Error     string         `json:"error,omitempty"`
+	At        time.Time      `json:"at"`
+}
+
+// CampaignReport reúne os resultados na ordem da lista de destinatários.
+type CampaignReport struct {
+	Results []CampaignResult `json:"results"`
+}
+
+// Counts conta os resultados por status.
+func (r *CampaignReport) Counts() map[CampaignStatus]int {
+	out := map[CampaignStatus]int{}
+	for _, res := range r.Results {
+		out[res.Status]++
+	}
+	return out
+}
+
+// WriteCSV grava o relatório como CSV (phone, status, message_id, error_code,
+// error, at).
+func (r *CampaignReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"phone", "status", "message_id", "error_code", "error", "at"})
+	for _, res := range r.Results {
+		cw.Write([]string{res.Phone, string(res.Status), res.MessageID, res.ErrorCode, res.Error, res.At.Format(time.RFC3339)})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON grava o relatório como JSON indentado.
+func (r *CampaignReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// Campaign envia uma mensagem personalizada para uma lista de destinatários,
+// um de cada vez e no ritmo de CampaignConfig.Throttle. Implementa Pausable.
+type Campaign struct {
+	c       *Client
+	cfg     CampaignConfig
+	tmpl    *template.Template
+	limiter *rateLimiter
+
+	mu     sync.Mutex
+	paused bool
+	resume chan struct{} // fechado por Resume
+}
+
+func NewCampaign(c *Client, cfg CampaignConfig) (*Campaign, error) {
+	if c == nil {
+		return nil, errors.New("campaign: client is required")
+	}
+	media := 0
+	for _, m := range []string{cfg.Image, cfg.Video, cfg.Document} {
+		if m != "" {
+			media++
+		}
+	}
+	if media > 1 {
+		return nil, errors.New("campaign: set at most one of Image, Video and Document")
+	}
+	if cfg.Message == "" && media == 0 {
+		return nil, errors.New("campaign: Message or media is required")
+	}
+	tmpl, err := template.New("message").Option("missingkey=error").Parse(cfg.Message)
+	if err != nil {
+		return nil, fmt.Errorf("campaign: template: %w", err)
+	}
+	if !cfg.Throttle.enabled() {
+		cfg.Throttle = PerMinute(20, 1)
+	}
+	return &Campaign{
+		c:       c,
+		cfg:     cfg,
+		tmpl:    tmpl,
+		limiter: newRateLimiter(RateLimitConfig{Global: cfg.Throttle, Jitter: cfg.Jitter}),
+	}, nil
+}
+
+func (cp *Campaign) Pause() {
+	cp.mu.Lock()
+	if !cp.paused {
+		cp.paused = true
+		cp.resume = make(chan struct{})
+	}
+	cp.mu.Unlock()
+}
+
+func (cp *Campaign) Resume() {
+	cp.mu.Lock()
+	if cp.paused {
+		cp.paused = false
+		close(cp.resume)
+	}
+	cp.mu.Unlock()
+}
+
+// waitResumed bloqueia enquanto a campanha estiver pausada.
+func (cp *Campaign) waitResumed(ctx context.Context) error {
+	cp.mu.Lock()
+	paused, ch := cp.paused, cp.resume
+	cp.mu.Unlock()
+	if !paused {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-ch:
+		return nil
+	}
+}
+
+// Run processa os destinatários em ordem. Se o ctx for cancelado, retorna o
+// relatório parcial (só os já processados) junto com ctx.Err().
+func (cp *Campaign) Run(ctx context.Context, recipients []Recipient) (*CampaignReport, error) {
+	report := &CampaignReport{Results: make([]CampaignResult, 0, len(recipients))}
+	seen := make(map[string]bool, len(recipients))
+	for _, rcpt := range recipients {
+		if err := cp.waitResumed(ctx); err != nil {
+			return report, err
+		}
+		phone := strings.TrimSpace(rcpt.Phone)
+		// "+5511…", "5511…" e "5511…@s.whatsapp.net" são o mesmo destinatário
+		key := recipientKey(phone)
+		var res CampaignResult
+		switch {
+		case phone == "":
+			res = CampaignResult{Status: CampaignInvalid, Error: "empty phone"}
+		case seen[key]:
+			res = CampaignResult{Status: CampaignDuplicate}
+		default:
+			seen[key] = true
+			var err error
+			if res, err = cp.deliver(ctx, phone, rcpt.Vars); err != nil {
+				return report, err
+			}
+		}
+		res.Phone, res.At = phone, time.Now()
+		report.Results = append(report.Results, res)
+		if cp.cfg.OnResult != nil {
+			cp.cfg.OnResult(res)
+		}
+	}
+	return report, nil
+}
+
+// deliver renderiza, verifica e envia para um destinatário. Só retorna erro
+// quando o ctx é cancelado; as demais falhas vão para o resultado.
+func (cp *Campaign) deliver(ctx context.Context, phone string, vars map[string]string) (CampaignResult, error) {
+	data := make(map[string]string, len(vars)+1)
+	for k, v := range vars {
+		data[k] = v
+	}
+	data["Phone"] = phone
+	var text strings.Builder
+	if err := cp.tmpl.Execute(&text, data); err != nil {
+		return CampaignResult{Status: CampaignFailed, ErrorCode: "template", Error: err.Error()}, nil
+	}
+
+	if !cp.cfg.SkipCheck {
+		resp, err := cp.c.Raw().UserCheck(ctx, UserCheckRequest{Phone: phone})
+		if ctx.Err() != nil {
+			return CampaignResult{}, ctx.Err()
+		}
+		if err != nil {
+			return cp.failed(err), nil
+		}
+		if !resp.Results.IsOnWhatsapp {
+			return CampaignResult{Status: CampaignInvalid, Error: "not on WhatsApp"}, nil
+		}
+	}
+
+	if _, err := cp.limiter.wait(ctx, ""); err != nil {
+		return CampaignResult{}, err
+	}
+	resp, err := cp.send(ctx, phone, text.String())
+	if ctx.Err() != nil {
+		return CampaignResult{}, ctx.Err()
+	}
+	if err != nil {
+		return cp.failed(err), nil
+	}
+	return CampaignResult{Status: CampaignSent, MessageID: resp.Results.MessageID}, nil
+}
+
+func (cp *Campaign) failed(err error) CampaignResult {
+	return CampaignResult{Status: CampaignFailed, ErrorCode: errorCode(err), Error: err.Error()}
+}
+
+func (cp *Campaign) send(ctx context.Context, phone, text string) (*SendResponse, error) {
+	opts := cp.cfg.Options
+	switch {
+	case cp.cfg.Image != "":
+		if isURL(cp.cfg.Image) {
+			return cp.c.SendImageURL(ctx, phone, text, cp.cfg.Image, false, false, opts...)
+		}
+		return cp.c.SendImageFile(ctx, phone, text, cp.cfg.Image, false, false, opts...)
+	case cp.cfg.Video != "":
+		p := SendVideoParams{Phone: phone, Caption: text}
+		if isURL(cp.cfg.Video) {
+			p.VideoURL = cp.cfg.Video
+		} else {
+			p.VideoPath = cp.cfg.Video
+		}
+		return cp.c.SendVideo(ctx, p, opts...)
+	case cp.cfg.Document != "":
+		return cp.c.SendFile(ctx, SendFileParams{Phone: phone, Caption: text, FilePath: cp.cfg.Document}, opts...)
+	default:
+		return cp.c.SendMessage(ctx, phone, text, opts...)
+	}
+}
+
+func isURL(s string) bool {
+	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
+}
@@ -0,0 +1,243 @@
+package gowa_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+func TestReadRecipientsCSV(t *testing.T) {
+	in := "\ufeffNome, Phone ,pedido\n" +
+		`"Silva, Ana", 5511999990001,"A-1"` + "\n" +
+		`"Bia ""B""",5511999990002,"linha 1` + "\n" + `linha 2"` + "\n" +
+		"Caio\n" // linha curta: sem número
+	rs, err := gowa.ReadRecipientsCSV(strings.NewReader(in), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []gowa.Recipient{
+		{Phone: "5511999990001", Vars: map[string]string{"Nome": "Silva, Ana", "Phone": "5511999990001", "pedido": "A-1"}},
+		{Phone: "5511999990002", Vars: map[string]string{"Nome": `Bia "B"`, "Phone": "5511999990002", "pedido": "linha 1\nlinha 2"}},
+		{Phone: "", Vars: map[string]string{"Nome": "Caio"}},
+	}
+	if !reflect.DeepEqual(rs, want) {
+		t.Errorf("recipients =\n%+v\nwant\n%+v", rs, want)
+	}
+
+	rs, err = gowa.ReadRecipientsCSV(strings.NewReader("nome,celular\nAna,5511999990001\n"), "Celular")
+	if err != nil || len(rs) != 1 || rs[0].Phone != "5511999990001" {
+		t.Errorf("custom column: %+v, %v", rs, err)
+	}
+
+	for name, in := range map[string]string{
+		"missing column": "nome,telefone\nAna,5511999990001\n",
+		"empty":          "",
+		"bad quoting":    "phone\n\"5511\"x\n",
+	} {
+		if _, err := gowa.ReadRecipientsCSV(strings.NewReader(in), "phone"); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func newCampaign(t *testing.T, c *gowa.Client, cfg gowa.CampaignConfig) *gowa.Campaign {
+	t.Helper()
+	cfg.Throttle = gowa.RateLimit{Rate: 1000, Every: time.Second}
+	cp, err := gowa.NewCampaign(c, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cp
+}
+
+func TestCampaignRun(t *testing.T) {
+	ctx := context.Background()
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	srv.SetOnWhatsApp("5511999990004", false)
+	var seen []gowa.CampaignResult
+	cp := newCampaign(t, srv.GowaClient(), gowa.CampaignConfig{
+		Message:  "Olá {{.nome}}, pedido {{.pedido}} ({{.Phone}})",
+		OnResult: func(r gowa.CampaignResult) { seen = append(seen, r) },
+	})
+	rs := []gowa.Recipient{
+		{Phone: "5511999990001", Vars: map[string]string{"nome": "Ana", "pedido": "A-1"}},
+		{Phone: " +5511999990001 ", Vars: map[string]string{"nome": "Ana", "pedido": "A-1"}},
+		{Phone: "5511999990001@s.whatsapp.net", Vars: map[string]string{"nome": "Ana", "pedido": "A-1"}},
+		{Phone: "", Vars: map[string]string{"nome": "Sem número"}},
+		{Phone: "5511999990003", Vars: map[string]string{"nome": "Bia"}}, // falta pedido
+		{Phone: "5511999990004", Vars: map[string]string{"nome": "Caio", "pedido": "C-3"}},
+	}
+	rep, err := cp.Run(ctx, rs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []gowa.CampaignStatus{gowa.CampaignSent, gowa.CampaignDuplicate, gowa.CampaignDuplicate, gowa.CampaignInvalid, gowa.CampaignFailed, gowa.CampaignInvalid}
+	if len(rep.Results) != len(want) {
+		t.Fatalf("%d results, want %d", len(rep.Results), len(want))
+	}
+	for i, r := range rep.Results {
+		if r.Status != want[i] {
+			t.Errorf("result %d (%q) = %s %s, want %s", i, r.Phone, r.Status, r.Error, want[i])
+		}
+	}
+	if r := rep.Results[0]; r.MessageID == "" {
+		t.Error("sent result without message_id")
+	}
+	if r := rep.Results[4]; r.ErrorCode != "template" || !strings.Contains(r.Error, "pedido") {
+		t.Errorf("missing variable: %+v, want template error naming the key", r)
+	}
+	if r := rep.Results[5]; r.Error != "not on WhatsApp" {
+		t.Errorf("off WhatsApp: %+v", r)
+	}
+	if got := rep.Counts(); got[gowa.CampaignSent] != 1 || got[gowa.CampaignDuplicate] != 2 || got[gowa.CampaignInvalid] != 2 || got[gowa.CampaignFailed] != 1 {
+		t.Errorf("Counts = %v", got)
+	}
+	if !reflect.DeepEqual(seen, rep.Results) {
+		t.Error("OnResult not called once per result in order")
+	}
+
+	// só uma mensagem, com o template renderizado
+	var sent []gowa.SendMessageRequest
+	for _, r := range srv.Requests() {
+		if r.Path == "/send/message" {
+			var body gowa.SendMessageRequest
+			if err := r.JSON(&body); err != nil {
+				t.Fatal(err)
+			}
+			sent = append(sent, body)
+		}
+	}
+	if len(sent) != 1 || sent[0].Message != "Olá Ana, pedido A-1 (5511999990001)" {
+		t.Errorf("sent = %+v", sent)
+	}
+}
+
+func TestCampaignSendError(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	srv.AddFault(gowatest.Fault{Path: "/send/message", Status: 400, Times: 1, Body: `{"code":"INVALID_JID","message":"invalid jid"}`})
+	cp := newCampaign(t, srv.GowaClient(), gowa.CampaignConfig{Message: "oi", SkipCheck: true})
+	rep, err := cp.Run(context.Background(), []gowa.Recipient{{Phone: "5511999990001"}, {Phone: "5511999990002"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r := rep.Results[0]; r.Status != gowa.CampaignFailed || r.ErrorCode != "INVALID_JID" {
+		t.Errorf("failed send = %+v", r)
+	}
+	if r := rep.Results[1]; r.Status != gowa.CampaignSent {
+		t.Errorf("next recipient = %+v, want sent", r)
+	}
+	if _, ok := srv.LastRequest("/user/check"); ok {
+		t.Error("SkipCheck still called /user/check")
+	}
+}
+
+func TestCampaignPause(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	var (
+		mu      sync.Mutex
+		results int
+	)
+	var cp *gowa.Campaign
+	cp = newCampaign(t, srv.GowaClient(), gowa.CampaignConfig{
+		Message: "oi",
+		OnResult: func(gowa.CampaignResult) {
+			mu.Lock()
+			results++
+			if results == 1 {
+				cp.Pause()
+			}
+			mu.Unlock()
+		},
+	})
+	count := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return results
+	}
+	rs := []gowa.Recipient{{Phone: "5511999990001"}, {Phone: "5511999990002"}, {Phone: "5511999990003"}}
+
+	done := make(chan *gowa.CampaignReport)
+	go func() {
+		rep, _ := cp.Run(context.Background(), rs)
+		done <- rep
+	}()
+	eventually(t, "first result", func() bool { return count() == 1 })
+	time.Sleep(50 * time.Millisecond)
+	if n := count(); n != 1 {
+		t.Fatalf("%d results while paused", n)
+	}
+	cp.Resume()
+	select {
+	case rep := <-done:
+		if len(rep.Results) != 3 {
+			t.Errorf("%d results after resume, want 3", len(rep.Results))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("campaign did not resume")
+	}
+
+	// cancelar enquanto pausada devolve o relatório parcial
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cp = newCampaign(t, srv.GowaClient(), gowa.CampaignConfig{
+		Message: "oi",
+		OnResult: func(gowa.CampaignResult) {
+			cp.Pause()
+			cancel()
+		},
+	})
+	rep, err := cp.Run(ctx, rs)
+	if !errors.Is(err, context.Canceled) || len(rep.Results) != 1 {
+		t.Errorf("cancelled while paused: %d results, err %v", len(rep.Results), err)
+	}
+}
+
+func TestCampaignReport(t *testing.T) {
+	at := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	rep := &gowa.CampaignReport{Results: []gowa.CampaignResult{
+		{Phone: "5511999990001", Status: gowa.CampaignSent, MessageID: "3EB0", At: at},
+		{Phone: "5511999990002", Status: gowa.CampaignFailed, ErrorCode: "template", Error: `map has no entry for key "pedido", linha 2`, At: at},
+	}}
+
+	var buf bytes.Buffer
+	if err := rep.WriteCSV(&buf); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"phone", "status", "message_id", "error_code", "error", "at"},
+		{"5511999990001", "sent", "3EB0", "", "", "2025-05-01T12:00:00Z"},
+		{"5511999990002", "failed", "", "template", `map has no entry for key "pedido", linha 2`, "2025-05-01T12:00:00Z"},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("csv = %q", rows)
+	}
+
+	buf.Reset()
+	if err := rep.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"message_id": "3EB0"`) || strings.Contains(buf.String(), `"error_code": ""`) {
+		t.Errorf("json = %s", buf.String())
+	}
+	var back gowa.CampaignReport
+	if err := json.Unmarshal(buf.Bytes(), &back); err != nil || !reflect.DeepEqual(back, *rep) {
+		t.Errorf("json round trip = %+v, %v", back, err)
+	}
+}
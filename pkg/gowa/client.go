@@ -1,12 +1,14 @@
 package gowa
 
 import (
+	"bytes"
 	"context"
 	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"net/url"
@@ -16,6 +18,8 @@ import (
 	"time"
 
 	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type Config struct {
@@ -24,13 +28,41 @@ type Config struct {
 	Password   string
 	HTTPClient *http.Client
 	Timeout    time.Duration
+	RetryMax   int // default 3; negativo desativa os retries
+
+	// Middlewares envolvem todas as chamadas da API (getJSON, postJSON,
+	// postFormFile e os downloads de mídia e QR), na ordem da lista: o
+	// primeiro é o mais externo.
+	Middlewares []Middleware
+
+	// TracerProvider e MeterProvider recebem os spans e métricas de cada
+	// chamada. Quando nil, usam os providers globais do OpenTelemetry, que
+	// são no-op até a aplicação configurar um.
+	TracerProvider trace.TracerProvider
+	MeterProvider  metric.MeterProvider
+
+	// Logger recebe o ciclo de vida das requisições (Debug), retries (Warn) e
+	// erros (Error), além dos logs do retryablehttp. Nil desativa os logs.
+	// Redact define a redação de telefones e textos (padrão RedactStrict).
+	Logger *slog.Logger
+	Redact RedactLevel
+
+	// RateLimit limita o ritmo dos envios (POST /send/*, exceto os de
+	// presença); nil não limita.
+	RateLimit *RateLimitConfig
 }
 
 type Client struct {
 	cfg    Config
 	c      *retryablehttp.Client
+	stream *retryablehttp.Client // downloads: sem o Timeout do http.Client
 	base   *url.URL
 	common http.Header
+	doer   Doer
+	tel    *telemetry
+	log    *slog.Logger
+	redact redactor
+	limit  *rateLimiter
 }
 
 func New(cfg Config) (*Client, error) {
@@ -44,18 +76,23 @@ func New(cfg Config) (*Client, error) {
 	if cfg.Timeout == 0 {
 		cfg.Timeout = 30 * time.Second
 	}
-	rc := retryablehttp.NewClient()
-	rc.RetryWaitMin = 200 * time.Millisecond
-	rc.RetryWaitMax = 2 * time.Second
-	rc.RetryMax = 3
+	rc := newRetryClient(cfg)
 	if cfg.HTTPClient != nil {
 		rc.HTTPClient = cfg.HTTPClient
 	}
 	rc.HTTPClient.Timeout = cfg.Timeout
+	// O Timeout do http.Client cobre também a leitura do corpo e cortaria
+	// downloads grandes: eles usam uma cópia sem Timeout (o mesmo transporte)
+	// e o prazo vem do ctx (veja doStream).
+	streamHC := *rc.HTTPClient
+	streamHC.Timeout = 0
+	sc := newRetryClient(cfg)
+	sc.HTTPClient = &streamHC
 	cl := &Client{
-		cfg:  cfg,
-		c:    rc,
-		base: u,
+		cfg:    cfg,
+		c:      rc,
+		stream: sc,
+		base:   u,
 		common: http.Header{
 			"Accept":       []string{"application/json"},
 			"Content-Type": []string{"application/json"},
@@ -65,15 +102,45 @@ func New(cfg Config) (*Client, error) {
 		basic := base64.StdEncoding.EncodeToString([]byte(cfg.Username + ":" + cfg.Password))
 		cl.common.Set("Authorization", "Basic "+basic)
 	}
+	cl.log, cl.redact = cfg.Logger, redactor{level: cfg.Redact}
+	if cl.log == nil {
+		cl.log = discardLogger
+	}
+	for _, r := range []*retryablehttp.Client{rc, sc} {
+		r.Logger = retryLogger{l: cl.log, redact: cl.redact}
+		r.RequestLogHook = cl.requestHook
+	}
+	if cfg.RateLimit != nil {
+		cl.limit = newRateLimiter(*cfg.RateLimit)
+	}
+	cl.tel = newTelemetry(cfg.TracerProvider, cfg.MeterProvider)
+	cl.doer = chain(cfg.Middlewares, DoerFunc(cl.send))
 	return cl, nil
 }
 
+func newRetryClient(cfg Config) *retryablehttp.Client {
+	rc := retryablehttp.NewClient()
+	rc.RetryWaitMin = 200 * time.Millisecond
+	rc.RetryWaitMax = 2 * time.Second
+	rc.RetryMax = 3
+	if cfg.RetryMax != 0 {
+		rc.RetryMax = max(cfg.RetryMax, 0)
+	}
+	// Após esgotar as tentativas, devolve a última resposta para que doURL
+	// monte o APIError com o corpo retornado pelo servidor.
+	rc.ErrorHandler = retryablehttp.PassthroughErrorHandler
+	return rc
+}
+
 func (c *Client) url(p string) string {
-	return c.base.ResolveReference(&url.URL{Path: path.Join(c.base.Path, p)}).String()
+	p, rawQuery, _ := strings.Cut(p, "?")
+	return c.base.ResolveReference(&url.URL{Path: path.Join(c.base.Path, p), RawQuery: rawQuery}).String()
 }
 
-func (c *Client) do(ctx context.Context, method, p string, body io.Reader, headers http.Header) (*http.Response, error) {
-	req, err := retryablehttp.NewRequest(method, c.url(p), body)
+// doURL executa a requisição em uma URL absoluta. O header Authorization só é
+// enviado para o mesmo host do BaseURL, para não vazar credenciais.
+func (c *Client) doURL(ctx context.Context, rc *retryablehttp.Client, method, u string, body io.Reader, headers http.Header) (*http.Response, error) {
+	req, err := retryablehttp.NewRequest(method, u, body)
 	if err != nil {
 		return nil, err
 	}
@@ -83,57 +150,205 @@ func (c *Client) do(ctx context.Context, method, p string, body io.Reader, heade
 			req.Header.Add(k, vv)
 		}
 	}
+	if req.URL.Host != c.base.Host {
+		req.Header.Del("Authorization")
+	}
 	for k, v := range headers {
 		for _, vv := range v {
 			req.Header.Set(k, vv)
 		}
 	}
-	resp, err := c.c.Do(req)
+	resp, err := rc.Do(req)
 	if err != nil {
 		return nil, err
 	}
 	if resp.StatusCode >= 400 {
 		defer resp.Body.Close()
 		b, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("http %d: %s", resp.StatusCode, string(b))
+		return nil, newAPIError(resp.StatusCode, b)
 	}
 	return resp, nil
 }
 
-func (c *Client) getJSON(ctx context.Context, p string, q url.Values, out any) error {
-	if q != nil && len(q) > 0 {
-		p = p + "?" + q.Encode()
+// doStream executa um download com c.stream. Config.Timeout vale até a
+// chegada dos headers; a leitura do corpo fica limitada só pelo ctx. O
+// cancel retornado encerra a requisição e deve ser chamado depois de ler o
+// corpo.
+func (c *Client) doStream(ctx context.Context, method, u string, headers http.Header) (*http.Response, func(), error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	timeout := c.cfg.Timeout
+	timer := time.AfterFunc(timeout, func() {
+		cancel(fmt.Errorf("gowa: no response within %s: %w", timeout, context.DeadlineExceeded))
+	})
+	resp, err := c.doURL(ctx, c.stream, method, u, nil, headers)
+	if !timer.Stop() && err == nil {
+		// o prazo venceu junto com a chegada da resposta
+		resp.Body.Close()
+		err = context.Cause(ctx)
 	}
-	resp, err := c.do(ctx, http.MethodGet, p, nil, nil)
 	if err != nil {
-		return err
+		if cause := context.Cause(ctx); cause != nil {
+			err = cause
+		}
+		cancel(nil)
+		return nil, nil, err
 	}
-	defer resp.Body.Close()
-	return json.NewDecoder(resp.Body).Decode(out)
+	return resp, func() { cancel(nil) }, nil
+}
+
+// APIError é retornado quando o servidor responde com status >= 400. Code e
+// Message vêm do envelope JSON de erro, quando presente.
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("http %d: %s", e.StatusCode, e.Body)
+}
+
+func newAPIError(status int, body []byte) *APIError {
+	e := &APIError{StatusCode: status, Body: string(body)}
+	var env struct {
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+	}
+	if json.Unmarshal(body, &env) == nil {
+		// code é string ("INTERNAL_SERVER_ERROR") ou número (400) conforme o endpoint
+		var code string
+		if json.Unmarshal(env.Code, &code) != nil {
+			code = string(env.Code)
+		}
+		e.Code, e.Message = code, env.Message
+	}
+	return e
+}
+
+// getJSON, postJSON e postFormFile recebem, além do path montado p, o
+// template route do OpenAPI (ex.: "/chat/{chat_jid}/messages").
+func (c *Client) getJSON(ctx context.Context, op, route, p string, q url.Values, req, out any) error {
+	call := newCall(op, http.MethodGet, p, q, req)
+	call.Route = route
+	call.Response = out
+	return c.doer.Do(ctx, call)
+}
+
+func (c *Client) postJSON(ctx context.Context, op, route, p string, req, in, out any) error {
+	call := newCall(op, http.MethodPost, p, nil, req)
+	call.Route = route
+	call.Body = in
+	call.Response = out
+	return c.doer.Do(ctx, call)
 }
 
-func (c *Client) postJSON(ctx context.Context, p string, in any, out any) error {
+func (c *Client) postFormFile(ctx context.Context, op, route, p string, req any, fields map[string]string, fileField, filePath string, out any) error {
+	call := newCall(op, http.MethodPost, p, nil, req)
+	call.Route = route
+	call.Multipart = true
+	call.Form, call.FileField, call.FilePath = fields, fileField, filePath
+	call.Response = out
+	return c.doer.Do(ctx, call)
+}
+
+// download baixa a URL absoluta u (mídias e QR servidos pelo gowa) para w,
+// pela mesma cadeia de middlewares das chamadas da API, e retorna os headers
+// da resposta.
+func (c *Client) download(ctx context.Context, op, u, accept string, w io.Writer) (http.Header, error) {
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return nil, err
+	}
+	call := newCall(op, http.MethodGet, parsed.Path, nil, nil)
+	call.URL = u
+	call.Header.Set("Accept", accept)
+	call.Output = w
+	if err := c.doer.Do(ctx, call); err != nil {
+		return nil, err
+	}
+	return call.ResponseHeader, nil
+}
+
+// send é o fim da cadeia de middlewares: serializa o Call, executa a
+// requisição e decodifica a resposta em call.Response.
+func (c *Client) send(ctx context.Context, call *Call) error {
+	ctx, span := c.tel.start(ctx, call)
+	began := time.Now()
+	// o rate limit vem antes de montar o corpo: o multipart abre o arquivo
+	// em uma goroutine que só termina quando o corpo é lido
+	if c.limit != nil && rateLimited(call) {
+		waited, err := c.limit.wait(ctx, callRecipient(call))
+		span.rateLimit(ctx, waited, err)
+		if err != nil {
+			span.end(ctx, 0, err)
+			c.logEnd(ctx, call, 0, began, err)
+			return err
+		}
+	}
+	p := call.Path
+	if len(call.Query) > 0 {
+		p += "?" + call.Query.Encode()
+	}
+	headers := call.Header.Clone()
+	if headers == nil {
+		headers = http.Header{}
+	}
 	var body io.Reader
-	if in != nil {
-		b, err := json.Marshal(in)
+	switch {
+	case call.Multipart:
+		pr, ct := multipartBody(call.Form, call.FileField, call.FilePath)
+		body = pr
+		headers.Set("Content-Type", ct)
+	case call.Body != nil:
+		b, err := json.Marshal(call.Body)
 		if err != nil {
+			span.end(ctx, 0, err)
+			c.logEnd(ctx, call, 0, began, err)
 			return err
 		}
-		body = strings.NewReader(string(b))
+		body = bytes.NewReader(b)
+		headers.Set("Content-Type", "application/json")
+	}
+	c.logStart(ctx, call, headers)
+	u := call.URL
+	if u == "" {
+		u = c.url(p)
+	}
+	var resp *http.Response
+	var err error
+	if call.Output != nil {
+		var done func()
+		resp, done, err = c.doStream(ctx, call.Method, u, headers)
+		if done != nil {
+			defer done()
+		}
+	} else {
+		resp, err = c.doURL(ctx, c.c, call.Method, u, body, headers)
 	}
-	resp, err := c.do(ctx, http.MethodPost, p, body, http.Header{"Content-Type": []string{"application/json"}})
 	if err != nil {
+		span.end(ctx, 0, err)
+		c.logEnd(ctx, call, 0, began, err)
 		return err
 	}
 	defer resp.Body.Close()
-	if out == nil {
+	call.ResponseHeader = resp.Header
+	switch {
+	case call.Output != nil:
+		_, err = io.Copy(call.Output, resp.Body)
+	case call.Response == nil:
 		io.Copy(io.Discard, resp.Body)
-		return nil
+	default:
+		err = json.NewDecoder(resp.Body).Decode(call.Response)
 	}
-	return json.NewDecoder(resp.Body).Decode(out)
+	span.end(ctx, resp.StatusCode, err)
+	c.logEnd(ctx, call, resp.StatusCode, began, err)
+	return err
 }
 
-func (c *Client) postFormFile(ctx context.Context, p string, fields map[string]string, fileField, filePath string, out any) error {
+// multipartBody transmite os campos e o arquivo (se houver) por um pipe, sem
+// carregar o arquivo inteiro em memória.
+func multipartBody(fields map[string]string, fileField, filePath string) (io.Reader, string) {
 	pr, pw := io.Pipe()
 	mw := multipart.NewWriter(pw)
 	go func() {
@@ -160,90 +375,101 @@ func (c *Client) postFormFile(ctx context.Context, p string, fields map[string]s
 			}
 		}
 	}()
-	resp, err := c.do(ctx, http.MethodPost, p, pr, http.Header{"Content-Type": []string{mw.FormDataContentType()}})
-	if err != nil {
-		return err
-	}
-	defer resp.Body.Close()
-	if out == nil {
-		io.Copy(io.Discard, resp.Body)
-		return nil
-	}
-	return json.NewDecoder(resp.Body).Decode(out)
-}
-
-// Tipos de resposta mínimos conforme OpenAPI
-type GenericResponse struct {
-	Code    string      `json:"code"`
-	Message string      `json:"message"`
-	Results interface{} `json:"results"`
-}
-
-type LoginResponse struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Results struct {
-		QRDuration int    `json:"qr_duration"`
-		QRLink     string `json:"qr_link"`
-	} `json:"results"`
-}
-
-type LoginWithCodeResponse struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Results struct {
-		PairCode string `json:"pair_code"`
-	} `json:"results"`
-}
-
-type SendResponse struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Results struct {
-		MessageID string `json:"message_id"`
-		Status    string `json:"status"`
-	} `json:"results"`
-}
-
-type UserInfoResponse struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Results struct {
-		VerifiedName string `json:"verified_name"`
-		Status       string `json:"status"`
-		PictureID    string `json:"picture_id"`
-	} `json:"results"`
-}
-
-type ChatListResponse struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Results struct {
-		Data []struct {
-			JID             string `json:"jid"`
-			Name            string `json:"name"`
-			LastMessageTime string `json:"last_message_time"`
-			EphemeralExpire int    `json:"ephemeral_expiration"`
-		} `json:"data"`
-	} `json:"results"`
-}
-
-type ChatMessagesResponse struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Results struct {
-		Data []struct {
-			ID        string  `json:"id"`
-			ChatJID   string  `json:"chat_jid"`
-			SenderJID string  `json:"sender_jid"`
-			Content   string  `json:"content"`
-			Timestamp string  `json:"timestamp"`
-			IsFromMe  bool    `json:"is_from_me"`
-			MediaType *string `json:"media_type"`
-		} `json:"data"`
-	} `json:"results"`
+	return pr, mw.FormDataContentType()
+}
+
+// Tipos de resposta conforme OpenAPI; todos compartilham o envelope Response.
+type GenericResponse = Response[any]
+
+type LoginResult struct {
+	QRDuration int    `json:"qr_duration"`
+	QRLink     string `json:"qr_link"`
+}
+
+type LoginResponse = Response[LoginResult]
+
+type LoginWithCodeResult struct {
+	PairCode string `json:"pair_code"`
+}
+
+type LoginWithCodeResponse = Response[LoginWithCodeResult]
+
+type SendResult struct {
+	MessageID string `json:"message_id"`
+	Status    string `json:"status"`
+}
+
+type SendResponse = Response[SendResult]
+
+type Device struct {
+	Name   string `json:"name"`
+	Device string `json:"device"`
+}
+
+type DeviceResponse = Response[[]Device]
+
+type UserInfoResult struct {
+	VerifiedName string       `json:"verified_name"`
+	Status       string       `json:"status"`
+	PictureID    string       `json:"picture_id"`
+	Devices      []UserDevice `json:"devices"`
+}
+
+type UserInfoResponse = Response[UserInfoResult]
+
+type UserDevice struct {
+	User   string `json:"User"`
+	Agent  int    `json:"Agent"`
+	Device string `json:"Device"`
+	Server string `json:"Server"`
+	AD     bool   `json:"AD"`
+}
+
+type Pagination struct {
+	Limit  int `json:"limit"`
+	Offset int `json:"offset"`
+	Total  int `json:"total"`
+}
+
+type Chat struct {
+	JID             string `json:"jid"`
+	Name            string `json:"name"`
+	LastMessageTime string `json:"last_message_time"`
+	EphemeralExpire int    `json:"ephemeral_expiration"`
+	CreatedAt       string `json:"created_at,omitempty"`
+	UpdatedAt       string `json:"updated_at,omitempty"`
 }
 
+type ChatListResult struct {
+	Data       []Chat     `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+type ChatListResponse = Response[ChatListResult]
+
+type ChatMessage struct {
+	ID         string  `json:"id"`
+	ChatJID    string  `json:"chat_jid"`
+	SenderJID  string  `json:"sender_jid"`
+	Content    string  `json:"content"`
+	Timestamp  string  `json:"timestamp"`
+	IsFromMe   bool    `json:"is_from_me"`
+	MediaType  *string `json:"media_type"`
+	Filename   *string `json:"filename,omitempty"`
+	URL        *string `json:"url,omitempty"`
+	FileLength *int64  `json:"file_length,omitempty"`
+	CreatedAt  string  `json:"created_at,omitempty"`
+	UpdatedAt  string  `json:"updated_at,omitempty"`
+}
+
+type ChatMessagesResult struct {
+	Data       []ChatMessage `json:"data"`
+	Pagination Pagination    `json:"pagination"`
+	ChatInfo   *Chat         `json:"chat_info,omitempty"`
+}
+
+type ChatMessagesResponse = Response[ChatMessagesResult]
+
 // Métodos de alto nível inteligentes
 // Parâmetros para envio de mensagem de texto
 type SendTextParams struct {
@@ -255,90 +481,73 @@ type SendTextParams struct {
 }
 
 // Envia uma mensagem de texto usando SendTextParams
-func (c *Client) SendTextMessage(ctx context.Context, p SendTextParams) (*SendResponse, error) {
+func (c *Client) SendTextMessage(ctx context.Context, p SendTextParams, opts ...SendOption) (*SendResponse, error) {
 	if strings.TrimSpace(p.Phone) == "" || strings.TrimSpace(p.Message) == "" {
 		return nil, errors.New("phone e message são obrigatórios")
 	}
-	payload := map[string]any{
-		"phone":   p.Phone,
-		"message": p.Message,
-	}
-	if p.ReplyMessageID != "" {
-		payload["reply_message_id"] = p.ReplyMessageID
+	req := SendMessageRequest{
+		Phone:          p.Phone,
+		Message:        p.Message,
+		ReplyMessageID: p.ReplyMessageID,
+		IsForwarded:    p.IsForwarded,
+		Duration:       max(p.Duration, 0),
 	}
-	if p.IsForwarded {
-		payload["is_forwarded"] = true
-	}
-	if p.Duration > 0 {
-		payload["duration"] = p.Duration
-	}
-	var out SendResponse
-	if err := c.postJSON(ctx, "/send/message", payload, &out); err != nil {
+	if err := applySendOptions(messageFields(&req), opts); err != nil {
 		return nil, err
 	}
-	return &out, nil
+	return c.Raw().SendMessage(ctx, req)
 }
 func (c *Client) Login(ctx context.Context) (*LoginResponse, error) {
-	var out LoginResponse
-	if err := c.getJSON(ctx, "/app/login", nil, &out); err != nil {
-		return nil, err
-	}
-	return &out, nil
+	return c.Raw().AppLogin(ctx)
 }
 
 func (c *Client) LoginWithCode(ctx context.Context, phone string) (*LoginWithCodeResponse, error) {
 	if strings.TrimSpace(phone) == "" {
 		return nil, errors.New("phone is required")
 	}
-	q := url.Values{"phone": []string{phone}}
-	var out LoginWithCodeResponse
-	if err := c.getJSON(ctx, "/app/login-with-code", q, &out); err != nil {
-		return nil, err
-	}
-	return &out, nil
+	return c.Raw().AppLoginWithCode(ctx, AppLoginWithCodeRequest{Phone: phone})
 }
 
-func (c *Client) Logout(ctx context.Context) error {
-	var out GenericResponse
-	if err := c.getJSON(ctx, "/app/logout", nil, &out); err != nil {
-		return err
-	}
-	return nil
+// Logout remove a sessão no servidor. O envelope retornado traz a mensagem do
+// servidor (ex.: aviso de que já não havia sessão).
+func (c *Client) Logout(ctx context.Context) (*GenericResponse, error) {
+	return c.Raw().AppLogout(ctx)
 }
 
-func (c *Client) Reconnect(ctx context.Context) error {
-	var out GenericResponse
-	if err := c.getJSON(ctx, "/app/reconnect", nil, &out); err != nil {
-		return err
+func (c *Client) Reconnect(ctx context.Context) (*GenericResponse, error) {
+	return c.Raw().AppReconnect(ctx)
+}
+
+// LeaveGroup sai do grupo informado. Como Logout e Reconnect, retorna o
+// envelope do servidor para que avisos fiquem visíveis em Warning.
+func (c *Client) LeaveGroup(ctx context.Context, groupJID string) (*GenericResponse, error) {
+	if strings.TrimSpace(groupJID) == "" {
+		return nil, errors.New("groupJID is required")
 	}
-	return nil
+	return c.Raw().LeaveGroup(ctx, LeaveGroupRequest{GroupID: groupJID})
+}
+
+// Lista os dispositivos conectados; vazio enquanto a sessão não está logada.
+func (c *Client) Devices(ctx context.Context) (*DeviceResponse, error) {
+	return c.Raw().AppDevices(ctx)
 }
 
 func (c *Client) UserInfo(ctx context.Context, phoneJID string) (*UserInfoResponse, error) {
 	if strings.TrimSpace(phoneJID) == "" {
 		return nil, errors.New("phoneJID is required")
 	}
-	q := url.Values{"phone": []string{phoneJID}}
-	var out UserInfoResponse
-	if err := c.getJSON(ctx, "/user/info", q, &out); err != nil {
-		return nil, err
-	}
-	return &out, nil
+	return c.Raw().UserInfo(ctx, UserInfoRequest{Phone: phoneJID})
 }
 
-func (c *Client) SendPresence(ctx context.Context, presenceType string, opts ...func(*map[string]any)) (*SendResponse, error) {
+func (c *Client) SendPresence(ctx context.Context, presenceType string, opts ...SendOption) (*SendResponse, error) {
 	if presenceType != "available" && presenceType != "unavailable" {
 		return nil, errors.New("presenceType must be 'available' or 'unavailable'")
 	}
-	payload := map[string]any{"type": presenceType}
-	for _, o := range opts {
-		o(&payload)
-	}
-	var out SendResponse
-	if err := c.postJSON(ctx, "/send/presence", payload, &out); err != nil {
+	req := SendPresenceRequest{Type: presenceType}
+	if err := applySendOptions(sendFields{endpoint: "/send/presence", forwarded: &req.IsForwarded}, opts); err != nil {
 		return nil, err
 	}
-	return &out, nil
+	return c.Raw().SendPresence(ctx, req)
 }
 
 type ListChatsParams struct {
@@ -349,24 +558,12 @@ type ListChatsParams struct {
 }
 
 func (c *Client) ListChats(ctx context.Context, p ListChatsParams) (*ChatListResponse, error) {
-	q := url.Values{}
-	if p.Limit > 0 {
-		q.Set("limit", fmt.Sprint(p.Limit))
-	}
-	if p.Offset > 0 {
-		q.Set("offset", fmt.Sprint(p.Offset))
-	}
-	if p.Search != "" {
-		q.Set("search", p.Search)
-	}
-	if p.HasMedia != nil {
-		q.Set("has_media", fmt.Sprint(*p.HasMedia))
-	}
-	var out ChatListResponse
-	if err := c.getJSON(ctx, "/chats", q, &out); err != nil {
-		return nil, err
-	}
-	return &out, nil
+	return c.Raw().ListChats(ctx, ListChatsRequest{
+		Limit:    max(p.Limit, 0),
+		Offset:   max(p.Offset, 0),
+		Search:   p.Search,
+		HasMedia: p.HasMedia,
+	})
 }
 
 type GetChatMessagesParams struct {
@@ -383,110 +580,93 @@ func (c *Client) GetChatMessages(ctx context.Context, chatJID string, p GetChatM
 	if chatJID == "" {
 		return nil, errors.New("chatJID is required")
 	}
-	q := url.Values{}
-	if p.Limit > 0 {
-		q.Set("limit", fmt.Sprint(p.Limit))
-	}
-	if p.Offset > 0 {
-		q.Set("offset", fmt.Sprint(p.Offset))
-	}
-	if p.StartTime != "" {
-		q.Set("start_time", p.StartTime)
-	}
-	if p.EndTime != "" {
-		q.Set("end_time", p.EndTime)
-	}
-	if p.MediaOnly != nil {
-		q.Set("media_only", fmt.Sprint(*p.MediaOnly))
-	}
-	if p.IsFromMe != nil {
-		q.Set("is_from_me", fmt.Sprint(*p.IsFromMe))
-	}
-	if p.Search != "" {
-		q.Set("search", p.Search)
+	return c.Raw().GetChatMessages(ctx, GetChatMessagesRequest{
+		ChatJID:   chatJID,
+		Limit:     max(p.Limit, 0),
+		Offset:    max(p.Offset, 0),
+		StartTime: p.StartTime,
+		EndTime:   p.EndTime,
+		MediaOnly: p.MediaOnly,
+		IsFromMe:  p.IsFromMe,
+		Search:    p.Search,
+	})
+}
+
+// allChats percorre todas as páginas de ListChats.
+func (c *Client) allChats(ctx context.Context, pageSize int) ([]Chat, error) {
+	p := ListChatsParams{Limit: pageSize}
+	var all []Chat
+	for {
+		resp, err := c.ListChats(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		data := resp.Results.Data
+		all = append(all, data...)
+		p.Offset += len(data)
+		if len(data) < p.Limit || (resp.Results.Pagination.Total > 0 && p.Offset >= resp.Results.Pagination.Total) {
+			return all, nil
+		}
 	}
-	var out ChatMessagesResponse
-	path := "/chat/" + url.PathEscape(chatJID) + "/messages"
-	if err := c.getJSON(ctx, path, q, &out); err != nil {
-		return nil, err
+}
+
+// allChatMessages percorre todas as páginas de GetChatMessages a partir de p.
+func (c *Client) allChatMessages(ctx context.Context, chatJID string, p GetChatMessagesParams) ([]ChatMessage, error) {
+	var all []ChatMessage
+	for {
+		resp, err := c.GetChatMessages(ctx, chatJID, p)
+		if err != nil {
+			return nil, err
+		}
+		data := resp.Results.Data
+		all = append(all, data...)
+		p.Offset += len(data)
+		if len(data) < p.Limit || (resp.Results.Pagination.Total > 0 && p.Offset >= resp.Results.Pagination.Total) {
+			return all, nil
+		}
 	}
-	return &out, nil
 }
 
-func (c *Client) SendMessage(ctx context.Context, phone, message string, opts ...func(*map[string]any)) (*SendResponse, error) {
+func (c *Client) SendMessage(ctx context.Context, phone, message string, opts ...SendOption) (*SendResponse, error) {
 	if strings.TrimSpace(phone) == "" || strings.TrimSpace(message) == "" {
 		return nil, errors.New("phone and message are required")
 	}
-	payload := map[string]any{
-		"phone":   phone,
-		"message": message,
-	}
-	for _, o := range opts {
-		o(&payload)
-	}
-	var out SendResponse
-	if err := c.postJSON(ctx, "/send/message", payload, &out); err != nil {
+	req := SendMessageRequest{Phone: phone, Message: message}
+	if err := applySendOptions(messageFields(&req), opts); err != nil {
 		return nil, err
 	}
-	return &out, nil
-}
-
-func WithReplyMessageID(id string) func(*map[string]any) {
-	return func(m *map[string]any) { (*m)["reply_message_id"] = id }
+	return c.Raw().SendMessage(ctx, req)
 }
 
-func WithForwarded(forwarded bool) func(*map[string]any) {
-	return func(m *map[string]any) { (*m)["is_forwarded"] = forwarded }
-}
-
-func WithDisappearingDuration(seconds int) func(*map[string]any) {
-	return func(m *map[string]any) { (*m)["duration"] = seconds }
+func messageFields(req *SendMessageRequest) sendFields {
+	return sendFields{endpoint: "/send/message", replyMessageID: &req.ReplyMessageID, forwarded: &req.IsForwarded, duration: &req.Duration}
 }
 
 // Envio de imagem por arquivo local (ou use ImageURL)
-func (c *Client) SendImageFile(ctx context.Context, phone, caption, filePath string, viewOnce, compress bool, opts ...func(*map[string]string)) (*SendResponse, error) {
+func (c *Client) SendImageFile(ctx context.Context, phone, caption, filePath string, viewOnce, compress bool, opts ...SendOption) (*SendResponse, error) {
 	if phone == "" || filePath == "" {
 		return nil, errors.New("phone and filePath are required")
 	}
-	fields := map[string]string{
-		"phone":     phone,
-		"caption":   caption,
-		"view_once": fmt.Sprint(viewOnce),
-		"compress":  fmt.Sprint(compress),
-	}
-	for _, o := range opts {
-		o(&fields)
-	}
-	var out SendResponse
-	if err := c.postFormFile(ctx, "/send/image", fields, "image", filePath, &out); err != nil {
+	req := SendImageRequest{Phone: phone, Caption: caption, Image: filePath, ViewOnce: viewOnce, Compress: compress}
+	if err := applySendOptions(imageFields(&req), opts); err != nil {
 		return nil, err
 	}
-	return &out, nil
-}
-
-func WithDurationStr(seconds int) func(*map[string]string) {
-	return func(m *map[string]string) { (*m)["duration"] = fmt.Sprint(seconds) }
+	return c.Raw().SendImage(ctx, req)
 }
 
-func (c *Client) SendImageURL(ctx context.Context, phone, caption, imageURL string, viewOnce, compress bool, opts ...func(*map[string]any)) (*SendResponse, error) {
+func (c *Client) SendImageURL(ctx context.Context, phone, caption, imageURL string, viewOnce, compress bool, opts ...SendOption) (*SendResponse, error) {
 	if phone == "" || imageURL == "" {
 		return nil, errors.New("phone and imageURL are required")
 	}
-	payload := map[string]any{
-		"phone":     phone,
-		"caption":   caption,
-		"view_once": viewOnce,
-		"compress":  compress,
-		"image_url": imageURL,
-	}
-	for _, o := range opts {
-		o(&payload)
-	}
-	var out SendResponse
-	if err := c.postJSON(ctx, "/send/image", payload, &out); err != nil {
+	req := SendImageRequest{Phone: phone, Caption: caption, ImageURL: imageURL, ViewOnce: viewOnce, Compress: compress}
+	if err := applySendOptions(imageFields(&req), opts); err != nil {
 		return nil, err
 	}
-	return &out, nil
+	return c.Raw().SendImage(ctx, req)
+}
+
+func imageFields(req *SendImageRequest) sendFields {
+	return sendFields{endpoint: "/send/image", forwarded: &req.IsForwarded, duration: &req.Duration, viewOnce: &req.ViewOnce, compress: &req.Compress}
 }
 
 type SendAudioParams struct {
@@ -497,42 +677,21 @@ type SendAudioParams struct {
 	Duration    int
 }
 
-func (c *Client) SendAudio(ctx context.Context, p SendAudioParams) (*SendResponse, error) {
+func (c *Client) SendAudio(ctx context.Context, p SendAudioParams, opts ...SendOption) (*SendResponse, error) {
 	if p.Phone == "" || (p.AudioPath == "" && p.AudioURL == "") {
 		return nil, errors.New("phone and audio required")
 	}
-	fields := map[string]string{
-		"phone":        p.Phone,
-		"is_forwarded": fmt.Sprint(p.IsForwarded),
-	}
-	if p.Duration > 0 {
-		fields["duration"] = fmt.Sprint(p.Duration)
-	}
-	if p.AudioURL != "" {
-		fields["audio_url"] = p.AudioURL
-	}
-	var out SendResponse
-	fileField := "audio"
-	filePath := p.AudioPath
-	if filePath != "" {
-		if err := c.postFormFile(ctx, "/send/audio", fields, fileField, filePath, &out); err != nil {
-			return nil, err
-		}
-		return &out, nil
-	}
-	// se só url, manda como json
-	payload := map[string]any{
-		"phone":        p.Phone,
-		"audio_url":    p.AudioURL,
-		"is_forwarded": p.IsForwarded,
-	}
-	if p.Duration > 0 {
-		payload["duration"] = p.Duration
+	req := SendAudioRequest{
+		Phone:       p.Phone,
+		Audio:       p.AudioPath,
+		AudioURL:    p.AudioURL,
+		IsForwarded: p.IsForwarded,
+		Duration:    max(p.Duration, 0),
 	}
-	if err := c.postJSON(ctx, "/send/audio", payload, &out); err != nil {
+	if err := applySendOptions(sendFields{endpoint: "/send/audio", forwarded: &req.IsForwarded, duration: &req.Duration}, opts); err != nil {
 		return nil, err
 	}
-	return &out, nil
+	return c.Raw().SendAudio(ctx, req)
 }
 
 type SendFileParams struct {
@@ -543,23 +702,21 @@ type SendFileParams struct {
 	Duration    int
 }
 
-func (c *Client) SendFile(ctx context.Context, p SendFileParams) (*SendResponse, error) {
+func (c *Client) SendFile(ctx context.Context, p SendFileParams, opts ...SendOption) (*SendResponse, error) {
 	if p.Phone == "" || p.FilePath == "" {
 		return nil, errors.New("phone and filePath required")
 	}
-	fields := map[string]string{
-		"phone":        p.Phone,
-		"caption":      p.Caption,
-		"is_forwarded": fmt.Sprint(p.IsForwarded),
+	req := SendFileRequest{
+		Phone:       p.Phone,
+		Caption:     p.Caption,
+		File:        p.FilePath,
+		IsForwarded: p.IsForwarded,
+		Duration:    max(p.Duration, 0),
 	}
-	if p.Duration > 0 {
-		fields["duration"] = fmt.Sprint(p.Duration)
-	}
-	var out SendResponse
-	if err := c.postFormFile(ctx, "/send/file", fields, "file", p.FilePath, &out); err != nil {
+	if err := applySendOptions(sendFields{endpoint: "/send/file", forwarded: &req.IsForwarded, duration: &req.Duration}, opts); err != nil {
 		return nil, err
 	}
-	return &out, nil
+	return c.Raw().SendFile(ctx, req)
 }
 
 type SendVideoParams struct {
@@ -573,48 +730,24 @@ type SendVideoParams struct {
 	Duration    int
 }
 
-func (c *Client) SendVideo(ctx context.Context, p SendVideoParams) (*SendResponse, error) {
+func (c *Client) SendVideo(ctx context.Context, p SendVideoParams, opts ...SendOption) (*SendResponse, error) {
 	if p.Phone == "" || (p.VideoPath == "" && p.VideoURL == "") {
 		return nil, errors.New("phone and video required")
 	}
-	fields := map[string]string{
-		"phone":        p.Phone,
-		"caption":      p.Caption,
-		"view_once":    fmt.Sprint(p.ViewOnce),
-		"compress":     fmt.Sprint(p.Compress),
-		"is_forwarded": fmt.Sprint(p.IsForwarded),
-	}
-	if p.Duration > 0 {
-		fields["duration"] = fmt.Sprint(p.Duration)
-	}
-	if p.VideoURL != "" {
-		fields["video_url"] = p.VideoURL
-	}
-	var out SendResponse
-	fileField := "video"
-	filePath := p.VideoPath
-	if filePath != "" {
-		if err := c.postFormFile(ctx, "/send/video", fields, fileField, filePath, &out); err != nil {
-			return nil, err
-		}
-		return &out, nil
-	}
-	// se só url, manda como json
-	payload := map[string]any{
-		"phone":        p.Phone,
-		"caption":      p.Caption,
-		"view_once":    p.ViewOnce,
-		"compress":     p.Compress,
-		"video_url":    p.VideoURL,
-		"is_forwarded": p.IsForwarded,
-	}
-	if p.Duration > 0 {
-		payload["duration"] = p.Duration
-	}
-	if err := c.postJSON(ctx, "/send/video", payload, &out); err != nil {
+	req := SendVideoRequest{
+		Phone:       p.Phone,
+		Caption:     p.Caption,
+		ViewOnce:    p.ViewOnce,
+		Video:       p.VideoPath,
+		VideoURL:    p.VideoURL,
+		Compress:    p.Compress,
+		Duration:    max(p.Duration, 0),
+		IsForwarded: p.IsForwarded,
+	}
+	if err := applySendOptions(sendFields{endpoint: "/send/video", forwarded: &req.IsForwarded, duration: &req.Duration, viewOnce: &req.ViewOnce, compress: &req.Compress}, opts); err != nil {
 		return nil, err
 	}
-	return &out, nil
+	return c.Raw().SendVideo(ctx, req)
 }
 
 type SendContactParams struct {
@@ -625,24 +758,21 @@ type SendContactParams struct {
 	Duration     int
 }
 
-func (c *Client) SendContact(ctx context.Context, p SendContactParams) (*SendResponse, error) {
+func (c *Client) SendContact(ctx context.Context, p SendContactParams, opts ...SendOption) (*SendResponse, error) {
 	if p.Phone == "" || p.ContactName == "" || p.ContactPhone == "" {
 		return nil, errors.New("phone, contactName, contactPhone required")
 	}
-	payload := map[string]any{
-		"phone":         p.Phone,
-		"contact_name":  p.ContactName,
-		"contact_phone": p.ContactPhone,
-		"is_forwarded":  p.IsForwarded,
+	req := SendContactRequest{
+		Phone:        p.Phone,
+		ContactName:  p.ContactName,
+		ContactPhone: p.ContactPhone,
+		IsForwarded:  p.IsForwarded,
+		Duration:     max(p.Duration, 0),
 	}
-	if p.Duration > 0 {
-		payload["duration"] = p.Duration
-	}
-	var out SendResponse
-	if err := c.postJSON(ctx, "/send/contact", payload, &out); err != nil {
+	if err := applySendOptions(sendFields{endpoint: "/send/contact", forwarded: &req.IsForwarded, duration: &req.Duration}, opts); err != nil {
 		return nil, err
 	}
-	return &out, nil
+	return c.Raw().SendContact(ctx, req)
 }
 
 type SendLinkParams struct {
@@ -653,52 +783,64 @@ type SendLinkParams struct {
 	Duration    int
 }
 
-func (c *Client) SendLink(ctx context.Context, p SendLinkParams) (*SendResponse, error) {
+func (c *Client) SendLink(ctx context.Context, p SendLinkParams, opts ...SendOption) (*SendResponse, error) {
 	if p.Phone == "" || p.Link == "" {
 		return nil, errors.New("phone and link required")
 	}
-	payload := map[string]any{
-		"phone":        p.Phone,
-		"link":         p.Link,
-		"caption":      p.Caption,
-		"is_forwarded": p.IsForwarded,
-	}
-	if p.Duration > 0 {
-		payload["duration"] = p.Duration
+	req := SendLinkRequest{
+		Phone:       p.Phone,
+		Link:        p.Link,
+		Caption:     p.Caption,
+		IsForwarded: p.IsForwarded,
+		Duration:    max(p.Duration, 0),
 	}
-	var out SendResponse
-	if err := c.postJSON(ctx, "/send/link", payload, &out); err != nil {
+	if err := applySendOptions(sendFields{endpoint: "/send/link", forwarded: &req.IsForwarded, duration: &req.Duration}, opts); err != nil {
 		return nil, err
 	}
-	return &out, nil
+	return c.Raw().SendLink(ctx, req)
 }
 
 type SendLocationParams struct {
-	Phone       string
+	Phone string
+	// Location tem precedência sobre Latitude/Longitude.
+	Location *Location
+	// Latitude e Longitude em texto, com ponto decimal; são validadas antes
+	// do envio ("-23,55" é recusado).
 	Latitude    string
 	Longitude   string
 	IsForwarded bool
 	Duration    int
 }
 
-func (c *Client) SendLocation(ctx context.Context, p SendLocationParams) (*SendResponse, error) {
-	if p.Phone == "" || p.Latitude == "" || p.Longitude == "" {
-		return nil, errors.New("phone, latitude, longitude required")
+func (c *Client) SendLocation(ctx context.Context, p SendLocationParams, opts ...SendOption) (*SendResponse, error) {
+	loc := p.Location
+	if loc == nil {
+		if p.Phone == "" || p.Latitude == "" || p.Longitude == "" {
+			return nil, errors.New("phone, latitude, longitude required")
+		}
+		l, err := parseLatLng(p.Latitude, p.Longitude)
+		if err != nil {
+			return nil, err
+		}
+		loc = &l
+	}
+	if p.Phone == "" {
+		return nil, errors.New("phone required")
 	}
-	payload := map[string]any{
-		"phone":        p.Phone,
-		"latitude":     p.Latitude,
-		"longitude":    p.Longitude,
-		"is_forwarded": p.IsForwarded,
+	if err := loc.Validate(); err != nil {
+		return nil, err
 	}
-	if p.Duration > 0 {
-		payload["duration"] = p.Duration
+	req := SendLocationRequest{
+		Phone:       p.Phone,
+		Latitude:    formatCoord(loc.Latitude),
+		Longitude:   formatCoord(loc.Longitude),
+		IsForwarded: p.IsForwarded,
+		Duration:    max(p.Duration, 0),
 	}
-	var out SendResponse
-	if err := c.postJSON(ctx, "/send/location", payload, &out); err != nil {
+	if err := applySendOptions(sendFields{endpoint: "/send/location", forwarded: &req.IsForwarded, duration: &req.Duration}, opts); err != nil {
 		return nil, err
 	}
-	return &out, nil
+	return c.Raw().SendLocation(ctx, req)
 }
 
 type SendPollParams struct {
@@ -709,24 +851,21 @@ type SendPollParams struct {
 	Duration  int
 }
 
-func (c *Client) SendPoll(ctx context.Context, p SendPollParams) (*SendResponse, error) {
+func (c *Client) SendPoll(ctx context.Context, p SendPollParams, opts ...SendOption) (*SendResponse, error) {
 	if p.Phone == "" || p.Question == "" || len(p.Options) == 0 || p.MaxAnswer == 0 {
 		return nil, errors.New("phone, question, options, maxAnswer required")
 	}
-	payload := map[string]any{
-		"phone":      p.Phone,
-		"question":   p.Question,
-		"options":    p.Options,
-		"max_answer": p.MaxAnswer,
-	}
-	if p.Duration > 0 {
-		payload["duration"] = p.Duration
+	req := SendPollRequest{
+		Phone:     p.Phone,
+		Question:  p.Question,
+		Options:   p.Options,
+		MaxAnswer: p.MaxAnswer,
+		Duration:  max(p.Duration, 0),
 	}
-	var out SendResponse
-	if err := c.postJSON(ctx, "/send/poll", payload, &out); err != nil {
+	if err := applySendOptions(sendFields{endpoint: "/send/poll", duration: &req.Duration}, opts); err != nil {
 		return nil, err
 	}
-	return &out, nil
+	return c.Raw().SendPoll(ctx, req)
 }
 
 type SendChatPresenceParams struct {
@@ -738,15 +877,7 @@ func (c *Client) SendChatPresence(ctx context.Context, p SendChatPresenceParams)
 	if p.Phone == "" || (p.Action != "start" && p.Action != "stop") {
 		return nil, errors.New("phone and action=start|stop required")
 	}
-	payload := map[string]any{
-		"phone":  p.Phone,
-		"action": p.Action,
-	}
-	var out SendResponse
-	if err := c.postJSON(ctx, "/send/chat-presence", payload, &out); err != nil {
-		return nil, err
-	}
-	return &out, nil
+	return c.Raw().SendChatPresence(ctx, SendChatPresenceRequest{Phone: p.Phone, Action: p.Action})
 }
 
 type MessageActionParams struct {
@@ -760,89 +891,47 @@ func (c *Client) RevokeMessage(ctx context.Context, p MessageActionParams) (*Sen
 	if p.MessageID == "" || p.Phone == "" {
 		return nil, errors.New("messageID and phone required")
 	}
-	payload := map[string]any{"phone": p.Phone}
-	var out SendResponse
-	path := "/message/" + url.PathEscape(p.MessageID) + "/revoke"
-	if err := c.postJSON(ctx, path, payload, &out); err != nil {
-		return nil, err
-	}
-	return &out, nil
+	return c.Raw().RevokeMessage(ctx, RevokeMessageRequest{MessageID: p.MessageID, Phone: p.Phone})
 }
 
 func (c *Client) DeleteMessage(ctx context.Context, p MessageActionParams) (*SendResponse, error) {
 	if p.MessageID == "" || p.Phone == "" {
 		return nil, errors.New("messageID and phone required")
 	}
-	payload := map[string]any{"phone": p.Phone}
-	var out SendResponse
-	path := "/message/" + url.PathEscape(p.MessageID) + "/delete"
-	if err := c.postJSON(ctx, path, payload, &out); err != nil {
-		return nil, err
-	}
-	return &out, nil
+	return c.Raw().DeleteMessage(ctx, DeleteMessageRequest{MessageID: p.MessageID, Phone: p.Phone})
 }
 
 func (c *Client) ReactMessage(ctx context.Context, p MessageActionParams) (*SendResponse, error) {
 	if p.MessageID == "" || p.Phone == "" || p.Emoji == "" {
 		return nil, errors.New("messageID, phone, emoji required")
 	}
-	payload := map[string]any{"phone": p.Phone, "emoji": p.Emoji}
-	var out SendResponse
-	path := "/message/" + url.PathEscape(p.MessageID) + "/reaction"
-	if err := c.postJSON(ctx, path, payload, &out); err != nil {
-		return nil, err
-	}
-	return &out, nil
+	return c.Raw().ReactMessage(ctx, ReactMessageRequest{MessageID: p.MessageID, Phone: p.Phone, Emoji: p.Emoji})
 }
 
 func (c *Client) UpdateMessage(ctx context.Context, p MessageActionParams) (*SendResponse, error) {
 	if p.MessageID == "" || p.Phone == "" || p.Message == "" {
 		return nil, errors.New("messageID, phone, message required")
 	}
-	payload := map[string]any{"phone": p.Phone, "message": p.Message}
-	var out SendResponse
-	path := "/message/" + url.PathEscape(p.MessageID) + "/update"
-	if err := c.postJSON(ctx, path, payload, &out); err != nil {
-		return nil, err
-	}
-	return &out, nil
+	return c.Raw().UpdateMessage(ctx, UpdateMessageRequest{MessageID: p.MessageID, Phone: p.Phone, Message: p.Message})
 }
 
 func (c *Client) ReadMessage(ctx context.Context, p MessageActionParams) (*SendResponse, error) {
 	if p.MessageID == "" || p.Phone == "" {
 		return nil, errors.New("messageID and phone required")
 	}
-	payload := map[string]any{"phone": p.Phone}
-	var out SendResponse
-	path := "/message/" + url.PathEscape(p.MessageID) + "/read"
-	if err := c.postJSON(ctx, path, payload, &out); err != nil {
-		return nil, err
-	}
-	return &out, nil
+	return c.Raw().ReadMessage(ctx, ReadMessageRequest{MessageID: p.MessageID, Phone: p.Phone})
 }
 
 func (c *Client) StarMessage(ctx context.Context, p MessageActionParams) (*GenericResponse, error) {
 	if p.MessageID == "" || p.Phone == "" {
 		return nil, errors.New("messageID and phone required")
 	}
-	payload := map[string]any{"phone": p.Phone}
-	var out GenericResponse
-	path := "/message/" + url.PathEscape(p.MessageID) + "/star"
-	if err := c.postJSON(ctx, path, payload, &out); err != nil {
-		return nil, err
-	}
-	return &out, nil
+	return c.Raw().StarMessage(ctx, StarMessageRequest{MessageID: p.MessageID, Phone: p.Phone})
 }
 
 func (c *Client) UnstarMessage(ctx context.Context, p MessageActionParams) (*GenericResponse, error) {
 	if p.MessageID == "" || p.Phone == "" {
 		return nil, errors.New("messageID and phone required")
 	}
-	payload := map[string]any{"phone": p.Phone}
-	var out GenericResponse
-	path := "/message/" + url.PathEscape(p.MessageID) + "/unstar"
-	if err := c.postJSON(ctx, path, payload, &out); err != nil {
-		return nil, err
-	}
-	return &out, nil
+	return c.Raw().UnstarMessage(ctx, UnstarMessageRequest{MessageID: p.MessageID, Phone: p.Phone})
 }
@@ -0,0 +1,40 @@
+package gowa_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+func TestLeaveGroup(t *testing.T) {
+	ctx := context.Background()
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	gid := "120363025246125486@g.us"
+	srv.AddGroup(gowatest.Group{JID: gid, Name: "Equipe", Participants: []gowatest.Participant{{JID: srv.JID()}, {JID: "5511999990000@s.whatsapp.net"}}})
+	c := srv.GowaClient()
+
+	resp, err := c.LeaveGroup(ctx, gid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Success() || resp.Message == "" {
+		t.Errorf("envelope = %+v", resp)
+	}
+	g, _ := srv.Group(gid)
+	for _, p := range g.Participants {
+		if p.JID == srv.JID() {
+			t.Error("still a participant after LeaveGroup")
+		}
+	}
+	if _, err := c.LeaveGroup(ctx, " "); err == nil {
+		t.Error("empty group JID accepted")
+	}
+	if _, err := c.LeaveGroup(ctx, "999@g.us"); err == nil {
+		t.Error("unknown group accepted")
+	}
+	if _, ok := srv.LastRequest("/group/leave"); !ok {
+		t.Error("no request to /group/leave")
+	}
+}
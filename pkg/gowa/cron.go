@@ -0,0 +1,174 @@
+package gowa
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CronSchedule é uma expressão cron de 5 campos (minuto hora dia mês
+// dia-da-semana), com listas (1,15), intervalos (1-5), passos (*/10, 8-18/2),
+// nomes (JAN, MON) e os atalhos @hourly, @daily, @weekly, @monthly e @yearly.
+// Quando dia do mês e dia da semana são restritos, basta um casar (como no
+// cron tradicional).
+type CronSchedule struct {
+	expr                          string
+	minute, hour, dom, month, dow uint64
+	domAny, dowAny                bool
+}
+
+var cronMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var cronMonths = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var cronDays = map[string]int{"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6}
+
+// ParseCron interpreta uma expressão cron. O fuso não faz parte da expressão:
+// Next usa o fuso do instante recebido.
+func ParseCron(expr string) (*CronSchedule, error) {
+	spec := strings.TrimSpace(expr)
+	if m, ok := cronMacros[strings.ToLower(spec)]; ok {
+		spec = m
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("cron %q: expected 5 fields, got %d", expr, len(fields))
+	}
+	c := &CronSchedule{expr: expr}
+	var err error
+	if c.minute, err = parseCronField(fields[0], 0, 59, nil); err != nil {
+		return nil, fmt.Errorf("cron %q: minute: %w", expr, err)
+	}
+	if c.hour, err = parseCronField(fields[1], 0, 23, nil); err != nil {
+		return nil, fmt.Errorf("cron %q: hour: %w", expr, err)
+	}
+	if c.dom, err = parseCronField(fields[2], 1, 31, nil); err != nil {
+		return nil, fmt.Errorf("cron %q: day of month: %w", expr, err)
+	}
+	if c.month, err = parseCronField(fields[3], 1, 12, cronMonths); err != nil {
+		return nil, fmt.Errorf("cron %q: month: %w", expr, err)
+	}
+	if c.dow, err = parseCronField(fields[4], 0, 7, cronDays); err != nil {
+		return nil, fmt.Errorf("cron %q: day of week: %w", expr, err)
+	}
+	if c.dow&(1<<7) != 0 { // 7 também é domingo
+		c.dow |= 1
+	}
+	c.domAny, c.dowAny = fields[2] == "*" || fields[2] == "?", fields[4] == "*" || fields[4] == "?"
+	return c, nil
+}
+
+// MustParseCron é como ParseCron, mas entra em pânico com expressão inválida.
+func MustParseCron(expr string) *CronSchedule {
+	c, err := ParseCron(expr)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func (c *CronSchedule) String() string { return c.expr }
+
+func parseCronField(f string, lo, hi int, names map[string]int) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(f, ",") {
+		rng, stepStr, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepStr)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("invalid step %q", stepStr)
+			}
+			step = n
+		}
+		start, end := lo, hi
+		switch {
+		case rng == "*" || rng == "?":
+		default:
+			a, b, isRange := strings.Cut(rng, "-")
+			var err error
+			if start, err = cronValue(a, names); err != nil {
+				return 0, err
+			}
+			end = start
+			if isRange {
+				if end, err = cronValue(b, names); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				end = hi
+			}
+		}
+		if start < lo || end > hi || start > end {
+			return 0, fmt.Errorf("%q out of range %d-%d", part, lo, hi)
+		}
+		for v := start; v <= end; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func cronValue(s string, names map[string]int) (int, error) {
+	if v, ok := names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	return v, nil
+}
+
+func (c *CronSchedule) dayMatches(t time.Time) bool {
+	domOK := c.dom&(1<<uint(t.Day())) != 0
+	dowOK := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domAny || c.dowAny {
+		return domOK && dowOK
+	}
+	return domOK || dowOK
+}
+
+// Next retorna o primeiro instante estritamente posterior a after que casa
+// com a expressão, no fuso de after. Retorna o tempo zero se não houver
+// nenhum nos próximos 5 anos (ex.: 30 de fevereiro).
+func (c *CronSchedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Year() + 5
+	// advance garante progresso mesmo quando time.Date normaliza para trás
+	// (horário de verão).
+	advance := func(next time.Time, min time.Duration) {
+		if !next.After(t) {
+			next = t.Add(min)
+		}
+		t = next
+	}
+	for t.Year() <= limit {
+		switch {
+		case c.month&(1<<uint(t.Month())) == 0:
+			advance(time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc), 24*time.Hour)
+		case !c.dayMatches(t):
+			advance(time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc), time.Hour)
+		case c.hour&(1<<uint(t.Hour())) == 0:
+			advance(time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc), time.Minute)
+		case c.minute&(1<<uint(t.Minute())) == 0:
+			t = t.Add(time.Minute)
+		default:
+			return t
+		}
+	}
+	return time.Time{}
+}
@@ -0,0 +1,77 @@
+package gowa_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+)
+
+func TestParseCronErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"* * * FOO *",
+	} {
+		if _, err := gowa.ParseCron(expr); err == nil {
+			t.Errorf("ParseCron(%q): expected error", expr)
+		}
+	}
+}
+
+func TestCronNext(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skip("tzdata unavailable:", err)
+	}
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("tzdata unavailable:", err)
+	}
+	at := func(loc *time.Location, s string) time.Time {
+		t.Helper()
+		v, err := time.ParseInLocation("2006-01-02 15:04", s, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	tests := []struct {
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"*/15 * * * *", at(time.UTC, "2025-03-10 10:07"), at(time.UTC, "2025-03-10 10:15")},
+		{"*/15 * * * *", at(time.UTC, "2025-03-10 10:15"), at(time.UTC, "2025-03-10 10:30")},
+		{"0 9 * * MON-FRI", at(time.UTC, "2025-03-07 09:00"), at(time.UTC, "2025-03-10 09:00")}, // sexta → segunda
+		{"30 8-18/2 * * *", at(time.UTC, "2025-03-10 18:31"), at(time.UTC, "2025-03-11 08:30")},
+		{"0 0 1 JAN *", at(time.UTC, "2025-06-01 00:00"), at(time.UTC, "2026-01-01 00:00")},
+		{"@daily", at(time.UTC, "2025-03-10 23:59"), at(time.UTC, "2025-03-11 00:00")},
+		{"@hourly", at(time.UTC, "2025-03-10 10:00"), at(time.UTC, "2025-03-10 11:00")},
+		{"0 12 * * 7", at(time.UTC, "2025-03-10 00:00"), at(time.UTC, "2025-03-16 12:00")}, // 7 = domingo
+		// dia do mês e da semana restritos: basta um casar (dia 13 ou sexta)
+		{"0 0 13 * FRI", at(time.UTC, "2025-03-08 00:00"), at(time.UTC, "2025-03-13 00:00")},
+		{"0 0 13 * FRI", at(time.UTC, "2025-03-13 00:00"), at(time.UTC, "2025-03-14 00:00")},
+		{"0 0 29 2 *", at(time.UTC, "2025-01-01 00:00"), at(time.UTC, "2028-02-29 00:00")},
+		{"0 0 30 2 *", at(time.UTC, "2025-01-01 00:00"), time.Time{}},
+		// o fuso é o do instante recebido
+		{"0 9 * * *", at(saoPaulo, "2025-03-10 08:00"), at(saoPaulo, "2025-03-10 09:00")},
+		// 02:30 não existe no início do horário de verão em Nova York
+		{"30 2 * * *", at(newYork, "2025-03-08 03:00"), at(newYork, "2025-03-10 02:30")},
+	}
+	for _, tt := range tests {
+		got := gowa.MustParseCron(tt.expr).Next(tt.after)
+		if !got.Equal(tt.want) {
+			t.Errorf("Next(%q, %s) = %s, want %s", tt.expr, tt.after, got, tt.want)
+		}
+	}
+}
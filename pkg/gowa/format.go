@@ -0,0 +1,288 @@
+package gowa
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Style é uma combinação de estilos de texto do WhatsApp.
+type Style uint8
+
+const (
+	StyleBold      Style = 1 << iota // *texto*
+	StyleItalic                      // _texto_
+	StyleStrike                      // ~texto~
+	StyleMonospace                   // ```texto```
+	StyleCode                        // `texto` (código inline)
+)
+
+// zwsp é o caractere invisível usado por Escape para quebrar marcadores.
+const zwsp = "\u200b"
+
+// Text monta mensagens com a formatação do WhatsApp. Todo texto passado aos
+// métodos, exceto Raw, é escapado: um "*" digitado pelo usuário não vira
+// negrito.
+//
+//	msg := gowa.NewText().
+//		Styled("Pedido confirmado", gowa.StyleBold).Line().
+//		Plain("Cliente: ").Plain(nome).Line().
+//		Bullet("2x pizza", "1x refri").
+//		Mention(vendedorJID).Plain(", confira!").
+//		String()
+type Text struct {
+	b strings.Builder
+}
+
+func NewText() *Text { return &Text{} }
+
+// Plain acrescenta texto sem formatação.
+func (t *Text) Plain(s string) *Text {
+	t.b.WriteString(escapeFrom(s, t.atLineStart()))
+	return t
+}
+
+// Raw acrescenta s sem escapar, para trechos já formatados.
+func (t *Text) Raw(s string) *Text {
+	t.b.WriteString(s)
+	return t
+}
+
+// Styled acrescenta s com os estilos combinados, por exemplo StyleBold|StyleItalic.
+// Espaços nas pontas ficam fora dos marcadores, como o WhatsApp exige.
+func (t *Text) Styled(s string, st Style) *Text {
+	t.b.WriteString(styled(s, st, t.atLineStart()))
+	return t
+}
+
+func (t *Text) Bold(s string) *Text   { return t.Styled(s, StyleBold) }
+func (t *Text) Italic(s string) *Text { return t.Styled(s, StyleItalic) }
+func (t *Text) Strike(s string) *Text { return t.Styled(s, StyleStrike) }
+func (t *Text) Mono(s string) *Text   { return t.Styled(s, StyleMonospace) }
+func (t *Text) Code(s string) *Text   { return t.Styled(s, StyleCode) }
+
+// Mention acrescenta "@<número>" para o JID (ou número) informado. É só
+// texto: a API do gowa não recebe a lista de menções, então não há garantia
+// de que o WhatsApp destaque o número ou notifique o contato.
+func (t *Text) Mention(jid string) *Text {
+	if user := mentionUser(jid); user != "" {
+		t.b.WriteString("@" + user)
+	}
+	return t
+}
+
+// Line acrescenta uma quebra de linha.
+func (t *Text) Line() *Text {
+	t.b.WriteByte('\n')
+	return t
+}
+
+// Bullet acrescenta uma lista com marcadores, um item por linha.
+func (t *Text) Bullet(items ...string) *Text {
+	for _, it := range items {
+		t.b.WriteString("- " + escapeLine(it) + "\n")
+	}
+	return t
+}
+
+// Numbered acrescenta uma lista numerada a partir de 1.
+func (t *Text) Numbered(items ...string) *Text {
+	for i, it := range items {
+		t.b.WriteString(strconv.Itoa(i+1) + ". " + escapeLine(it) + "\n")
+	}
+	return t
+}
+
+// Quote acrescenta s como citação; cada linha recebe "> ".
+func (t *Text) Quote(s string) *Text {
+	for _, line := range strings.Split(s, "\n") {
+		t.b.WriteString("> " + escapeLine(line) + "\n")
+	}
+	return t
+}
+
+func (t *Text) String() string { return t.b.String() }
+
+// atLineStart diz se o próximo trecho começa uma linha (só espaços antes
+// dele), onde "> " e "- " viram citação e lista.
+func (t *Text) atLineStart() bool {
+	s := t.b.String()
+	return strings.TrimSpace(s[strings.LastIndexByte(s, '\n')+1:]) == ""
+}
+
+func styled(s string, st Style, lineStart bool) string {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" || st == 0 {
+		return escapeFrom(s, lineStart)
+	}
+	lead := s[:strings.Index(s, trimmed)]
+	trail := s[len(lead)+len(trimmed):]
+	// monoespaçado e código não interpretam outros marcadores: o conteúdo
+	// fica literal e os demais estilos envolvem o bloco
+	inner := escapeFrom(trimmed, false) // depois do marcador de estilo
+	switch {
+	case st&StyleMonospace != 0:
+		inner = "```" + strings.ReplaceAll(trimmed, "`", "`"+zwsp) + "```"
+	case st&StyleCode != 0:
+		inner = "`" + strings.ReplaceAll(trimmed, "`", "'") + "`"
+	}
+	for _, m := range []struct {
+		s Style
+		c string
+	}{{StyleStrike, "~"}, {StyleItalic, "_"}, {StyleBold, "*"}} {
+		if st&m.s != 0 {
+			inner = m.c + inner + m.c
+		}
+	}
+	return lead + inner + trail
+}
+
+var escapeReplacer = strings.NewReplacer(
+	"```", "`"+zwsp+"`"+zwsp+"`",
+	"*", "*"+zwsp,
+	"_", "_"+zwsp,
+	"~", "~"+zwsp,
+	"`", "`"+zwsp,
+)
+
+// Escape neutraliza os marcadores do WhatsApp (* _ ~ ` e ```) em texto vindo
+// do usuário, inserindo um caractere invisível (U+200B) depois de cada um. O
+// texto exibido não muda. URLs e e-mails ficam intactos, para o link
+// continuar funcionando. Citações e listas no início de linha também são
+// neutralizadas.
+func Escape(s string) string { return escapeFrom(s, true) }
+
+// escapeFrom é o Escape para um trecho que pode continuar uma linha já
+// começada (lineStart false): aí a primeira linha não é citação nem lista e
+// fica sem o U+200B inicial.
+func escapeFrom(s string, lineStart bool) string {
+	s = escapeMarkers(s)
+	if !strings.ContainsAny(s, ">-0123456789") {
+		return s
+	}
+	lines := strings.Split(s, "\n")
+	for i, l := range lines {
+		if (i > 0 || lineStart) && lineMarkerRe.MatchString(l) {
+			lines[i] = zwsp + l
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// linkRe casa URLs e e-mails. A URL não termina em marcador nem pontuação e o
+// e-mail começa e termina em letra ou dígito, para que "*veja x.com/a_b*" ou
+// "_a@b.com_" continuem com os marcadores de fora escapados.
+var linkRe = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S*[^\s*_~` + "`" + `.,;:!?)\]]|[a-z0-9][\w.+-]*@[a-z0-9-]+(?:\.[a-z0-9-]+)+`)
+
+// escapeMarkers aplica o escapeReplacer fora dos trechos de linkRe: o U+200B
+// dentro de uma URL ou e-mail quebraria o link.
+func escapeMarkers(s string) string {
+	locs := linkRe.FindAllStringIndex(s, -1)
+	if locs == nil {
+		return escapeReplacer.Replace(s)
+	}
+	var b strings.Builder
+	last := 0
+	for _, l := range locs {
+		b.WriteString(escapeReplacer.Replace(s[last:l[0]]))
+		b.WriteString(s[l[0]:l[1]])
+		last = l[1]
+	}
+	b.WriteString(escapeReplacer.Replace(s[last:]))
+	return b.String()
+}
+
+// lineMarkerRe casa linhas que o WhatsApp formata como citação ou lista.
+var lineMarkerRe = regexp.MustCompile(`^\s*(>|[-*]\s|\d+\.\s)`)
+
+// escapeLine escapa um item de lista ou citação, que fica numa linha só.
+func escapeLine(s string) string {
+	return strings.TrimPrefix(Escape(strings.ReplaceAll(s, "\n", " ")), zwsp)
+}
+
+func mentionUser(jid string) string {
+	user, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(jid), "@"), "@")
+	user, _, _ = strings.Cut(user, ":") // sufixo de dispositivo
+	return strings.TrimPrefix(user, "+")
+}
+
+var (
+	mdLinkRe    = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdBoldRe    = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|__(\S(?:.*?\S)?)__`)
+	mdItalicRe  = regexp.MustCompile(`\*(\S(?:[^*]*?\S)?)\*`)
+	mdStrikeRe  = regexp.MustCompile(`~~(\S(?:.*?\S)?)~~`)
+	mdCodeRe    = regexp.MustCompile("`([^`]+)`")
+	mdEscapeRe  = regexp.MustCompile(`\\([\\*_~` + "`" + `\[\]()#>-])`)
+	mdHeadingRe = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*\s*$`)
+	mdBulletRe  = regexp.MustCompile(`^(\s*)[*+-]\s+(.*)$`)
+	mdRuleRe    = regexp.MustCompile(`^\s*([-*_])(\s*([-*_])){2,}\s*$`)
+)
+
+// FromMarkdown converte um subconjunto de Markdown para a marcação do
+// WhatsApp: **negrito**/__negrito__, *itálico*/_itálico_, ~~riscado~~,
+// `código`, blocos ```, títulos (viram negrito), listas com -, * ou +,
+// listas numeradas, citações com > e links [texto](url) (viram
+// "texto (url)"). Barra invertida escapa um marcador.
+func FromMarkdown(md string) string {
+	var out []string
+	lines := strings.Split(strings.ReplaceAll(md, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			// bloco de código: conteúdo literal até a cerca de fechamento
+			var block []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), "```"); i++ {
+				block = append(block, lines[i])
+			}
+			out = append(out, "```"+strings.Join(block, "\n")+"```")
+			continue
+		}
+		switch {
+		case mdRuleRe.MatchString(line):
+			out = append(out, "")
+		case mdHeadingRe.MatchString(line):
+			out = append(out, "*"+mdInline(mdHeadingRe.FindStringSubmatch(line)[1], true)+"*")
+		case mdBulletRe.MatchString(line):
+			m := mdBulletRe.FindStringSubmatch(line)
+			out = append(out, m[1]+"- "+mdInline(m[2], false))
+		case strings.HasPrefix(strings.TrimSpace(line), ">"):
+			body := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), ">"))
+			out = append(out, "> "+mdInline(body, false))
+		default:
+			out = append(out, mdInline(line, false))
+		}
+	}
+	return strings.Join(out, "\n")
+}
+
+// mdInline converte a formatação dentro de uma linha. Trechos literais
+// (código e escapes) são trocados por marcadores \x00<n>\x00 durante a
+// conversão para não serem reinterpretados. Com inBold, negrito interno é
+// removido (o título inteiro já é negrito).
+func mdInline(s string, inBold bool) string {
+	var keep []string
+	hold := func(v string) string {
+		keep = append(keep, v)
+		return "\x00" + strconv.Itoa(len(keep)-1) + "\x00"
+	}
+	s = mdEscapeRe.ReplaceAllStringFunc(s, func(m string) string { return hold(Escape(m[1:])) })
+	s = mdCodeRe.ReplaceAllStringFunc(s, func(m string) string { return hold(m) })
+	s = mdLinkRe.ReplaceAllString(s, "$1 ($2)")
+
+	bold := "\x01"
+	if inBold {
+		bold = ""
+	}
+	s = mdBoldRe.ReplaceAllStringFunc(s, func(m string) string {
+		sub := mdBoldRe.FindStringSubmatch(m)
+		return bold + sub[1] + sub[2] + bold
+	})
+	s = mdItalicRe.ReplaceAllString(s, "_${1}_")
+	s = mdStrikeRe.ReplaceAllString(s, "~${1}~")
+	s = strings.ReplaceAll(s, "\x01", "*")
+
+	for i := len(keep) - 1; i >= 0; i-- {
+		s = strings.ReplaceAll(s, "\x00"+strconv.Itoa(i)+"\x00", keep[i])
+	}
+	return s
+}
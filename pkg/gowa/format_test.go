@@ -0,0 +1,82 @@
+package gowa_test
+
+import (
+	"testing"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+)
+
+func TestEscape(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"sem marcadores", "sem marcadores"},
+		{"2*3", "2*\u200b3"},
+		{"a_b~c`d", "a_\u200bb~\u200bc`\u200bd"},
+		{"```x```", "`\u200b`\u200b`x`\u200b`\u200b`"},
+		{"> citação", "\u200b> citação"},
+		{"- item", "\u200b- item"},
+		{"1. item", "\u200b1. item"},
+		{"linha\n- item", "linha\n\u200b- item"},
+		{"3 - 1", "3 - 1"},
+		// URLs e e-mails ficam intactos; os marcadores em volta, não
+		{"https://x.com/a_b", "https://x.com/a_b"},
+		{"veja https://x.com/a_b*c?q=~1 e *isso*", "veja https://x.com/a_b*c?q=~1 e *\u200bisso*\u200b"},
+		{"*www.site.com/x_y*", "*\u200bwww.site.com/x_y*\u200b"},
+		{"https://x.com/a_b.", "https://x.com/a_b."},
+		{"joao_silva@ex.com.br", "joao_silva@ex.com.br"},
+		{"_a_b@ex.com_", "_\u200ba_b@ex.com_\u200b"},
+	}
+	for _, tt := range tests {
+		if got := gowa.Escape(tt.in); got != tt.want {
+			t.Errorf("Escape(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestText(t *testing.T) {
+	got := gowa.NewText().
+		Styled("Pedido", gowa.StyleBold).Line().
+		Styled(" ok ", gowa.StyleBold|gowa.StyleItalic).Line().
+		Mono("a*b").Code("x`y").Line().
+		Bullet("um", "dois").
+		Numbered("três").
+		Quote("q1\nq2").
+		Plain("5*2").
+		Plain("> 3").Line().
+		Plain("- item").Plain(" - 4").Line().
+		Plain("Total: ").Plain("1. lugar").Styled(" > ", 0).
+		Mention("+5511999990000:3@s.whatsapp.net").
+		String()
+	want := "*Pedido*\n" +
+		" *_ok_* \n" +
+		"```a*b````x'y`\n" +
+		"- um\n- dois\n" +
+		"1. três\n" +
+		"> q1\n> q2\n" +
+		"5*\u200b2> 3\n" +
+		"\u200b- item - 4\n" +
+		"Total: 1. lugar > @5511999990000"
+	if got != want {
+		t.Errorf("Text = %q, want %q", got, want)
+	}
+}
+
+func TestFromMarkdown(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"**negrito** e __também__", "*negrito* e *também*"},
+		{"*itálico* e _também_", "_itálico_ e _também_"},
+		{"~~riscado~~", "~riscado~"},
+		{"`a **b**`", "`a **b**`"},
+		{"# Título **forte**", "*Título forte*"},
+		{"* item\n+ outro", "- item\n- outro"},
+		{"> citação", "> citação"},
+		{"[site](https://x.io)", "site (https://x.io)"},
+		{`\*literal\*`, "*\u200bliteral*\u200b"},
+		{"```\n**cru**\n```", "```**cru**```"},
+		{"---", ""},
+	}
+	for _, tt := range tests {
+		if got := gowa.FromMarkdown(tt.in); got != tt.want {
+			t.Errorf("FromMarkdown(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
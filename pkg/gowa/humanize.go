@@ -0,0 +1,143 @@
+package gowa
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+	"unicode/utf8"
+)
+
+type HumanizeConfig struct {
+	// CharsPerSecond é a velocidade de digitação simulada; default 8.
+	CharsPerSecond float64
+	// Jitter soma um atraso aleatório em [0, Jitter) ao tempo de digitação.
+	Jitter time.Duration
+	// MinDelay e MaxDelay limitam o tempo de digitação (já com jitter);
+	// default 1s e 10s.
+	MinDelay time.Duration
+	MaxDelay time.Duration
+	// ReadDelay é a pausa entre marcar a mensagem como lida e começar a
+	// digitar, em Reply.
+	ReadDelay time.Duration
+	// Clock permite controlar as esperas nos testes; default relógio do sistema.
+	Clock Clock
+	// OnPresenceError recebe as falhas do indicador "digitando…" (action
+	// "start" ou "stop"), que não interrompem o envio. Sem ele, as falhas
+	// vão para Config.Logger em Warn.
+	OnPresenceError func(phone, action string, err error)
+}
+
+// presenceRefresh é o intervalo para renovar o "digitando…", que o WhatsApp
+// apaga sozinho depois de alguns segundos sem atualização.
+const presenceRefresh = 20 * time.Second
+
+// HumanizedSender envia mensagens como uma pessoa: mostra "digitando…",
+// espera um tempo proporcional ao tamanho do texto e só então envia.
+// Falhas no indicador de presença e na confirmação de leitura não impedem o
+// envio: vão para HumanizeConfig.OnPresenceError ou para o Logger do client.
+type HumanizedSender struct {
+	c   *Client
+	cfg HumanizeConfig
+}
+
+func NewHumanizedSender(c *Client, cfg HumanizeConfig) *HumanizedSender {
+	if cfg.CharsPerSecond <= 0 {
+		cfg.CharsPerSecond = 8
+	}
+	if cfg.MinDelay <= 0 {
+		cfg.MinDelay = time.Second
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = 10 * time.Second
+	}
+	cfg.MaxDelay = max(cfg.MaxDelay, cfg.MinDelay)
+	if cfg.Clock == nil {
+		cfg.Clock = systemClock{}
+	}
+	return &HumanizedSender{c: c, cfg: cfg}
+}
+
+// TypingDelay calcula quanto tempo "digitar" text, com jitter e limites.
+func (h *HumanizedSender) TypingDelay(text string) time.Duration {
+	d := time.Duration(float64(utf8.RuneCountInString(text)) / h.cfg.CharsPerSecond * float64(time.Second))
+	if h.cfg.Jitter > 0 {
+		d += rand.N(h.cfg.Jitter)
+	}
+	return min(max(d, h.cfg.MinDelay), h.cfg.MaxDelay)
+}
+
+// Send mostra "digitando…" para phone, espera TypingDelay(text), envia com
+// SendMessage e encerra o indicador. Cancelar o ctx interrompe a espera sem
+// enviar; o indicador é encerrado mesmo assim.
+func (h *HumanizedSender) Send(ctx context.Context, phone, text string, opts ...SendOption) (*SendResponse, error) {
+	h.presence(ctx, phone, "start")
+	defer func() {
+		// ctx pode já estar cancelado: o "stop" sai mesmo assim, com prazo curto
+		stopCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+		defer cancel()
+		h.presence(stopCtx, phone, "stop")
+	}()
+
+	remaining := h.TypingDelay(text)
+	for remaining > 0 {
+		step := min(remaining, presenceRefresh)
+		if err := h.sleep(ctx, step); err != nil {
+			return nil, err
+		}
+		if remaining -= step; remaining > 0 {
+			h.presence(ctx, phone, "start")
+		}
+	}
+	return h.c.SendMessage(ctx, phone, text, opts...)
+}
+
+// Reply marca messageID (a mensagem que motivou a resposta) como lida, espera
+// ReadDelay e segue como Send. Se a confirmação de leitura falhar, o erro vai
+// para o Logger do client em Warn e a resposta sai mesmo assim. Para citar a
+// mensagem, passe WithReplyMessageID(messageID) em opts.
+func (h *HumanizedSender) Reply(ctx context.Context, phone, messageID, text string, opts ...SendOption) (*SendResponse, error) {
+	if messageID != "" {
+		if _, err := h.c.ReadMessage(ctx, MessageActionParams{MessageID: messageID, Phone: phone}); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			h.c.log.WarnContext(ctx, "gowa: mark as read failed",
+				slog.String("phone", h.c.redact.phones(phone)),
+				slog.String("message_id", messageID),
+				slog.String("error", h.c.redact.phones(err.Error())))
+		}
+		if err := h.sleep(ctx, h.cfg.ReadDelay); err != nil {
+			return nil, err
+		}
+	}
+	return h.Send(ctx, phone, text, opts...)
+}
+
+// presence envia o indicador "digitando…" e repassa a falha a
+// OnPresenceError (ou ao log).
+func (h *HumanizedSender) presence(ctx context.Context, phone, action string) {
+	_, err := h.c.SendChatPresence(ctx, SendChatPresenceParams{Phone: phone, Action: action})
+	switch {
+	case err == nil:
+	case h.cfg.OnPresenceError != nil:
+		h.cfg.OnPresenceError(phone, action, err)
+	default:
+		h.c.log.WarnContext(ctx, "gowa: typing indicator failed",
+			slog.String("phone", h.c.redact.phones(phone)),
+			slog.String("action", action),
+			slog.String("error", h.c.redact.phones(err.Error())))
+	}
+}
+
+func (h *HumanizedSender) sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-h.cfg.Clock.After(d):
+		return nil
+	}
+}
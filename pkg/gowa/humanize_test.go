@@ -0,0 +1,118 @@
+package gowa_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+func TestHumanizedSenderErrors(t *testing.T) {
+	ctx := context.Background()
+	const phone = "5511999990000"
+	newClient := func(t *testing.T, srv *gowatest.Server, buf *bytes.Buffer) *gowa.Client {
+		t.Helper()
+		c, err := gowa.New(gowa.Config{
+			BaseURL:    srv.URL,
+			Username:   "admin",
+			Password:   "admin",
+			HTTPClient: srv.Server.Client(),
+			RetryMax:   -1,
+			Logger:     slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelWarn})),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	// send roda do liberando as esperas do relógio manual
+	send := func(t *testing.T, clock *gowatest.Clock, do func() error) {
+		t.Helper()
+		done := make(chan error, 1)
+		go func() { done <- do() }()
+		for {
+			select {
+			case err := <-done:
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			default:
+			}
+			if clock.Waiters() > 0 {
+				clock.Advance(time.Minute)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	t.Run("presence hook", func(t *testing.T) {
+		srv := gowatest.NewServer(gowatest.Config{})
+		defer srv.Close()
+		srv.AddFault(gowatest.Fault{Path: "/send/chat-presence", Status: 500})
+		var buf bytes.Buffer
+		clock := gowatest.NewClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		var mu sync.Mutex
+		var actions []string
+		h := gowa.NewHumanizedSender(newClient(t, srv, &buf), gowa.HumanizeConfig{
+			Clock: clock,
+			OnPresenceError: func(p, action string, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				if p != phone || err == nil {
+					t.Errorf("OnPresenceError(%q, %q, %v)", p, action, err)
+				}
+				actions = append(actions, action)
+			},
+		})
+		send(t, clock, func() error {
+			_, err := h.Send(ctx, phone, "oi")
+			return err
+		})
+		mu.Lock()
+		defer mu.Unlock()
+		if !reflect.DeepEqual(actions, []string{"start", "stop"}) {
+			t.Errorf("actions = %v, want [start stop]", actions)
+		}
+		if _, ok := srv.LastRequest("/send/message"); !ok {
+			t.Error("message not sent after presence failure")
+		}
+	})
+
+	t.Run("logged", func(t *testing.T) {
+		srv := gowatest.NewServer(gowatest.Config{})
+		defer srv.Close()
+		srv.AddFault(gowatest.Fault{Path: "/send/chat-presence", Status: 500})
+		srv.AddFault(gowatest.Fault{Path: "/message/", Status: 500})
+		var buf bytes.Buffer
+		clock := gowatest.NewClock(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+		h := gowa.NewHumanizedSender(newClient(t, srv, &buf), gowa.HumanizeConfig{Clock: clock})
+		send(t, clock, func() error {
+			_, err := h.Reply(ctx, phone, "msg-1", "oi")
+			return err
+		})
+		var msgs []string
+		for _, l := range logLines(t, &buf) {
+			if msg := l["msg"].(string); msg != "gowa: request failed" {
+				msgs = append(msgs, msg)
+			}
+		}
+		want := []string{"gowa: mark as read failed", "gowa: typing indicator failed", "gowa: typing indicator failed"}
+		if !reflect.DeepEqual(msgs, want) {
+			t.Errorf("log = %q, want %q", msgs, want)
+		}
+		if strings.Contains(buf.String(), phone) {
+			t.Errorf("phone not redacted:\n%s", buf.String())
+		}
+		if _, ok := srv.LastRequest("/send/message"); !ok {
+			t.Error("reply not sent after read failure")
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package gowa
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// journal é um arquivo JSON Lines append-only de registros do tipo R, usado
+// pelo FileStore, pelo Outbox e pelo Scheduler. Cada linha é gravada com
+// fsync; ao abrir, um fragmento final sem '\n' (queda no meio da escrita) é
+// descartado. Não é seguro para uso concorrente: o dono serializa as chamadas
+// com o próprio mutex.
+type journal[R any] struct {
+	path string
+	f    journalFile
+}
+
+// journalFile é o que o journal usa do *os.File (os testes simulam falhas de
+// escrita com ele).
+type journalFile interface {
+	io.WriteCloser
+	Sync() error
+	Stat() (os.FileInfo, error)
+	Truncate(size int64) error
+	Seek(offset int64, whence int) (int64, error)
+}
+
+// openJournal abre (ou cria) o arquivo e chama apply para cada registro, em
+// ordem. Uma linha completa que não decodifica é corrupção, não queda: o
+// arquivo fica intacto e o erro indica a linha.
+func openJournal[R any](path string, apply func(R)) (*journal[R], error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	r := bufio.NewReader(f)
+	var good int64
+	for n := 1; ; n++ {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			break // line, se não vazia, é o fragmento da escrita interrompida
+		}
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		var rec R
+		if err := json.Unmarshal(line, &rec); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("gowa: %s: line %d: %w", path, n, err)
+		}
+		apply(rec)
+		good += int64(len(line))
+	}
+	if err := f.Truncate(good); err != nil {
+		f.Close()
+		return nil, err
+	}
+	if _, err := f.Seek(good, io.SeekStart); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &journal[R]{path: path, f: f}, nil
+}
+
+func (j *journal[R]) append(recs ...R) error {
+	if len(recs) == 0 {
+		return nil
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, rec := range recs {
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+	}
+	// uma escrita que falha no meio deixaria um fragmento de linha, e a
+	// próxima seria colada nele: volta o arquivo ao tamanho anterior
+	st, err := j.f.Stat()
+	if err != nil {
+		return err
+	}
+	if _, err := j.f.Write(buf.Bytes()); err != nil {
+		return errors.Join(err, j.rollback(st.Size()))
+	}
+	if err := j.f.Sync(); err != nil {
+		return errors.Join(err, j.rollback(st.Size()))
+	}
+	return nil
+}
+
+func (j *journal[R]) rollback(size int64) error {
+	if err := j.f.Truncate(size); err != nil {
+		return fmt.Errorf("gowa: %s: truncate after failed write: %w", j.path, err)
+	}
+	_, err := j.f.Seek(size, io.SeekStart)
+	return err
+}
+
+// rewrite substitui o arquivo, atomicamente, pelos registros informados.
+func (j *journal[R]) rewrite(recs []R) error {
+	tmp, err := os.CreateTemp(filepath.Dir(j.path), ".gowa-journal-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, rec := range recs {
+		if err := enc.Encode(rec); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), j.path); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(j.path, os.O_RDWR|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	j.f.Close()
+	j.f = f
+	return nil
+}
+
+func (j *journal[R]) close() error { return j.f.Close() }
@@ -0,0 +1,48 @@
+package gowa
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// shortWriteFile grava só os primeiros n bytes e falha, como um disco cheio.
+type shortWriteFile struct {
+	journalFile
+	n int
+}
+
+func (f shortWriteFile) Write(p []byte) (int, error) {
+	n, _ := f.journalFile.Write(p[:min(f.n, len(p))])
+	return n, errors.New("no space left on device")
+}
+
+func TestJournalFailedWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "journal.jsonl")
+	j, err := openJournal(path, func(string) {})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := j.append("a"); err != nil {
+		t.Fatal(err)
+	}
+	f := j.f
+	j.f = shortWriteFile{journalFile: f, n: 2}
+	if err := j.append("b"); err == nil {
+		t.Fatal("append with a short write succeeded")
+	}
+	j.f = f
+	if err := j.append("c"); err != nil {
+		t.Fatal(err)
+	}
+	j.close()
+
+	var got []string
+	if _, err := openJournal(path, func(s string) { got = append(got, s) }); err != nil {
+		t.Fatalf("reopen after failed write: %v", err)
+	}
+	if want := []string{"a", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("records = %q, want %q", got, want)
+	}
+}
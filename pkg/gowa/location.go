@@ -0,0 +1,217 @@
+package gowa
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrInvalidLocation é retornado (embrulhado) por ParseLocation e
+// Location.Validate.
+var ErrInvalidLocation = errors.New("gowa: invalid location")
+
+// Location é uma coordenada geográfica (WGS84, graus decimais). Name e Address
+// são metadados locais, por exemplo de uma lista de lojas: o /send/location
+// só transmite as coordenadas.
+type Location struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Name      string  `json:"name,omitempty"`
+	Address   string  `json:"address,omitempty"`
+}
+
+// NewLocation cria e valida uma coordenada.
+func NewLocation(lat, lng float64) (Location, error) {
+	l := Location{Latitude: lat, Longitude: lng}
+	return l, l.Validate()
+}
+
+// Validate confere se latitude está em [-90, 90] e longitude em [-180, 180].
+func (l Location) Validate() error {
+	switch {
+	case math.IsNaN(l.Latitude) || math.IsNaN(l.Longitude):
+		return fmt.Errorf("%w: NaN coordinate", ErrInvalidLocation)
+	case l.Latitude < -90 || l.Latitude > 90:
+		return fmt.Errorf("%w: latitude %v out of range [-90, 90]", ErrInvalidLocation, l.Latitude)
+	case l.Longitude < -180 || l.Longitude > 180:
+		return fmt.Errorf("%w: longitude %v out of range [-180, 180]", ErrInvalidLocation, l.Longitude)
+	}
+	return nil
+}
+
+func formatCoord(v float64) string { return strconv.FormatFloat(v, 'f', -1, 64) }
+
+// String retorna "lat,lng", o formato aceito por ParseLocation.
+func (l Location) String() string {
+	return formatCoord(l.Latitude) + "," + formatCoord(l.Longitude)
+}
+
+// GeoURI retorna a coordenada como URI geo: (RFC 5870).
+func (l Location) GeoURI() string { return "geo:" + l.String() }
+
+// MapsURL retorna um link do Google Maps para a coordenada.
+func (l Location) MapsURL() string {
+	return "https://www.google.com/maps/search/?api=1&query=" + url.QueryEscape(l.String())
+}
+
+var (
+	// "@-23.55,-46.63,15z" em URLs de lugar do Google Maps
+	mapsAtRe = regexp.MustCompile(`@(-?\d+(?:\.\d+)?),(-?\d+(?:\.\d+)?)`)
+	// "!3d-23.55!4d-46.63" (coordenada exata do marcador)
+	mapsDataRe = regexp.MustCompile(`!3d(-?\d+(?:\.\d+)?)!4d(-?\d+(?:\.\d+)?)`)
+	// par "lat,lng" ou "lat lng" com ponto decimal
+	coordPairRe = regexp.MustCompile(`^\s*(-?\d+(?:\.\d+)?)\s*([,; ])\s*(-?\d+(?:\.\d+)?)\s*$`)
+	coordRe     = regexp.MustCompile(`^-?\d+(?:\.\d+)?$`)
+)
+
+// ParseLocation interpreta "lat,lng" (também "lat lng" e "lat;lng"), URIs geo:
+// ("geo:-23.55,-46.63;u=10?q=Loja") e URLs do Google Maps (?q=, ?query=, ?ll=,
+// /@lat,lng e !3d…!4d…). O separador decimal é sempre o ponto e, com vírgula
+// entre os números, os dois precisam de casas decimais: "-23,55" (vírgula
+// decimal) é recusado em vez de virar a coordenada -23,55.
+func ParseLocation(s string) (Location, error) {
+	s = strings.TrimSpace(s)
+	var (
+		l   Location
+		err error
+	)
+	switch lower := strings.ToLower(s); {
+	case strings.HasPrefix(lower, "geo:"):
+		l, err = parseGeoURI(s)
+	case strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://"):
+		l, err = parseMapsURL(s)
+	default:
+		l, err = parseCoordPair(s)
+	}
+	if err != nil {
+		return Location{}, err
+	}
+	return l, l.Validate()
+}
+
+func parseCoordPair(s string) (Location, error) {
+	m := coordPairRe.FindStringSubmatch(s)
+	if m == nil {
+		return Location{}, fmt.Errorf("%w: %q is not \"lat,lng\"", ErrInvalidLocation, s)
+	}
+	if m[2] == "," && (!strings.Contains(m[1], ".") || !strings.Contains(m[3], ".")) {
+		return Location{}, fmt.Errorf("%w: %q is ambiguous, write \"lat.d,lng.d\"", ErrInvalidLocation, s)
+	}
+	return parseLatLng(m[1], m[3])
+}
+
+// parseLatLng converte latitude e longitude já separadas (componentes de URI
+// geo: ou de URL do Maps, onde a vírgula não é ambígua).
+func parseLatLng(lat, lng string) (Location, error) {
+	lat, lng = strings.TrimSpace(lat), strings.TrimSpace(lng)
+	if !coordRe.MatchString(lat) || !coordRe.MatchString(lng) {
+		return Location{}, fmt.Errorf("%w: %q,%q is not a coordinate", ErrInvalidLocation, lat, lng)
+	}
+	la, _ := strconv.ParseFloat(lat, 64)
+	lo, _ := strconv.ParseFloat(lng, 64)
+	return Location{Latitude: la, Longitude: lo}, nil
+}
+
+func parseGeoURI(s string) (Location, error) {
+	body := s[len("geo:"):]
+	body, query, _ := strings.Cut(body, "?")
+	coords, _, _ := strings.Cut(body, ";") // parâmetros como u= e crs=
+	parts := strings.Split(coords, ",")
+	if len(parts) < 2 || len(parts) > 3 { // altitude opcional
+		return Location{}, fmt.Errorf("%w: geo URI %q", ErrInvalidLocation, s)
+	}
+	l, err := parseLatLng(parts[0], parts[1])
+	if err != nil {
+		return Location{}, err
+	}
+	if q, err := url.ParseQuery(query); err == nil {
+		name := q.Get("q")
+		// geo:0,0?q=lat,lng(Nome) é a forma usada pelo Android
+		if i := strings.Index(name, "("); i >= 0 && strings.HasSuffix(name, ")") {
+			if inner, err := parseCoordPair(name[:i]); err == nil && l.Latitude == 0 && l.Longitude == 0 {
+				l.Latitude, l.Longitude = inner.Latitude, inner.Longitude
+			}
+			name = name[i+1 : len(name)-1]
+		}
+		if _, err := parseCoordPair(name); err != nil {
+			l.Name = name
+		}
+	}
+	return l, nil
+}
+
+func parseMapsURL(s string) (Location, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return Location{}, fmt.Errorf("%w: %v", ErrInvalidLocation, err)
+	}
+	// o marcador (!3d!4d) é mais preciso que o centro do mapa (@)
+	if m := mapsDataRe.FindStringSubmatch(u.Path); m != nil {
+		return parseLatLng(m[1], m[2])
+	}
+	q := u.Query()
+	for _, key := range []string{"query", "q", "ll", "destination", "daddr"} {
+		if v := q.Get(key); v != "" {
+			if l, err := parseCoordPair(v); err == nil {
+				return l, nil
+			}
+		}
+	}
+	if m := mapsAtRe.FindStringSubmatch(u.Path); m != nil {
+		return parseLatLng(m[1], m[2])
+	}
+	return Location{}, fmt.Errorf("%w: no coordinates in %q", ErrInvalidLocation, s)
+}
+
+// LocationFromMessage extrai a coordenada de uma mensagem de localização
+// recebida ("lat,lng") ou de um link geo:/Google Maps no texto.
+func LocationFromMessage(m ChatMessage) (Location, error) {
+	if l, err := ParseLocation(m.Content); err == nil {
+		return l, nil
+	}
+	for _, f := range strings.Fields(m.Content) {
+		if l, err := ParseLocation(f); err == nil {
+			return l, nil
+		}
+	}
+	return Location{}, fmt.Errorf("%w: message %s has no location", ErrInvalidLocation, m.ID)
+}
+
+// earthRadius é o raio médio da Terra em metros.
+const earthRadius = 6371008.8
+
+// DistanceTo retorna a distância em metros até o (fórmula de haversine).
+func (l Location) DistanceTo(o Location) float64 {
+	rad := math.Pi / 180
+	dLat := (o.Latitude - l.Latitude) * rad
+	dLng := (o.Longitude - l.Longitude) * rad
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(l.Latitude*rad)*math.Cos(o.Latitude*rad)*math.Sin(dLng/2)*math.Sin(dLng/2)
+	return 2 * earthRadius * math.Asin(math.Min(1, math.Sqrt(a)))
+}
+
+// LocationDistance é um lugar e sua distância, em metros, até a origem.
+type LocationDistance struct {
+	Location Location
+	Meters   float64
+}
+
+// NearestLocations ordena places pela distância até from e retorna os n mais
+// próximos (n <= 0 retorna todos). Útil para responder "qual a loja mais
+// perto" a uma localização recebida.
+func NearestLocations(from Location, places []Location, n int) []LocationDistance {
+	out := make([]LocationDistance, len(places))
+	for i, p := range places {
+		out[i] = LocationDistance{Location: p, Meters: from.DistanceTo(p)}
+	}
+	sort.SliceStable(out, func(a, b int) bool { return out[a].Meters < out[b].Meters })
+	if n > 0 && n < len(out) {
+		out = out[:n]
+	}
+	return out
+}
@@ -0,0 +1,97 @@
+package gowa_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+func TestParseLocation(t *testing.T) {
+	tests := []struct {
+		in       string
+		lat, lng float64
+		name     string
+	}{
+		{"-23.5505,-46.6333", -23.5505, -46.6333, ""},
+		{" -23.5505 -46.6333 ", -23.5505, -46.6333, ""},
+		{"-23.5505;-46.6333", -23.5505, -46.6333, ""},
+		{"-23 -46", -23, -46, ""}, // sem vírgula não há ambiguidade
+		{"geo:0,0", 0, 0, ""},
+		{"geo:-23.55,-46.63;u=10?q=Loja", -23.55, -46.63, "Loja"},
+		{"geo:-23.55,-46.63,760", -23.55, -46.63, ""},
+		{"geo:0,0?q=-23.55,-46.63(Loja Centro)", -23.55, -46.63, "Loja Centro"},
+		{"https://www.google.com/maps/search/?api=1&query=-23.55,-46.63", -23.55, -46.63, ""},
+		{"https://maps.google.com/?q=-23.55,-46.63", -23.55, -46.63, ""},
+		{"https://www.google.com/maps/place/X/@-23.50,-46.60,15z/data=!3d-23.55!4d-46.63", -23.55, -46.63, ""},
+		{"https://www.google.com/maps/@-23.50,-46.60,15z", -23.50, -46.60, ""},
+	}
+	for _, tt := range tests {
+		l, err := gowa.ParseLocation(tt.in)
+		if err != nil {
+			t.Errorf("ParseLocation(%q): %v", tt.in, err)
+			continue
+		}
+		if l.Latitude != tt.lat || l.Longitude != tt.lng || l.Name != tt.name {
+			t.Errorf("ParseLocation(%q) = %+v, want %v,%v %q", tt.in, l, tt.lat, tt.lng, tt.name)
+		}
+	}
+}
+
+func TestParseLocationErrors(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"abc",
+		"91.0,0.0",
+		"0.0,181.0",
+		"-23,55", // vírgula decimal
+		"-23.55,46",
+		"geo:NaN,0",
+		"geo:1",
+		"https://example.com/",
+	} {
+		if _, err := gowa.ParseLocation(in); !errors.Is(err, gowa.ErrInvalidLocation) {
+			t.Errorf("ParseLocation(%q): err = %v, want ErrInvalidLocation", in, err)
+		}
+	}
+}
+
+func TestNearestLocations(t *testing.T) {
+	from := gowa.Location{Latitude: -23.5505, Longitude: -46.6333} // São Paulo
+	rio := gowa.Location{Latitude: -22.9068, Longitude: -43.1729, Name: "Rio"}
+	cps := gowa.Location{Latitude: -22.9056, Longitude: -47.0608, Name: "Campinas"}
+	if d := from.DistanceTo(rio); math.Abs(d-361_000) > 5_000 {
+		t.Errorf("DistanceTo(Rio) = %.0f m", d)
+	}
+	got := gowa.NearestLocations(from, []gowa.Location{rio, cps}, 1)
+	if len(got) != 1 || got[0].Location.Name != "Campinas" {
+		t.Errorf("NearestLocations = %+v", got)
+	}
+}
+
+func TestSendLocationText(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	cli := srv.GowaClient()
+	ctx := context.Background()
+	for _, tt := range []struct {
+		lat, lng string
+		ok       bool
+	}{
+		{"-23.55", "-46.63", true},
+		{"-23", "-46", true},
+		{"-23,55", "-46.63", false},
+		{"-23.55", "46,63", false},
+	} {
+		_, err := cli.SendLocation(ctx, gowa.SendLocationParams{Phone: "5511999990000", Latitude: tt.lat, Longitude: tt.lng})
+		if tt.ok && err != nil {
+			t.Errorf("SendLocation(%s, %s): %v", tt.lat, tt.lng, err)
+		}
+		if !tt.ok && !errors.Is(err, gowa.ErrInvalidLocation) {
+			t.Errorf("SendLocation(%s, %s): err = %v, want ErrInvalidLocation", tt.lat, tt.lng, err)
+		}
+	}
+}
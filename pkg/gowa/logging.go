@@ -0,0 +1,263 @@
+package gowa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+)
+
+// RedactLevel controla o quanto de dados pessoais aparece nos logs. O header
+// Authorization (e a senha do BasicAuth) nunca é registrado.
+type RedactLevel int
+
+const (
+	// RedactStrict mascara telefones/JIDs e omite textos de mensagens,
+	// legendas e enquetes. É o padrão.
+	RedactStrict RedactLevel = iota
+	// RedactPhones mascara só os telefones; os textos aparecem.
+	RedactPhones
+	// RedactNone não altera telefones nem textos. Use só em depuração local.
+	RedactNone
+)
+
+// phoneRe casa números de telefone (10 a 15 dígitos), inclusive dentro de
+// JIDs e URLs.
+var phoneRe = regexp.MustCompile(`\d{10,15}`)
+
+// textFields são os campos de requisição (corpo ou query) com conteúdo
+// escrito pelo usuário.
+var textFields = map[string]bool{
+	"message": true, "caption": true, "question": true, "options": true,
+	"contact_name": true, "emoji": true, "push_name": true, "search": true,
+}
+
+// sensitiveHeaders nunca são registrados, em nenhum nível.
+var sensitiveHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// MaskPhones troca cada número de telefone em s (10 a 15 dígitos, inclusive
+// dentro de JIDs e URLs) pelo retorno de mask. É o mesmo critério da redação
+// dos logs; o gowatest o usa nos cassettes.
+func MaskPhones(s string, mask func(digits string) string) string {
+	return phoneRe.ReplaceAllStringFunc(s, mask)
+}
+
+// RedactHeader retorna uma cópia de h com os headers de credenciais
+// (Authorization, Proxy-Authorization, Cookie e Set-Cookie) trocados por
+// "REDACTED".
+func RedactHeader(h http.Header) http.Header {
+	h = h.Clone()
+	for _, k := range sensitiveHeaders {
+		if h.Get(k) != "" {
+			h.Set(k, "REDACTED")
+		}
+	}
+	return h
+}
+
+type redactor struct {
+	level RedactLevel
+}
+
+// phones mascara os telefones em s mantendo os 4 últimos dígitos.
+func (r redactor) phones(s string) string {
+	if r.level >= RedactNone {
+		return s
+	}
+	return MaskPhones(s, func(d string) string {
+		return strings.Repeat("*", len(d)-4) + d[len(d)-4:]
+	})
+}
+
+func (r redactor) text(s string) string {
+	if r.level >= RedactPhones {
+		return r.phones(s)
+	}
+	return fmt.Sprintf("[%d chars]", len([]rune(s)))
+}
+
+// fields aplica a redação campo a campo sobre o corpo da requisição (JSON ou
+// multipart) já convertido em map.
+func (r redactor) fields(m map[string]any) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		switch {
+		case textFields[k]:
+			b, _ := json.Marshal(v)
+			s, ok := v.(string)
+			if !ok {
+				s = string(b)
+			}
+			out[k] = r.text(s)
+		default:
+			if s, ok := v.(string); ok {
+				out[k] = r.phones(s)
+			} else {
+				out[k] = v
+			}
+		}
+	}
+	return out
+}
+
+// callBody devolve o corpo da chamada como map para log, ou nil se não há.
+func (r redactor) callBody(call *Call) map[string]any {
+	m := map[string]any{}
+	switch {
+	case call.Multipart:
+		for k, v := range call.Form {
+			m[k] = v
+		}
+		if call.FilePath != "" {
+			m[call.FileField] = "@" + call.FilePath
+		}
+	case call.Body != nil:
+		b, err := json.Marshal(call.Body)
+		if err != nil || json.Unmarshal(b, &m) != nil {
+			return nil
+		}
+	}
+	if len(m) == 0 {
+		return nil
+	}
+	return r.fields(m)
+}
+
+// query redige a query string como os campos do corpo: a busca de
+// GetChatMessages e ListChats é texto do usuário.
+func (r redactor) query(q url.Values) map[string]any {
+	m := make(map[string]any, len(q))
+	for k, v := range q {
+		m[k] = strings.Join(v, ",")
+	}
+	return r.fields(m)
+}
+
+// url redige a query de uma URL completa, que o retryablehttp registra.
+func (r redactor) url(s string) string {
+	base, raw, ok := strings.Cut(s, "?")
+	if !ok {
+		return r.phones(s)
+	}
+	q, err := url.ParseQuery(raw)
+	if err != nil {
+		return r.phones(base) + "?[unparsable query]"
+	}
+	red := r.query(q)
+	parts := make([]string, 0, len(red))
+	for _, k := range slices.Sorted(maps.Keys(red)) {
+		parts = append(parts, k+"="+fmt.Sprint(red[k]))
+	}
+	return r.phones(base) + "?" + strings.Join(parts, "&")
+}
+
+// discardLogger é usado quando Config.Logger é nil: a biblioteca fica em
+// silêncio, inclusive o retryablehttp.
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// logStart registra o início da chamada em Debug, com a query e o corpo
+// redigidos.
+func (c *Client) logStart(ctx context.Context, call *Call, headers http.Header) {
+	if !c.log.Enabled(ctx, slog.LevelDebug) {
+		return
+	}
+	attrs := []any{
+		slog.String("operation", call.Operation),
+		slog.String("method", call.Method),
+		slog.String("path", c.redact.phones(call.Path)),
+	}
+	if len(call.Query) > 0 {
+		attrs = append(attrs, slog.Any("query", c.redact.query(call.Query)))
+	}
+	if len(headers) > 0 {
+		attrs = append(attrs, slog.Any("header", RedactHeader(headers)))
+	}
+	if body := c.redact.callBody(call); body != nil {
+		attrs = append(attrs, slog.Any("body", body))
+	}
+	c.log.DebugContext(ctx, "gowa: request", attrs...)
+}
+
+// logEnd registra o resultado: Debug no sucesso, Error na falha.
+func (c *Client) logEnd(ctx context.Context, call *Call, status int, began time.Time, err error) {
+	attrs := []any{
+		slog.String("operation", call.Operation),
+		slog.String("method", call.Method),
+		slog.String("path", c.redact.phones(call.Path)),
+		slog.Duration("duration", time.Since(began)),
+	}
+	if kind := recipientKind(call); kind != "" {
+		attrs = append(attrs, slog.String("recipient_kind", kind))
+	}
+	if err == nil {
+		c.log.DebugContext(ctx, "gowa: response", append(attrs, slog.Int("status", status))...)
+		return
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		attrs = append(attrs, slog.Int("status", apiErr.StatusCode), slog.String("code", apiErr.Code))
+	}
+	c.log.ErrorContext(ctx, "gowa: request failed", append(attrs, slog.String("error", c.redact.phones(err.Error())))...)
+}
+
+// requestHook é o RequestLogHook do retryablehttp: conta as tentativas para a
+// telemetria e registra cada retry em Warn.
+func (c *Client) requestHook(l retryablehttp.Logger, req *http.Request, attempt int) {
+	countAttempt(l, req, attempt)
+	if attempt > 0 {
+		c.log.WarnContext(req.Context(), "gowa: retry",
+			slog.String("method", req.Method),
+			slog.String("path", c.redact.phones(req.URL.Path)),
+			slog.Int("attempt", attempt))
+	}
+}
+
+// retryLogger encaminha os logs internos do retryablehttp para o slog, com
+// redação dos valores (URLs podem conter telefones na query).
+type retryLogger struct {
+	l      *slog.Logger
+	redact redactor
+}
+
+func (r retryLogger) Error(msg string, kv ...any) { r.log(slog.LevelError, msg, kv) }
+func (r retryLogger) Info(msg string, kv ...any)  { r.log(slog.LevelInfo, msg, kv) }
+func (r retryLogger) Debug(msg string, kv ...any) { r.log(slog.LevelDebug, msg, kv) }
+func (r retryLogger) Warn(msg string, kv ...any)  { r.log(slog.LevelWarn, msg, kv) }
+
+func (r retryLogger) log(level slog.Level, msg string, kv []any) {
+	ctx := context.Background()
+	if !r.l.Enabled(ctx, level) {
+		return
+	}
+	attrs := make([]any, 0, len(kv))
+	for i := 0; i+1 < len(kv); i += 2 {
+		k := fmt.Sprint(kv[i])
+		switch v := kv[i+1].(type) {
+		case *http.Request:
+			attrs = append(attrs, slog.String(k, v.Method+" "+r.redact.phones(v.URL.Path)))
+		case *http.Response:
+			attrs = append(attrs, slog.Int(k, v.StatusCode))
+		case error:
+			attrs = append(attrs, slog.String(k, r.redact.phones(v.Error())))
+		case string:
+			if k == "url" {
+				attrs = append(attrs, slog.String(k, r.redact.url(v)))
+			} else {
+				attrs = append(attrs, slog.String(k, r.redact.phones(v)))
+			}
+		default:
+			attrs = append(attrs, slog.Any(k, v))
+		}
+	}
+	r.l.Log(ctx, level, "retryablehttp: "+msg, attrs...)
+}
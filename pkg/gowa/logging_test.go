@@ -0,0 +1,121 @@
+package gowa_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+// logLines decodifica as linhas do gowa na saída do slog.JSONHandler (as do
+// retryablehttp ficam de fora).
+func logLines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, l := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if l == "" {
+			continue
+		}
+		var m map[string]any
+		if err := json.Unmarshal([]byte(l), &m); err != nil {
+			t.Fatalf("log line %q: %v", l, err)
+		}
+		if msg, _ := m["msg"].(string); strings.HasPrefix(msg, "gowa: ") {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+func TestLogging(t *testing.T) {
+	ctx := context.Background()
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	var buf bytes.Buffer
+	breakBody := false
+	c, err := gowa.New(gowa.Config{
+		BaseURL:    srv.URL,
+		HTTPClient: srv.Server.Client(),
+		RetryMax:   -1,
+		Logger:     slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})),
+		Middlewares: []gowa.Middleware{func(next gowa.Doer) gowa.Doer {
+			return gowa.DoerFunc(func(ctx context.Context, call *gowa.Call) error {
+				if breakBody {
+					call.Body = map[string]any{"x": make(chan int)} // json.Marshal falha
+				}
+				return next.Do(ctx, call)
+			})
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	const phone = "5511999990000"
+
+	t.Run("path redacted", func(t *testing.T) {
+		buf.Reset()
+		_, _ = c.Raw().GetChatMessages(ctx, gowa.GetChatMessagesRequest{ChatJID: phone + "@s.whatsapp.net"})
+		if strings.Contains(buf.String(), phone) {
+			t.Errorf("phone leaked into the logs:\n%s", buf.String())
+		}
+		lines := logLines(t, &buf)
+		if len(lines) != 2 {
+			t.Fatalf("got %d log lines, want start and end:\n%s", len(lines), buf.String())
+		}
+		for _, l := range lines {
+			if p, _ := l["path"].(string); !strings.HasSuffix(p, "0000@s.whatsapp.net/messages") {
+				t.Errorf("%s: path = %q", l["msg"], p)
+			}
+		}
+	})
+
+	t.Run("query redacted", func(t *testing.T) {
+		buf.Reset()
+		_, _ = c.Raw().GetChatMessages(ctx, gowa.GetChatMessagesRequest{ChatJID: phone + "@s.whatsapp.net", Limit: 5, Search: "boleto atrasado"})
+		if strings.Contains(buf.String(), "boleto") || strings.Contains(buf.String(), phone) {
+			t.Errorf("search text or phone leaked into the logs:\n%s", buf.String())
+		}
+		lines := logLines(t, &buf)
+		q, _ := lines[0]["query"].(map[string]any)
+		if q["search"] != "[15 chars]" || q["limit"] != "5" {
+			t.Errorf("query = %v", lines[0]["query"])
+		}
+		if p, _ := lines[0]["path"].(string); strings.Contains(p, "?") {
+			t.Errorf("path = %q, want the query logged apart", p)
+		}
+	})
+
+	t.Run("api error", func(t *testing.T) {
+		buf.Reset()
+		srv.AddFault(gowatest.Fault{Path: "/send/message", Status: 400, Times: 1, Body: `{"code":"INVALID_JID","message":"invalid jid"}`})
+		if _, err := c.SendMessage(ctx, phone, "oi"); err == nil {
+			t.Fatal("expected the injected 400")
+		}
+		lines := logLines(t, &buf)
+		last := lines[len(lines)-1]
+		if last["msg"] != "gowa: request failed" || last["status"] != float64(400) || last["code"] != "INVALID_JID" {
+			t.Errorf("failure line = %v", last)
+		}
+	})
+
+	t.Run("marshal error", func(t *testing.T) {
+		buf.Reset()
+		breakBody = true
+		defer func() { breakBody = false }()
+		if _, err := c.SendMessage(ctx, phone, "oi"); err == nil {
+			t.Fatal("expected a marshal error")
+		}
+		lines := logLines(t, &buf)
+		if len(lines) != 1 || lines[0]["msg"] != "gowa: request failed" {
+			t.Fatalf("log = %v, want a single failure line", lines)
+		}
+		if lines[0]["operation"] != "sendMessage" {
+			t.Errorf("operation = %v", lines[0]["operation"])
+		}
+	})
+}
@@ -0,0 +1,288 @@
+package gowa
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+type LoginEventKind string
+
+const (
+	LoginEventQR       LoginEventKind = "qr"        // QR novo ou renovado
+	LoginEventPairCode LoginEventKind = "pair_code" // código de pareamento novo ou renovado
+	LoginEventSuccess  LoginEventKind = "success"   // dispositivo conectado
+)
+
+// LoginEvent é entregue ao callback de LoginFlow a cada mudança do login.
+type LoginEvent struct {
+	Kind      LoginEventKind
+	QR        *QRCode
+	PairCode  string
+	ExpiresAt time.Time
+	Devices   []Device
+}
+
+// QRCode é a imagem do QR retornada por /app/login.
+type QRCode struct {
+	Link string
+	PNG  []byte
+}
+
+// SavePNG grava a imagem original do QR em path.
+func (q *QRCode) SavePNG(path string) error {
+	return os.WriteFile(path, q.PNG, 0o644)
+}
+
+// Terminal renderiza o QR com blocos Unicode (dois módulos por caractere).
+// Por padrão assume terminal de fundo escuro; invert=true para fundo claro.
+func (q *QRCode) Terminal(invert bool) (string, error) {
+	grid, err := qrModules(q.PNG)
+	if err != nil {
+		return "", err
+	}
+	return renderHalfBlocks(grid, invert), nil
+}
+
+type LoginFlowConfig struct {
+	// Phone ativa o login por código de pareamento em vez do QR.
+	Phone string
+	// PairCodeTTL é a validade do código de pareamento, que o servidor não
+	// informa; ao vencer, um código novo é pedido. Default 2min.
+	PairCodeTTL  time.Duration
+	PollInterval time.Duration // intervalo de consulta a /app/devices; default 2s
+	// OnEvent recebe os eventos de QR, código de pareamento e sucesso.
+	OnEvent func(LoginEvent)
+}
+
+// LoginFlow conduz o login: busca e renova o QR (ou o código de pareamento)
+// e consulta /app/devices até um dispositivo aparecer.
+type LoginFlow struct {
+	c   *Client
+	cfg LoginFlowConfig
+}
+
+func NewLoginFlow(c *Client, cfg LoginFlowConfig) (*LoginFlow, error) {
+	if c == nil {
+		return nil, errors.New("client is required")
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 2 * time.Second
+	}
+	if cfg.PairCodeTTL <= 0 {
+		cfg.PairCodeTTL = 2 * time.Minute
+	}
+	if cfg.OnEvent == nil {
+		cfg.OnEvent = func(LoginEvent) {}
+	}
+	return &LoginFlow{c: c, cfg: cfg}, nil
+}
+
+// Run bloqueia até a sessão ficar logada ou ctx terminar. Se já houver um
+// dispositivo conectado, retorna imediatamente. Falhas transitórias na
+// consulta a /app/devices (rede, 5xx, 429) são tentadas de novo no próximo
+// intervalo; as demais encerram o login.
+func (f *LoginFlow) Run(ctx context.Context) ([]Device, error) {
+	if devs, err := f.devices(ctx); err != nil && !transientPollError(err) {
+		return nil, err
+	} else if len(devs) > 0 {
+		f.cfg.OnEvent(LoginEvent{Kind: LoginEventSuccess, Devices: devs})
+		return devs, nil
+	}
+
+	fetch := f.fetchQR
+	if f.cfg.Phone != "" {
+		fetch = f.fetchPairCode
+	}
+	var expires time.Time
+	tick := time.NewTicker(f.cfg.PollInterval)
+	defer tick.Stop()
+	for {
+		if !time.Now().Before(expires) {
+			ev, err := fetch(ctx)
+			if err != nil {
+				return nil, err
+			}
+			expires = ev.ExpiresAt
+			f.cfg.OnEvent(ev)
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-tick.C:
+		}
+		devs, err := f.devices(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if !transientPollError(err) {
+				return nil, err
+			}
+			continue // tenta de novo no próximo tick
+		}
+		if len(devs) > 0 {
+			f.cfg.OnEvent(LoginEvent{Kind: LoginEventSuccess, Devices: devs})
+			return devs, nil
+		}
+	}
+}
+
+// transientPollError diz se vale repetir a consulta: falhas de rede e
+// respostas 5xx, 429 e 408. Erros de requisição (401, 400…) não mudam.
+func transientPollError(err error) bool {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		s := apiErr.StatusCode
+		return s >= 500 || s == http.StatusTooManyRequests || s == http.StatusRequestTimeout
+	}
+	return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
+
+func (f *LoginFlow) devices(ctx context.Context) ([]Device, error) {
+	resp, err := f.c.Devices(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Results, nil
+}
+
+func (f *LoginFlow) fetchQR(ctx context.Context) (LoginEvent, error) {
+	login, err := f.c.Login(ctx)
+	if err != nil {
+		return LoginEvent{}, err
+	}
+	d := time.Duration(login.Results.QRDuration) * time.Second
+	if d <= 0 {
+		d = 30 * time.Second
+	}
+	expires := time.Now().Add(d)
+	u, err := f.c.base.Parse(login.Results.QRLink)
+	if err != nil {
+		return LoginEvent{}, fmt.Errorf("invalid qr link: %w", err)
+	}
+	var png bytes.Buffer
+	if _, err := f.c.download(ctx, "downloadQRCode", u.String(), "image/png", &png); err != nil {
+		return LoginEvent{}, err
+	}
+	return LoginEvent{Kind: LoginEventQR, QR: &QRCode{Link: login.Results.QRLink, PNG: png.Bytes()}, ExpiresAt: expires}, nil
+}
+
+func (f *LoginFlow) fetchPairCode(ctx context.Context) (LoginEvent, error) {
+	resp, err := f.c.LoginWithCode(ctx, f.cfg.Phone)
+	if err != nil {
+		return LoginEvent{}, err
+	}
+	return LoginEvent{Kind: LoginEventPairCode, PairCode: resp.Results.PairCode, ExpiresAt: time.Now().Add(f.cfg.PairCodeTTL)}, nil
+}
+
+// TerminalLoginHandler é um OnEvent pronto que desenha o QR, o código de
+// pareamento e o resultado em w.
+func TerminalLoginHandler(w io.Writer, invert bool) func(LoginEvent) {
+	return func(ev LoginEvent) {
+		switch ev.Kind {
+		case LoginEventQR:
+			s, err := ev.QR.Terminal(invert)
+			if err != nil {
+				fmt.Fprintf(w, "QR: %s (%v)\n", ev.QR.Link, err)
+				return
+			}
+			fmt.Fprintf(w, "%s\nEscaneie o QR no WhatsApp (expira às %s)\n", s, ev.ExpiresAt.Format("15:04:05"))
+		case LoginEventPairCode:
+			fmt.Fprintf(w, "Código de pareamento: %s (expira às %s)\n", ev.PairCode, ev.ExpiresAt.Format("15:04:05"))
+		case LoginEventSuccess:
+			for _, d := range ev.Devices {
+				fmt.Fprintf(w, "Conectado: %s (%s)\n", d.Name, d.Device)
+			}
+		}
+	}
+}
+
+// qrModules reduz a imagem do QR à matriz de módulos (true = escuro). O
+// tamanho do módulo é medido pelo padrão localizador do canto superior
+// esquerdo, que tem sempre 7 módulos de largura.
+func qrModules(b []byte) ([][]bool, error) {
+	img, err := png.Decode(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("decode qr: %w", err)
+	}
+	r := img.Bounds()
+	dark := func(x, y int) bool {
+		cr, cg, cb, _ := img.At(x, y).RGBA()
+		return (cr+cg+cb)/3 < 0x8000
+	}
+	minX, minY, maxX, maxY := r.Max.X, r.Max.Y, r.Min.X-1, r.Min.Y-1
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if dark(x, y) {
+				minX, minY = min(minX, x), min(minY, y)
+				maxX, maxY = max(maxX, x), max(maxY, y)
+			}
+		}
+	}
+	if maxX < minX {
+		return nil, errors.New("decode qr: blank image")
+	}
+	run := 0
+	for x := minX; x <= maxX && dark(x, minY); x++ {
+		run++
+	}
+	module := float64(run) / 7
+	if module < 1 {
+		return nil, errors.New("decode qr: finder pattern not found")
+	}
+	n := int(float64(maxX-minX+1)/module + 0.5)
+	grid := make([][]bool, n)
+	for j := range grid {
+		grid[j] = make([]bool, n)
+		for i := range grid[j] {
+			x := minX + int((float64(i)+0.5)*module)
+			y := minY + int((float64(j)+0.5)*module)
+			grid[j][i] = image.Pt(x, y).In(r) && dark(x, y)
+		}
+	}
+	return grid, nil
+}
+
+// renderHalfBlocks desenha duas linhas de módulos por linha de texto, com
+// zona de silêncio de 2 módulos. Com número ímpar de linhas, a última linha
+// de texto é completada com mais uma linha da zona de silêncio, para que a
+// borda inferior não fique pela metade.
+func renderHalfBlocks(grid [][]bool, invert bool) string {
+	const quiet = 2
+	n := len(grid) + 2*quiet
+	rows := n + n%2
+	// lit indica se a célula deve ser desenhada com bloco: módulos claros em
+	// terminal escuro, módulos escuros em terminal claro.
+	lit := func(x, y int) bool {
+		x, y = x-quiet, y-quiet
+		d := y >= 0 && y < len(grid) && x >= 0 && x < len(grid) && grid[y][x]
+		return d == invert
+	}
+	var sb strings.Builder
+	for y := 0; y < rows; y += 2 {
+		for x := 0; x < n; x++ {
+			top, bottom := lit(x, y), lit(x, y+1)
+			switch {
+			case top && bottom:
+				sb.WriteRune('█')
+			case top:
+				sb.WriteRune('▀')
+			case bottom:
+				sb.WriteRune('▄')
+			default:
+				sb.WriteRune(' ')
+			}
+		}
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
@@ -0,0 +1,178 @@
+package gowa_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+// qrGrid monta uma matriz n×n com os três padrões localizadores (e seus
+// separadores claros) e módulos de dados pseudoaleatórios.
+func qrGrid(n int) [][]bool {
+	rng := rand.New(rand.NewPCG(uint64(n), 1))
+	grid := make([][]bool, n)
+	for y := range grid {
+		grid[y] = make([]bool, n)
+		for x := range grid[y] {
+			grid[y][x] = rng.IntN(2) == 0
+		}
+	}
+	for _, o := range [][2]int{{0, 0}, {n - 7, 0}, {0, n - 7}} {
+		for y := -1; y <= 7; y++ {
+			for x := -1; x <= 7; x++ {
+				gx, gy := o[0]+x, o[1]+y
+				if gx < 0 || gy < 0 || gx >= n || gy >= n {
+					continue
+				}
+				ring := x == 0 || y == 0 || x == 6 || y == 6
+				core := x >= 2 && x <= 4 && y >= 2 && y <= 4
+				inside := x >= 0 && y >= 0 && x <= 6 && y <= 6
+				grid[gy][gx] = inside && (ring || core)
+			}
+		}
+	}
+	return grid
+}
+
+func qrPNG(t *testing.T, grid [][]bool, scale, border int) []byte {
+	t.Helper()
+	size := (len(grid) + 2*border) * scale
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	for y, row := range grid {
+		for x, dark := range row {
+			if !dark {
+				continue
+			}
+			for dy := range scale {
+				for dx := range scale {
+					img.Set((border+x)*scale+dx, (border+y)*scale+dy, color.Black)
+				}
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// halfBlock lê de volta a célula (x, y) do desenho em meios blocos.
+func halfBlock(lines [][]rune, x, y int) bool {
+	switch lines[y/2][x] {
+	case '█':
+		return true
+	case '▀':
+		return y%2 == 0
+	case '▄':
+		return y%2 == 1
+	}
+	return false
+}
+
+func TestQRCodeTerminal(t *testing.T) {
+	const quiet = 2
+	for _, n := range []int{21, 22, 25} { // ímpar, par e outra versão do QR
+		grid := qrGrid(n)
+		qr := &gowa.QRCode{PNG: qrPNG(t, grid, 3, 4)}
+		for _, invert := range []bool{false, true} {
+			s, err := qr.Terminal(invert)
+			if err != nil {
+				t.Fatalf("n=%d: %v", n, err)
+			}
+			var lines [][]rune
+			for _, l := range strings.Split(strings.TrimSuffix(s, "\n"), "\n") {
+				lines = append(lines, []rune(l))
+			}
+			size := n + 2*quiet
+			if want := (size + 1) / 2; len(lines) != want {
+				t.Fatalf("n=%d invert=%v: %d lines, want %d", n, invert, len(lines), want)
+			}
+			// todas as linhas desenhadas, inclusive a zona de silêncio de baixo
+			// e o complemento da última linha quando size é ímpar
+			for y := 0; y < 2*len(lines); y++ {
+				for x := 0; x < size; x++ {
+					gx, gy := x-quiet, y-quiet
+					dark := gx >= 0 && gy >= 0 && gx < n && gy < n && grid[gy][gx]
+					if got := halfBlock(lines, x, y); got != (dark == invert) {
+						t.Fatalf("n=%d invert=%v: cell (%d,%d) lit=%v, want %v", n, invert, x, y, got, dark == invert)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestQRCodeTerminalErrors(t *testing.T) {
+	blank := qrPNG(t, [][]bool{{false}}, 4, 4)
+	for name, b := range map[string][]byte{"not png": []byte("x"), "blank": blank} {
+		if _, err := (&gowa.QRCode{PNG: b}).Terminal(false); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoginFlowRetriesDevices(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv := gowatest.NewServer(gowatest.Config{Session: gowa.StateLoggedOut})
+	defer srv.Close()
+	// a consulta inicial e a primeira do loop falham; o login segue
+	srv.AddFault(gowatest.Fault{Path: "/app/devices", Status: http.StatusBadGateway, Times: 2})
+	var kinds []gowa.LoginEventKind
+	f, err := gowa.NewLoginFlow(srv.GowaClient(), gowa.LoginFlowConfig{
+		PollInterval: 5 * time.Millisecond,
+		OnEvent: func(ev gowa.LoginEvent) {
+			kinds = append(kinds, ev.Kind)
+			if ev.Kind == gowa.LoginEventQR {
+				srv.CompleteLogin()
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	devs, err := f.Run(ctx)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if len(devs) != 1 || len(kinds) != 2 || kinds[0] != gowa.LoginEventQR || kinds[1] != gowa.LoginEventSuccess {
+		t.Errorf("devices = %+v, events = %v", devs, kinds)
+	}
+}
+
+func TestLoginFlowFatalDevicesError(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	srv := gowatest.NewServer(gowatest.Config{Session: gowa.StateLoggedOut})
+	defer srv.Close()
+	f, err := gowa.NewLoginFlow(srv.GowaClient(), gowa.LoginFlowConfig{
+		PollInterval: 5 * time.Millisecond,
+		OnEvent: func(ev gowa.LoginEvent) {
+			if ev.Kind == gowa.LoginEventQR {
+				srv.AddFault(gowatest.Fault{Path: "/app/devices", Status: http.StatusUnauthorized})
+			}
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var apiErr *gowa.APIError
+	if _, err := f.Run(ctx); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("Run: err = %v, want the 401", err)
+	}
+}
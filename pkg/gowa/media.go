@@ -0,0 +1,196 @@
+package gowa
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+var ErrNoMedia = errors.New("message has no media url")
+
+// MediaFile descreve uma mídia baixada. Path aponta para o arquivo
+// endereçado por conteúdo (<sha256><ext>) dentro do diretório de destino.
+type MediaFile struct {
+	MessageID string
+	Path      string
+	SHA256    string
+	Size      int64
+	Filename  string // nome original, sanitizado
+	Duplicate bool   // o conteúdo já existia no destino
+}
+
+// DownloadMedia baixa a mídia de msg para o diretório dst usando o transporte
+// autenticado do client (com middlewares, telemetria e logs). O arquivo é
+// gravado como <sha256><ext>, de modo que conteúdos repetidos não ocupam
+// espaço duas vezes. Se msg.FileLength estiver presente, o tamanho baixado é
+// conferido. Config.Timeout vale só até o início da resposta: a duração do
+// download é limitada pelo ctx.
+func (c *Client) DownloadMedia(ctx context.Context, msg ChatMessage, dst string) (*MediaFile, error) {
+	if msg.URL == nil || *msg.URL == "" {
+		return nil, ErrNoMedia
+	}
+	if dst == "" {
+		return nil, errors.New("dst is required")
+	}
+	if err := os.MkdirAll(dst, 0o755); err != nil {
+		return nil, err
+	}
+	u, err := c.base.Parse(*msg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid media url: %w", err)
+	}
+	tmp, err := os.CreateTemp(dst, ".download-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	h := sha256.New()
+	cw := &countWriter{w: io.MultiWriter(tmp, h)}
+	header, err := c.download(ctx, "downloadMedia", u.String(), "*/*", cw)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	n := cw.n
+	if msg.FileLength != nil && *msg.FileLength > 0 && n != *msg.FileLength {
+		return nil, fmt.Errorf("media length mismatch: got %d bytes, want %d", n, *msg.FileLength)
+	}
+	if cl, err := strconv.ParseInt(header.Get("Content-Length"), 10, 64); err == nil && n != cl {
+		return nil, fmt.Errorf("media length mismatch: got %d bytes, content-length %d", n, cl)
+	}
+
+	name := ""
+	if msg.Filename != nil {
+		name = safeFilename(*msg.Filename)
+	}
+	if name == "" {
+		name = safeFilename(filepath.Base(u.Path))
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	out := &MediaFile{
+		MessageID: msg.ID,
+		Path:      filepath.Join(dst, sum+mediaExt(name, header.Get("Content-Type"))),
+		SHA256:    sum,
+		Size:      n,
+		Filename:  name,
+	}
+	if _, err := os.Stat(out.Path); err == nil {
+		out.Duplicate = true
+		return out, nil
+	}
+	if err := os.Rename(tmp.Name(), out.Path); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// countWriter conta os bytes gravados em w.
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// MediaDownloadResult é o resultado de uma mensagem em um download em lote.
+type MediaDownloadResult struct {
+	Message ChatMessage
+	File    *MediaFile
+	Err     error
+}
+
+// DownloadMediaBatch baixa as mídias de msgs com até workers downloads
+// simultâneos (default 4). Mensagens sem URL são ignoradas. A ordem do
+// resultado acompanha a ordem de msgs.
+func (c *Client) DownloadMediaBatch(ctx context.Context, msgs []ChatMessage, dst string, workers int) []MediaDownloadResult {
+	if workers <= 0 {
+		workers = 4
+	}
+	var media []ChatMessage
+	for _, m := range msgs {
+		if m.URL != nil && *m.URL != "" {
+			media = append(media, m)
+		}
+	}
+	results := make([]MediaDownloadResult, len(media))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				f, err := c.DownloadMedia(ctx, media[i], dst)
+				results[i] = MediaDownloadResult{Message: media[i], File: f, Err: err}
+			}
+		}()
+	}
+	for i := range media {
+		if ctx.Err() != nil {
+			results[i] = MediaDownloadResult{Message: media[i], Err: ctx.Err()}
+			continue
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// DownloadChatMedia lista todas as mensagens com mídia de chatJID e baixa
+// cada uma via DownloadMediaBatch.
+func (c *Client) DownloadChatMedia(ctx context.Context, chatJID, dst string, workers int) ([]MediaDownloadResult, error) {
+	mediaOnly := true
+	msgs, err := c.allChatMessages(ctx, chatJID, GetChatMessagesParams{Limit: 100, MediaOnly: &mediaOnly})
+	if err != nil {
+		return nil, err
+	}
+	return c.DownloadMediaBatch(ctx, msgs, dst, workers), nil
+}
+
+// safeFilename remove diretórios, caracteres de controle e separadores do nome
+// recebido do servidor e limita seu tamanho.
+func safeFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsControl(r), strings.ContainsRune(`/\:*?"<>|`, r):
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, ". ")
+	if r := []rune(name); len(r) > 128 {
+		name = string(r[len(r)-128:])
+	}
+	return name
+}
+
+func mediaExt(name, contentType string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if len(ext) > 1 && len(ext) <= 10 && strings.IndexFunc(ext[1:], func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9')
+	}) < 0 {
+		return ext
+	}
+	if exts, _ := mime.ExtensionsByType(contentType); len(exts) > 0 {
+		return exts[0]
+	}
+	return ""
+}
@@ -0,0 +1,183 @@
+package gowa_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+const mediaChat = "5511999990000@s.whatsapp.net"
+
+func TestDownloadMedia(t *testing.T) {
+	ctx := context.Background()
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	c := srv.GowaClient()
+	data := []byte("\x89PNG\r\n\x1a\nconteúdo")
+	sum := sha256.Sum256(data)
+	dst := t.TempDir()
+
+	msg := srv.AddMediaMessage(gowa.ChatMessage{ChatJID: mediaChat}, "image", "foto.png", data)
+	f, err := c.DownloadMedia(ctx, msg, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.SHA256 != hex.EncodeToString(sum[:]) || f.Size != int64(len(data)) || f.Duplicate {
+		t.Errorf("first download = %+v", f)
+	}
+	if f.Path != filepath.Join(dst, f.SHA256+".png") {
+		t.Errorf("path = %s, want <sha256>.png", f.Path)
+	}
+	if got, _ := os.ReadFile(f.Path); !bytes.Equal(got, data) {
+		t.Errorf("content = %q", got)
+	}
+
+	// mesmo conteúdo em outra mensagem: não grava de novo
+	again := srv.AddMediaMessage(gowa.ChatMessage{ChatJID: mediaChat}, "image", "copia.png", data)
+	f2, err := c.DownloadMedia(ctx, again, dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f2.Duplicate || f2.Path != f.Path {
+		t.Errorf("second download = %+v, want duplicate of %s", f2, f.Path)
+	}
+	if entries, _ := os.ReadDir(dst); len(entries) != 1 {
+		t.Errorf("%d files in dst, want 1 (temporaries removed)", len(entries))
+	}
+
+	if _, err := c.DownloadMedia(ctx, gowa.ChatMessage{ID: "x"}, dst); err != gowa.ErrNoMedia {
+		t.Errorf("no url: err = %v, want ErrNoMedia", err)
+	}
+}
+
+func TestDownloadMediaLengthMismatch(t *testing.T) {
+	ctx := context.Background()
+	t.Run("file length", func(t *testing.T) {
+		srv := gowatest.NewServer(gowatest.Config{})
+		defer srv.Close()
+		msg := srv.AddMediaMessage(gowa.ChatMessage{ChatJID: mediaChat}, "image", "a.png", []byte("12345"))
+		n := int64(9)
+		msg.FileLength = &n
+		dst := t.TempDir()
+		if _, err := srv.GowaClient().DownloadMedia(ctx, msg, dst); err == nil || !strings.Contains(err.Error(), "length mismatch") {
+			t.Errorf("err = %v, want length mismatch", err)
+		}
+		if entries, _ := os.ReadDir(dst); len(entries) != 0 {
+			t.Errorf("%d files left in dst after mismatch", len(entries))
+		}
+	})
+	t.Run("truncated body", func(t *testing.T) {
+		// o servidor promete 100 bytes e fecha a conexão antes
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Length", "100")
+			w.Write([]byte("curto"))
+		}))
+		defer ts.Close()
+		c, err := gowa.New(gowa.Config{BaseURL: ts.URL, HTTPClient: ts.Client(), RetryMax: -1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		u := ts.URL + "/statics/media/m1"
+		dst := t.TempDir()
+		if _, err := c.DownloadMedia(ctx, gowa.ChatMessage{ID: "m1", URL: &u}, dst); err == nil {
+			t.Error("truncated download succeeded")
+		}
+		if entries, _ := os.ReadDir(dst); len(entries) != 0 {
+			t.Errorf("%d files left in dst after truncated body", len(entries))
+		}
+	})
+}
+
+func TestDownloadMediaFilename(t *testing.T) {
+	ctx := context.Background()
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	c := srv.GowaClient()
+	tests := []struct {
+		name, want string
+	}{
+		{"relatorio.pdf", "relatorio.pdf"},
+		{"../../etc/passwd", "passwd"},
+		{`..\..\windows\win.ini`, "win.ini"},
+		{"a\x00b\nc\x1b.txt", "a_b_c_.txt"},
+		{`x:y*z?"<>|.txt`, "x_y_z_____.txt"},
+		{"  ..oculto.. ", "oculto"},
+		{strings.Repeat("a", 200) + ".pdf", strings.Repeat("a", 124) + ".pdf"},
+	}
+	for i, tt := range tests {
+		msg := srv.AddMediaMessage(gowa.ChatMessage{ChatJID: mediaChat}, "document", tt.name, []byte{byte(i)})
+		f, err := c.DownloadMedia(ctx, msg, t.TempDir())
+		if err != nil {
+			t.Fatalf("%q: %v", tt.name, err)
+		}
+		if f.Filename != tt.want {
+			t.Errorf("Filename(%q) = %q, want %q", tt.name, f.Filename, tt.want)
+		}
+	}
+}
+
+func TestDownloadMediaBatch(t *testing.T) {
+	ctx := context.Background()
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	c := srv.GowaClient()
+	var msgs []gowa.ChatMessage
+	for i := range 6 {
+		msgs = append(msgs, srv.AddMediaMessage(gowa.ChatMessage{ChatJID: mediaChat}, "image", "f.png", []byte{byte(i)}))
+	}
+	// sem mídia: ignorada
+	msgs = append(msgs, srv.AddMessage(gowa.ChatMessage{ChatJID: mediaChat, Content: "texto"}))
+	// mídia inexistente e tamanho errado falham sem derrubar o lote
+	gone := srv.URL + "/statics/media/nao-existe"
+	msgs[1].URL = &gone
+	n := int64(99)
+	msgs[4].FileLength = &n
+
+	res := c.DownloadMediaBatch(ctx, msgs, t.TempDir(), 3)
+	if len(res) != 6 {
+		t.Fatalf("%d results, want 6 (message without media skipped)", len(res))
+	}
+	for i, r := range res {
+		if r.Message.ID != msgs[i].ID {
+			t.Errorf("result %d is for %s, want %s (order kept)", i, r.Message.ID, msgs[i].ID)
+		}
+		failed := i == 1 || i == 4
+		if failed != (r.Err != nil) || failed != (r.File == nil) {
+			t.Errorf("result %d: file %v, err %v", i, r.File, r.Err)
+		}
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	for i, r := range c.DownloadMediaBatch(cctx, msgs, t.TempDir(), 2) {
+		if r.Err == nil {
+			t.Errorf("cancelled batch: result %d has no error", i)
+		}
+	}
+}
+
+func TestDownloadChatMedia(t *testing.T) {
+	ctx := context.Background()
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	srv.AddMessage(gowa.ChatMessage{ChatJID: mediaChat, Content: "oi"})
+	srv.AddMediaMessage(gowa.ChatMessage{ChatJID: mediaChat}, "image", "a.png", []byte("a"))
+	srv.AddMediaMessage(gowa.ChatMessage{ChatJID: mediaChat}, "image", "b.png", []byte("b"))
+	res, err := srv.GowaClient().DownloadChatMedia(ctx, mediaChat, t.TempDir(), 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 || res[0].Err != nil || res[1].Err != nil {
+		t.Errorf("results = %+v", res)
+	}
+}
@@ -0,0 +1,87 @@
+package gowa
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Call descreve uma operação da API a caminho do servidor. Os middlewares
+// recebem o Call já montado e podem inspecioná-lo, alterá-lo (headers, query,
+// corpo, campos) ou responder sem chegar ao servidor preenchendo Response.
+type Call struct {
+	Operation string      // operationId do OpenAPI (ex.: "sendMessage")
+	Method    string      // GET ou POST
+	Path      string      // relativo ao BaseURL, sem query
+	Route     string      // template do path no OpenAPI (ex.: "/chat/{chat_jid}/messages"); vazio nos downloads
+	URL       string      // URL absoluta, nos downloads de mídia e QR; Path fica com o caminho dela
+	Query     url.Values  // parâmetros de query
+	Header    http.Header // headers extras desta chamada
+	Request   any         // request tipado (ex.: SendMessageRequest); nil se a operação não tem parâmetros
+
+	// Body é o valor serializado como JSON em POSTs application/json. Nos
+	// demais casos é nil.
+	Body any
+
+	// Form, FileField e FilePath formam o corpo multipart. Multipart indica
+	// que a chamada usa esse transporte mesmo sem campos ou arquivo.
+	Multipart bool
+	Form      map[string]string
+	FileField string
+	FilePath  string
+
+	// Response aponta para a resposta tipada (ex.: *SendResponse) em que o
+	// corpo é decodificado. Pode ser nil quando o chamador descarta o corpo.
+	Response any
+
+	// Output recebe o corpo da resposta sem decodificar, nos downloads; com
+	// ele, Response é ignorado.
+	Output io.Writer
+
+	// ResponseHeader é preenchido com os headers da resposta HTTP.
+	ResponseHeader http.Header
+}
+
+// Doer executa um Call. O último Doer da cadeia é o transporte HTTP do Client.
+type Doer interface {
+	Do(ctx context.Context, call *Call) error
+}
+
+// DoerFunc adapta uma função a Doer.
+type DoerFunc func(ctx context.Context, call *Call) error
+
+func (f DoerFunc) Do(ctx context.Context, call *Call) error { return f(ctx, call) }
+
+// Middleware envolve um Doer. Chame next.Do para seguir adiante; retornar sem
+// chamá-lo interrompe a cadeia (ex.: cache, mocks, dry-run).
+type Middleware func(next Doer) Doer
+
+// chain compõe os middlewares de modo que o primeiro da lista seja o mais
+// externo, ou seja, o primeiro a ver a chamada e o último a ver a resposta.
+func chain(mws []Middleware, final Doer) Doer {
+	d := final
+	for i := len(mws) - 1; i >= 0; i-- {
+		if mws[i] != nil {
+			d = mws[i](d)
+		}
+	}
+	return d
+}
+
+// newCall separa a query eventualmente embutida em p (POSTs com parâmetros de
+// query montam o path com "?").
+func newCall(op, method, p string, q url.Values, req any) *Call {
+	p, rawQuery, _ := strings.Cut(p, "?")
+	if rawQuery != "" {
+		extra, _ := url.ParseQuery(rawQuery)
+		if q == nil {
+			q = url.Values{}
+		}
+		for k, v := range extra {
+			q[k] = append(q[k], v...)
+		}
+	}
+	return &Call{Operation: op, Method: method, Path: p, Query: q, Header: http.Header{}, Request: req}
+}
@@ -0,0 +1,112 @@
+package gowa
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnsupportedOption indica uma SendOption que o endpoint não aceita (por
+// exemplo WithViewOnce em SendMessage).
+var ErrUnsupportedOption = errors.New("gowa: option not supported by this endpoint")
+
+// SendOption ajusta campos opcionais de um envio. O mesmo valor funciona em
+// qualquer método Send*, seja o transporte JSON ou multipart; opções que o
+// endpoint não aceita retornam ErrUnsupportedOption antes da requisição.
+type SendOption func(*sendFields) error
+
+// sendFields aponta para os campos opcionais do request gerado de cada
+// endpoint. Ponteiro nil significa que o endpoint não tem o campo.
+type sendFields struct {
+	endpoint       string
+	replyMessageID *string
+	forwarded      *bool
+	duration       *int
+	viewOnce       *bool
+	compress       *bool
+}
+
+func (f *sendFields) unsupported(opt string) error {
+	return fmt.Errorf("%w: %s (%s)", ErrUnsupportedOption, opt, f.endpoint)
+}
+
+// applySendOptions aplica as opções na ordem; a última vence.
+func applySendOptions(f sendFields, opts []SendOption) error {
+	for _, o := range opts {
+		if o == nil {
+			continue
+		}
+		if err := o(&f); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// WithReplyMessageID responde à mensagem com o ID informado.
+func WithReplyMessageID(id string) SendOption {
+	return func(f *sendFields) error {
+		if f.replyMessageID == nil {
+			return f.unsupported("WithReplyMessageID")
+		}
+		if strings.TrimSpace(id) == "" {
+			return errors.New("gowa: WithReplyMessageID: empty id")
+		}
+		*f.replyMessageID = id
+		return nil
+	}
+}
+
+// WithForwarded marca o envio como encaminhado.
+func WithForwarded(forwarded bool) SendOption {
+	return func(f *sendFields) error {
+		if f.forwarded == nil {
+			return f.unsupported("WithForwarded")
+		}
+		*f.forwarded = forwarded
+		return nil
+	}
+}
+
+// WithDisappearingDuration define a duração (segundos) da mensagem temporária.
+// Zero desliga.
+func WithDisappearingDuration(seconds int) SendOption {
+	return func(f *sendFields) error {
+		if f.duration == nil {
+			return f.unsupported("WithDisappearingDuration")
+		}
+		if seconds < 0 {
+			return fmt.Errorf("gowa: WithDisappearingDuration: negative duration (%d)", seconds)
+		}
+		*f.duration = seconds
+		return nil
+	}
+}
+
+// WithDurationStr é o antigo nome de WithDisappearingDuration para envios
+// multipart.
+//
+// Deprecated: use WithDisappearingDuration, que funciona em qualquer envio.
+func WithDurationStr(seconds int) SendOption { return WithDisappearingDuration(seconds) }
+
+// WithViewOnce envia a mídia como visualização única (imagem e vídeo).
+func WithViewOnce(viewOnce bool) SendOption {
+	return func(f *sendFields) error {
+		if f.viewOnce == nil {
+			return f.unsupported("WithViewOnce")
+		}
+		*f.viewOnce = viewOnce
+		return nil
+	}
+}
+
+// WithCompress pede ao servidor para comprimir a mídia (imagem e vídeo).
+func WithCompress(compress bool) SendOption {
+	return func(f *sendFields) error {
+		if f.compress == nil {
+			return f.unsupported("WithCompress")
+		}
+		*f.compress = compress
+		return nil
+	}
+}
@@ -0,0 +1,610 @@
+package gowa
+
+import (
+	"context"
+	crand "crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Sendable é um request de envio aceito pelo Outbox: SendMessageRequest,
+// SendImageRequest, SendAudioRequest, SendFileRequest, SendVideoRequest,
+// SendContactRequest, SendLinkRequest, SendLocationRequest ou SendPollRequest.
+type Sendable interface {
+	sendKind() string
+	sendFile() string // arquivo local da mídia; "" se não há
+}
+
+func (SendMessageRequest) sendKind() string  { return "message" }
+func (SendImageRequest) sendKind() string    { return "image" }
+func (SendAudioRequest) sendKind() string    { return "audio" }
+func (SendFileRequest) sendKind() string     { return "file" }
+func (SendVideoRequest) sendKind() string    { return "video" }
+func (SendContactRequest) sendKind() string  { return "contact" }
+func (SendLinkRequest) sendKind() string     { return "link" }
+func (SendLocationRequest) sendKind() string { return "location" }
+func (SendPollRequest) sendKind() string     { return "poll" }
+
+func (SendMessageRequest) sendFile() string  { return "" }
+func (r SendImageRequest) sendFile() string  { return r.Image }
+func (r SendAudioRequest) sendFile() string  { return r.Audio }
+func (r SendFileRequest) sendFile() string   { return r.File }
+func (r SendVideoRequest) sendFile() string  { return r.Video }
+func (SendContactRequest) sendFile() string  { return "" }
+func (SendLinkRequest) sendFile() string     { return "" }
+func (SendLocationRequest) sendFile() string { return "" }
+func (SendPollRequest) sendFile() string     { return "" }
+
+// outboxKind sabe decodificar e enviar um tipo de request. setFile restaura
+// o arquivo local, que os tipos gerados não serializam em JSON.
+type outboxKind struct {
+	decode func(b []byte, file string) (Sendable, error)
+	send   func(context.Context, *RawAPI, Sendable) (*SendResponse, error)
+}
+
+func kindOf[T Sendable](setFile func(*T, string), send func(*RawAPI, context.Context, T) (*SendResponse, error)) outboxKind {
+	return outboxKind{
+		decode: func(b []byte, file string) (Sendable, error) {
+			var v T
+			if err := json.Unmarshal(b, &v); err != nil {
+				return nil, err
+			}
+			if setFile != nil {
+				setFile(&v, file)
+			}
+			return v, nil
+		},
+		send: func(ctx context.Context, a *RawAPI, s Sendable) (*SendResponse, error) {
+			return send(a, ctx, s.(T))
+		},
+	}
+}
+
+var outboxKinds = map[string]outboxKind{
+	"message":  kindOf[SendMessageRequest](nil, (*RawAPI).SendMessage),
+	"image":    kindOf(func(r *SendImageRequest, f string) { r.Image = f }, (*RawAPI).SendImage),
+	"audio":    kindOf(func(r *SendAudioRequest, f string) { r.Audio = f }, (*RawAPI).SendAudio),
+	"file":     kindOf(func(r *SendFileRequest, f string) { r.File = f }, (*RawAPI).SendFile),
+	"video":    kindOf(func(r *SendVideoRequest, f string) { r.Video = f }, (*RawAPI).SendVideo),
+	"contact":  kindOf[SendContactRequest](nil, (*RawAPI).SendContact),
+	"link":     kindOf[SendLinkRequest](nil, (*RawAPI).SendLink),
+	"location": kindOf[SendLocationRequest](nil, (*RawAPI).SendLocation),
+	"poll":     kindOf[SendPollRequest](nil, (*RawAPI).SendPoll),
+}
+
+// JobStatus é o estado de um job do Outbox.
+type JobStatus string
+
+const (
+	JobPending JobStatus = "pending" // aguardando envio (ou novo retry)
+	JobSending JobStatus = "sending" // requisição em andamento
+	JobSent    JobStatus = "sent"    // aceito pelo servidor; MessageID preenchido
+	JobFailed  JobStatus = "failed"  // erro permanente ou tentativas esgotadas
+	// JobUnknown marca jobs cuja entrega não foi confirmada: estavam em
+	// andamento quando o processo caiu, ou a conexão falhou depois de a
+	// requisição ser escrita (timeout da resposta, conexão resetada). O
+	// servidor pode ou não ter recebido o envio; veja Retry e
+	// OutboxConfig.ResendInFlight.
+	JobUnknown JobStatus = "unknown"
+)
+
+// OutboxJob é um envio persistido no journal.
+type OutboxJob struct {
+	ID        string          `json:"id"`
+	Key       string          `json:"key,omitempty"` // chave de idempotência do Enqueue
+	Kind      string          `json:"kind"`
+	Request   json.RawMessage `json:"request"`
+	File      string          `json:"file,omitempty"` // arquivo local das mídias
+	Status    JobStatus       `json:"status"`
+	Attempts  int             `json:"attempts"`
+	NextAt    time.Time       `json:"next_at"`
+	LastError string          `json:"last_error,omitempty"`
+	MessageID string          `json:"message_id,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+}
+
+// Decode reconstrói o request tipado do job (ex.: SendImageRequest).
+func (j OutboxJob) Decode() (Sendable, error) {
+	k, ok := outboxKinds[j.Kind]
+	if !ok {
+		return nil, fmt.Errorf("outbox: unknown kind %q", j.Kind)
+	}
+	return k.decode(j.Request, j.File)
+}
+
+type OutboxConfig struct {
+	Path        string        // arquivo do journal (JSON Lines); obrigatório
+	MaxAttempts int           // default 5
+	BackoffMin  time.Duration // default 2s
+	BackoffMax  time.Duration // default 5min
+
+	// ResendInFlight reenvia, ao reabrir o journal, os jobs que estavam em
+	// andamento na queda (entrega at-least-once, pode duplicar). Sem ele esses
+	// jobs ficam como JobUnknown e não são reenviados.
+	ResendInFlight bool
+
+	// OnUpdate é chamado (fora do lock) a cada mudança de estado de um job.
+	OnUpdate func(OutboxJob)
+}
+
+// EnqueueOptions ajusta um job. Key torna o Enqueue idempotente: um segundo
+// Enqueue com a mesma chave devolve o job existente em vez de criar outro.
+type EnqueueOptions struct {
+	Key   string
+	After time.Time // não envia antes deste instante
+}
+
+// Outbox é uma fila de envios persistida em arquivo: aceita requests de
+// envio, entrega pelo Client com retry e backoff, registra o message_id e
+// retoma os pendentes após reiniciar o processo. Implementa Pausable para ser
+// pausado pelo Supervisor enquanto a sessão está desconectada.
+type Outbox struct {
+	c   *Client
+	cfg OutboxConfig
+	now func() time.Time
+
+	mu     sync.Mutex
+	jr     *journal[outboxRecord]
+	jobs   map[string]*OutboxJob
+	keys   map[string]string
+	paused bool
+	wake   chan struct{}
+}
+
+type outboxRecord struct {
+	Job *OutboxJob `json:"job"`
+}
+
+// OpenOutbox abre (ou cria) o journal em cfg.Path e recupera os jobs.
+func OpenOutbox(c *Client, cfg OutboxConfig) (*Outbox, error) {
+	if c == nil || cfg.Path == "" {
+		return nil, errors.New("outbox: client and path are required")
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 5
+	}
+	if cfg.BackoffMin <= 0 {
+		cfg.BackoffMin = 2 * time.Second
+	}
+	if cfg.BackoffMax <= 0 {
+		cfg.BackoffMax = 5 * time.Minute
+	}
+	o := &Outbox{
+		c:    c,
+		cfg:  cfg,
+		now:  time.Now,
+		jobs: map[string]*OutboxJob{},
+		keys: map[string]string{},
+		wake: make(chan struct{}, 1),
+	}
+	jr, err := openJournal(cfg.Path, func(rec outboxRecord) {
+		if rec.Job != nil {
+			o.apply(rec.Job)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	o.jr = jr
+	if err := o.recover(); err != nil {
+		jr.close()
+		return nil, err
+	}
+	return o, nil
+}
+
+// recover trata os jobs que estavam em andamento quando o processo caiu.
+func (o *Outbox) recover() error {
+	var recs []*OutboxJob
+	for _, j := range o.jobs {
+		if j.Status != JobSending {
+			continue
+		}
+		cp := *j
+		cp.UpdatedAt = o.now()
+		if o.cfg.ResendInFlight {
+			cp.Status = JobPending
+		} else {
+			cp.Status = JobUnknown
+			cp.LastError = "interrupted while sending; delivery not confirmed"
+		}
+		recs = append(recs, &cp)
+	}
+	return o.append(recs...)
+}
+
+func (o *Outbox) apply(j *OutboxJob) {
+	o.jobs[j.ID] = j
+	if j.Key != "" {
+		o.keys[j.Key] = j.ID
+	}
+}
+
+func (o *Outbox) append(jobs ...*OutboxJob) error {
+	recs := make([]outboxRecord, len(jobs))
+	for i, j := range jobs {
+		recs[i] = outboxRecord{Job: j}
+	}
+	if err := o.jr.append(recs...); err != nil {
+		return err
+	}
+	for _, j := range jobs {
+		o.apply(j)
+	}
+	return nil
+}
+
+// Enqueue persiste o envio e o entrega na próxima volta de Run. O retorno só
+// acontece depois do fsync do journal.
+func (o *Outbox) Enqueue(req Sendable, opts ...EnqueueOptions) (OutboxJob, error) {
+	if req == nil {
+		return OutboxJob{}, errors.New("outbox: request is required")
+	}
+	var opt EnqueueOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	kind := req.sendKind()
+	b, err := json.Marshal(req)
+	if err != nil {
+		return OutboxJob{}, err
+	}
+	now := o.now()
+	j := &OutboxJob{
+		ID:        newJobID(),
+		Key:       opt.Key,
+		Kind:      kind,
+		Request:   b,
+		File:      req.sendFile(),
+		Status:    JobPending,
+		NextAt:    opt.After,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	o.mu.Lock()
+	if id, ok := o.keys[opt.Key]; ok && opt.Key != "" {
+		existing := *o.jobs[id]
+		o.mu.Unlock()
+		return existing, nil
+	}
+	err = o.append(j)
+	o.mu.Unlock()
+	if err != nil {
+		return OutboxJob{}, err
+	}
+	o.notify(*j)
+	o.signal()
+	return *j, nil
+}
+
+func newJobID() string {
+	b := make([]byte, 12)
+	crand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// Job retorna o estado atual de um job.
+func (o *Outbox) Job(id string) (OutboxJob, bool) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	j, ok := o.jobs[id]
+	if !ok {
+		return OutboxJob{}, false
+	}
+	return *j, true
+}
+
+// Jobs lista os jobs com os status informados (todos, se nenhum), em ordem de
+// criação.
+func (o *Outbox) Jobs(status ...JobStatus) []OutboxJob {
+	o.mu.Lock()
+	var out []OutboxJob
+	for _, j := range o.jobs {
+		if len(status) == 0 || containsStatus(status, j.Status) {
+			out = append(out, *j)
+		}
+	}
+	o.mu.Unlock()
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].CreatedAt.Equal(out[j].CreatedAt) {
+			return out[i].CreatedAt.Before(out[j].CreatedAt)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+func containsStatus(list []JobStatus, s JobStatus) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// Counts retorna quantos jobs há em cada status.
+func (o *Outbox) Counts() map[JobStatus]int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	out := map[JobStatus]int{}
+	for _, j := range o.jobs {
+		out[j.Status]++
+	}
+	return out
+}
+
+// Retry devolve para a fila um job JobFailed ou JobUnknown, zerando as
+// tentativas. Use em JobUnknown só depois de confirmar que o envio não chegou.
+func (o *Outbox) Retry(id string) error {
+	o.mu.Lock()
+	j, ok := o.jobs[id]
+	if !ok {
+		o.mu.Unlock()
+		return fmt.Errorf("outbox: job %s not found", id)
+	}
+	if j.Status != JobFailed && j.Status != JobUnknown {
+		o.mu.Unlock()
+		return fmt.Errorf("outbox: job %s is %s", id, j.Status)
+	}
+	cp := *j
+	cp.Status, cp.Attempts, cp.NextAt, cp.UpdatedAt = JobPending, 0, time.Time{}, o.now()
+	err := o.append(&cp)
+	o.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	o.notify(cp)
+	o.signal()
+	return nil
+}
+
+func (o *Outbox) Pause() {
+	o.mu.Lock()
+	o.paused = true
+	o.mu.Unlock()
+}
+
+func (o *Outbox) Resume() {
+	o.mu.Lock()
+	o.paused = false
+	o.mu.Unlock()
+	o.signal()
+}
+
+func (o *Outbox) signal() {
+	select {
+	case o.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (o *Outbox) notify(j OutboxJob) {
+	if o.cfg.OnUpdate != nil {
+		o.cfg.OnUpdate(j)
+	}
+}
+
+// Run entrega os jobs pendentes, um por vez e em ordem de NextAt/criação, até
+// o ctx ser cancelado. Um envio em andamento no cancelamento é concluído
+// antes de Run retornar, para não deixar o job em estado incerto. Várias
+// chamadas concorrentes de Run dividem a fila sem enviar o mesmo job duas
+// vezes.
+func (o *Outbox) Run(ctx context.Context) error {
+	for {
+		j, wait, err := o.next()
+		if err != nil {
+			return err
+		}
+		if j == nil {
+			if err := o.idle(ctx, wait); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := o.deliver(context.WithoutCancel(ctx), j); err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// idle espera um Enqueue/Resume, o próximo retry agendado (wait > 0) ou o
+// cancelamento do ctx.
+func (o *Outbox) idle(ctx context.Context, wait time.Duration) error {
+	var timer <-chan time.Time
+	if wait > 0 {
+		t := time.NewTimer(wait)
+		defer t.Stop()
+		timer = t.C
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-o.wake:
+	case <-timer:
+	}
+	return nil
+}
+
+// next escolhe o próximo job pronto e o grava como JobSending sob o mesmo
+// lock, para que outro Run não o pegue também; sem nenhum, retorna quanto
+// esperar pelo próximo retry agendado (0 = esperar sinal).
+func (o *Outbox) next() (*OutboxJob, time.Duration, error) {
+	o.mu.Lock()
+	if o.paused {
+		o.mu.Unlock()
+		return nil, 0, nil
+	}
+	now := o.now()
+	var best *OutboxJob
+	for _, j := range o.jobs {
+		if j.Status != JobPending {
+			continue
+		}
+		if best == nil || j.NextAt.Before(best.NextAt) ||
+			(j.NextAt.Equal(best.NextAt) && j.CreatedAt.Before(best.CreatedAt)) {
+			best = j
+		}
+	}
+	if best == nil {
+		o.mu.Unlock()
+		return nil, 0, nil
+	}
+	if best.NextAt.After(now) {
+		o.mu.Unlock()
+		return nil, best.NextAt.Sub(now), nil
+	}
+	cp := *best
+	cp.Status, cp.Attempts, cp.UpdatedAt = JobSending, cp.Attempts+1, now
+	err := o.append(&cp)
+	o.mu.Unlock()
+	if err != nil {
+		return nil, 0, err
+	}
+	o.notify(cp)
+	j := cp // &cp ficou no mapa; deliver trabalha numa cópia
+	return &j, 0, nil
+}
+
+// deliver envia um job já marcado como JobSending por next e grava o
+// resultado. Só retorna erro se o journal não puder ser escrito.
+func (o *Outbox) deliver(ctx context.Context, j *OutboxJob) error {
+	var resp *SendResponse
+	req, err := j.Decode()
+	if err != nil {
+		j.Status, j.LastError, j.UpdatedAt = JobFailed, err.Error(), o.now()
+		return o.save(j)
+	}
+	resp, err = outboxKinds[j.Kind].send(ctx, o.c.Raw(), req)
+
+	j.UpdatedAt = o.now()
+	switch {
+	case err == nil:
+		j.Status, j.LastError = JobSent, ""
+		if resp != nil {
+			j.MessageID = resp.Results.MessageID
+		}
+	case ambiguousSendError(err):
+		j.Status, j.LastError = JobUnknown, err.Error()
+	case !retryableSendError(err) || j.Attempts >= o.cfg.MaxAttempts:
+		j.Status, j.LastError = JobFailed, err.Error()
+	default:
+		j.Status, j.LastError = JobPending, err.Error()
+		j.NextAt = j.UpdatedAt.Add(o.backoff(j.Attempts))
+	}
+	return o.save(j)
+}
+
+func (o *Outbox) save(j *OutboxJob) error {
+	cp := *j
+	o.mu.Lock()
+	err := o.append(&cp)
+	o.mu.Unlock()
+	if err == nil {
+		o.notify(cp)
+	}
+	return err
+}
+
+// backoff é exponencial a partir de BackoffMin, limitado a BackoffMax, com
+// jitter de até 20%.
+func (o *Outbox) backoff(attempt int) time.Duration {
+	d := o.cfg.BackoffMin << min(attempt-1, 30)
+	if d <= 0 || d > o.cfg.BackoffMax {
+		d = o.cfg.BackoffMax
+	}
+	return d + rand.N(d/5+1)
+}
+
+// retryableSendError diz se vale tentar de novo: só falhas sabidamente
+// transitórias (5xx, 408, 429, rate limit local) e erros de rede em que a
+// requisição nem chegou a ser escrita (DNS, conexão recusada). O resto,
+// inclusive erros desconhecidos de middlewares, é permanente: repetir um
+// envio sem saber por que falhou pode duplicar a mensagem.
+func retryableSendError(err error) bool {
+	var apiErr *APIError
+	switch {
+	case errors.Is(err, os.ErrNotExist), errors.Is(err, ErrUnsupportedOption):
+		return false
+	case errors.As(err, &apiErr):
+		return apiErr.StatusCode >= 500 || apiErr.StatusCode == 429 || apiErr.StatusCode == 408
+	case errors.Is(err, ErrRateLimited):
+		return true
+	default:
+		return dialError(err)
+	}
+}
+
+// ambiguousSendError diz se a falha pode ter acontecido depois de o servidor
+// receber o envio: qualquer erro de rede que não seja de conexão.
+func ambiguousSendError(err error) bool {
+	var (
+		apiErr *APIError
+		netErr net.Error
+		urlErr *url.Error
+	)
+	if errors.As(err, &apiErr) || dialError(err) {
+		return false
+	}
+	return errors.As(err, &netErr) || errors.As(err, &urlErr)
+}
+
+// dialError reconhece falhas ao abrir a conexão, antes de qualquer byte da
+// requisição ser enviado.
+func dialError(err error) bool {
+	var (
+		opErr  *net.OpError
+		dnsErr *net.DNSError
+	)
+	return (errors.As(err, &opErr) && opErr.Op == "dial") || errors.As(err, &dnsErr) ||
+		errors.Is(err, syscall.ECONNREFUSED)
+}
+
+// Compact reescreve o journal apenas com o estado atual dos jobs. Jobs
+// concluídos (sent/failed) com UpdatedAt anterior a olderThan são descartados;
+// olderThan zero mantém todos.
+func (o *Outbox) Compact(olderThan time.Time) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	// o estado em memória só muda se o journal for reescrito: numa falha, os
+	// jobs descartados continuam no arquivo e precisam continuar aqui
+	jobs := make(map[string]*OutboxJob, len(o.jobs))
+	keys := make(map[string]string, len(o.keys))
+	var recs []outboxRecord
+	for id, j := range o.jobs {
+		done := j.Status == JobSent || j.Status == JobFailed
+		if done && !olderThan.IsZero() && j.UpdatedAt.Before(olderThan) {
+			continue
+		}
+		jobs[id] = j
+		if j.Key != "" {
+			keys[j.Key] = id
+		}
+		recs = append(recs, outboxRecord{Job: j})
+	}
+	if err := o.jr.rewrite(recs); err != nil {
+		return err
+	}
+	o.jobs, o.keys = jobs, keys
+	return nil
+}
+
+func (o *Outbox) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return o.jr.close()
+}
@@ -0,0 +1,335 @@
+package gowa_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+// eventually espera cond ficar verdadeira, falhando o teste após 5s.
+func eventually(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timeout waiting for %s", what)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// runOutbox executa o Run em segundo plano até o fim do teste.
+func runOutbox(t *testing.T, o *gowa.Outbox) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		o.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func TestOutboxDelivery(t *testing.T) {
+	tests := []struct {
+		name     string
+		fault    *gowatest.Fault
+		status   gowa.JobStatus
+		attempts int
+	}{
+		{"sent", nil, gowa.JobSent, 1},
+		{"transient 500", &gowatest.Fault{Path: "/send/message", Status: 500, Times: 1}, gowa.JobSent, 2},
+		{"rate limited 429", &gowatest.Fault{Path: "/send/message", Status: 429, Times: 1}, gowa.JobSent, 2},
+		{"permanent 400", &gowatest.Fault{Path: "/send/message", Status: 400}, gowa.JobFailed, 1},
+		{"attempts exhausted", &gowatest.Fault{Path: "/send/message", Status: 503}, gowa.JobFailed, 3},
+		{"network error", nil, gowa.JobSent, 2},
+		{"unknown error", nil, gowa.JobFailed, 1},
+		{"response timeout", nil, gowa.JobUnknown, 1},
+		{"connection reset", nil, gowa.JobUnknown, 1},
+	}
+	// erros devolvidos na primeira tentativa, antes de chegar ao servidor
+	injected := map[string]error{
+		"network error": &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+		"unknown error": errors.New("middleware rejected the call"),
+	}
+	// erros devolvidos depois de o servidor receber o envio: reenviar duplicaria
+	injectedAfter := map[string]error{
+		"response timeout": &url.Error{Op: "Post", URL: "/send/message", Err: context.DeadlineExceeded},
+		"connection reset": &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := gowatest.NewServer(gowatest.Config{})
+			t.Cleanup(srv.Close)
+			if tt.fault != nil {
+				srv.AddFault(*tt.fault)
+			}
+			var calls atomic.Int32
+			c, err := gowa.New(gowa.Config{
+				BaseURL:    srv.URL,
+				Username:   "admin",
+				Password:   "admin",
+				HTTPClient: srv.Server.Client(),
+				RetryMax:   -1,
+				Middlewares: []gowa.Middleware{func(next gowa.Doer) gowa.Doer {
+					return gowa.DoerFunc(func(ctx context.Context, call *gowa.Call) error {
+						if err := injected[tt.name]; err != nil && calls.Add(1) == 1 {
+							return err
+						}
+						if err := next.Do(ctx, call); err != nil {
+							return err
+						}
+						if err := injectedAfter[tt.name]; err != nil && calls.Add(1) == 1 {
+							return err
+						}
+						return nil
+					})
+				}},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			o, err := gowa.OpenOutbox(c, gowa.OutboxConfig{
+				Path:        filepath.Join(t.TempDir(), "outbox.jsonl"),
+				MaxAttempts: 3,
+				BackoffMin:  time.Millisecond,
+				BackoffMax:  5 * time.Millisecond,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { o.Close() })
+			runOutbox(t, o)
+
+			j, err := o.Enqueue(gowa.SendMessageRequest{Phone: "5511999990000", Message: "oi"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			eventually(t, "job to finish", func() bool {
+				cur, _ := o.Job(j.ID)
+				return cur.Status == gowa.JobSent || cur.Status == gowa.JobFailed || cur.Status == gowa.JobUnknown
+			})
+			cur, _ := o.Job(j.ID)
+			if cur.Status != tt.status || cur.Attempts != tt.attempts {
+				t.Errorf("job = %s after %d attempts, want %s after %d (%s)", cur.Status, cur.Attempts, tt.status, tt.attempts, cur.LastError)
+			}
+			if tt.status == gowa.JobSent && cur.MessageID == "" {
+				t.Error("sent job without MessageID")
+			}
+		})
+	}
+}
+
+// TestOutboxConcurrentRun roda dois Run sobre a mesma fila: cada job sai uma
+// única vez.
+func TestOutboxConcurrentRun(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{})
+	t.Cleanup(srv.Close)
+	o, err := gowa.OpenOutbox(srv.GowaClient(), gowa.OutboxConfig{Path: filepath.Join(t.TempDir(), "outbox.jsonl")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { o.Close() })
+	o.Pause()
+	const n = 20
+	for i := range n {
+		if _, err := o.Enqueue(gowa.SendMessageRequest{Phone: "5511999990000", Message: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	runOutbox(t, o)
+	runOutbox(t, o)
+	o.Resume()
+	eventually(t, "all jobs sent", func() bool { return o.Counts()[gowa.JobSent] == n })
+	sent := 0
+	for _, r := range srv.Requests() {
+		if r.Path == "/send/message" {
+			sent++
+		}
+	}
+	if sent != n {
+		t.Errorf("%d requests for %d jobs", sent, n)
+	}
+	for _, j := range o.Jobs() {
+		if j.Attempts != 1 {
+			t.Errorf("job %s: %d attempts", j.ID, j.Attempts)
+		}
+	}
+}
+
+func TestOutboxEnqueueNil(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{})
+	t.Cleanup(srv.Close)
+	o, err := gowa.OpenOutbox(srv.GowaClient(), gowa.OutboxConfig{Path: filepath.Join(t.TempDir(), "outbox.jsonl")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { o.Close() })
+	if _, err := o.Enqueue(nil); err == nil {
+		t.Error("Enqueue(nil) accepted")
+	}
+}
+
+func TestOutboxEnqueueKey(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{})
+	t.Cleanup(srv.Close)
+	o, err := gowa.OpenOutbox(srv.GowaClient(), gowa.OutboxConfig{Path: filepath.Join(t.TempDir(), "outbox.jsonl")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { o.Close() })
+	a, err := o.Enqueue(gowa.SendMessageRequest{Phone: "1", Message: "a"}, gowa.EnqueueOptions{Key: "pedido-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := o.Enqueue(gowa.SendMessageRequest{Phone: "1", Message: "b"}, gowa.EnqueueOptions{Key: "pedido-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.ID != b.ID || len(o.Jobs()) != 1 {
+		t.Errorf("same key produced jobs %s and %s (%d total)", a.ID, b.ID, len(o.Jobs()))
+	}
+}
+
+// TestOutboxCrashRecovery simula uma queda no meio do envio: o journal termina
+// com o job em "sending" e uma linha truncada.
+func TestOutboxCrashRecovery(t *testing.T) {
+	tests := []struct {
+		resend bool
+		want   gowa.JobStatus
+	}{
+		{false, gowa.JobUnknown},
+		{true, gowa.JobSent},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("resend=%v", tt.resend), func(t *testing.T) {
+			srv := gowatest.NewServer(gowatest.Config{})
+			t.Cleanup(srv.Close)
+			path := filepath.Join(t.TempDir(), "outbox.jsonl")
+			o, err := gowa.OpenOutbox(srv.GowaClient(), gowa.OutboxConfig{Path: path})
+			if err != nil {
+				t.Fatal(err)
+			}
+			j, err := o.Enqueue(gowa.SendMessageRequest{Phone: "5511999990000", Message: "oi"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			o.Close()
+
+			j.Status, j.Attempts = gowa.JobSending, 1
+			line, _ := json.Marshal(map[string]any{"job": j})
+			f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f.Write(append(line, '\n'))
+			f.Write([]byte(`{"job":{"id":"trunc`))
+			f.Close()
+
+			o, err = gowa.OpenOutbox(srv.GowaClient(), gowa.OutboxConfig{Path: path, ResendInFlight: tt.resend})
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { o.Close() })
+			if tt.resend {
+				runOutbox(t, o)
+				eventually(t, "resend", func() bool {
+					cur, _ := o.Job(j.ID)
+					return cur.Status == tt.want
+				})
+			}
+			cur, ok := o.Job(j.ID)
+			if !ok || cur.Status != tt.want {
+				t.Errorf("job = %+v, want %s", cur, tt.want)
+			}
+			if n := len(o.Jobs()); n != 1 {
+				t.Errorf("%d jobs after recovery, want 1", n)
+			}
+			if !tt.resend {
+				if _, sent := srv.LastRequest("/send/message"); sent {
+					t.Error("job in unknown state was resent")
+				}
+				if err := o.Retry(j.ID); err != nil {
+					t.Errorf("Retry: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestOutboxCompact(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{})
+	t.Cleanup(srv.Close)
+	dir := filepath.Join(t.TempDir(), "outbox")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "outbox.jsonl")
+	o, err := gowa.OpenOutbox(srv.GowaClient(), gowa.OutboxConfig{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { o.Close() })
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		o.Run(ctx)
+	}()
+	sent, _ := o.Enqueue(gowa.SendMessageRequest{Phone: "5511999990000", Message: "oi"}, gowa.EnqueueOptions{Key: "k1"})
+	eventually(t, "job sent", func() bool { j, _ := o.Job(sent.ID); return j.Status == gowa.JobSent })
+	cancel()
+	<-done
+	pending, _ := o.Enqueue(gowa.SendMessageRequest{Phone: "5511999990000", Message: "depois"})
+
+	// rewrite falha (diretório removido): nada sai da memória
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Compact(time.Now().Add(time.Hour)); err == nil {
+		t.Fatal("Compact succeeded without the journal directory")
+	}
+	if _, ok := o.Job(sent.ID); !ok {
+		t.Error("failed Compact dropped the sent job")
+	}
+	if again, _ := o.Enqueue(gowa.SendMessageRequest{Phone: "5511999990000", Message: "oi"}, gowa.EnqueueOptions{Key: "k1"}); again.ID != sent.ID {
+		t.Error("failed Compact dropped the idempotency key")
+	}
+
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Compact(time.Now().Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := o.Job(sent.ID); ok {
+		t.Error("sent job kept after Compact")
+	}
+	if _, ok := o.Job(pending.ID); !ok {
+		t.Error("pending job dropped by Compact")
+	}
+	o.Close()
+	o2, err := gowa.OpenOutbox(srv.GowaClient(), gowa.OutboxConfig{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { o2.Close() })
+	if jobs := o2.Jobs(); len(jobs) != 1 || jobs[0].ID != pending.ID {
+		t.Errorf("reopened journal = %+v, want only the pending job", jobs)
+	}
+}
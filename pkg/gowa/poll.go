@@ -0,0 +1,287 @@
+package gowa
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	ErrPollClosed      = errors.New("gowa: poll is closed")
+	ErrUnknownPoll     = errors.New("gowa: unknown poll")
+	ErrUnknownOption   = errors.New("gowa: unknown poll option")
+	ErrTooManyAnswers  = errors.New("gowa: too many poll answers")
+	errPollOptionCount = errors.New("gowa: poll needs at least 2 distinct options")
+)
+
+// PollUpdate é um voto recebido (evento de atualização de enquete do
+// webhook). Options é a seleção completa do eleitor naquele momento: vazia
+// retira o voto. O WhatsApp envia as opções como SHA-256 do texto; Poll aceita
+// tanto o texto quanto o hash em hexadecimal. O client não recebe webhooks e
+// o spec não descreve o payload do evento: cabe à aplicação montar o
+// PollUpdate a partir do que o seu servidor gowa entrega.
+type PollUpdate struct {
+	PollID    string    `json:"poll_id"` // message_id da enquete
+	Voter     string    `json:"voter"`   // JID de quem votou
+	Options   []string  `json:"options"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Poll é uma enquete enviada, com a apuração dos votos por eleitor.
+type Poll struct {
+	MessageID string
+	ChatJID   string
+	Question  string
+	// Options é informativo: a apuração usa a cópia feita em NewPoll, então
+	// alterar este slice não muda Results.
+	Options   []string
+	MaxAnswer int
+	Clock     Clock // UpdatedAt dos votos sem Timestamp; default relógio do sistema
+
+	mu      sync.Mutex
+	options []string       // opções da apuração, na ordem da enquete
+	index   map[string]int // texto e hash de cada opção → posição
+	votes   map[string]pollVote
+	closed  bool
+	updated time.Time
+}
+
+type pollVote struct {
+	options []int
+	at      time.Time // Timestamp do evento mais recente com um; zero se nenhum
+}
+
+// NewPoll cria uma enquete local (ainda não enviada). maxAnswer 0 vale 1.
+func NewPoll(question string, options []string, maxAnswer int) (*Poll, error) {
+	if strings.TrimSpace(question) == "" {
+		return nil, errors.New("gowa: poll question required")
+	}
+	// cópia: alterar o slice do chamador não pode desalinhar a apuração
+	options = slices.Clone(options)
+	p := &Poll{Question: question, Options: slices.Clone(options), MaxAnswer: maxAnswer, options: options, index: map[string]int{}, votes: map[string]pollVote{}}
+	for i, o := range options {
+		if _, dup := p.index[o]; dup || o == "" {
+			return nil, errPollOptionCount
+		}
+		sum := sha256.Sum256([]byte(o))
+		p.index[o] = i
+		p.index[hex.EncodeToString(sum[:])] = i
+	}
+	if len(options) < 2 {
+		return nil, errPollOptionCount
+	}
+	if p.MaxAnswer <= 0 {
+		p.MaxAnswer = 1
+	}
+	if p.MaxAnswer > len(options) {
+		return nil, fmt.Errorf("gowa: max answer %d exceeds %d options", p.MaxAnswer, len(options))
+	}
+	return p, nil
+}
+
+// CreatePoll envia a enquete e retorna o Poll com MessageID e ChatJID
+// preenchidos, pronto para receber votos.
+func (c *Client) CreatePoll(ctx context.Context, phone, question string, options []string, maxAnswer int, opts ...SendOption) (*Poll, error) {
+	p, err := NewPoll(question, options, maxAnswer)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.SendPoll(ctx, SendPollParams{Phone: phone, Question: question, Options: options, MaxAnswer: p.MaxAnswer}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	p.MessageID, p.ChatJID = resp.Results.MessageID, phone
+	return p, nil
+}
+
+// Vote registra a seleção completa de voter, substituindo a anterior; sem
+// opções, retira o voto. Só altera a apuração local: nada é enviado ao
+// WhatsApp, que não tem operação de voto na API do gowa. Use Vote para votos
+// coletados por outros canais e Apply para os eventos recebidos.
+func (p *Poll) Vote(voter string, options ...string) error {
+	return p.Apply(PollUpdate{PollID: p.MessageID, Voter: voter, Options: options})
+}
+
+// Apply processa um evento de voto. Eventos mais antigos que o último já
+// aplicado para o mesmo eleitor são ignorados (entrega fora de ordem); só
+// eventos com Timestamp são comparados, e sem ele vale a ordem de chegada. Retorna ErrTooManyAnswers se a seleção
+// passar de MaxAnswer e ErrUnknownOption para opção inexistente; nesses casos
+// o voto anterior é mantido.
+func (p *Poll) Apply(u PollUpdate) error {
+	if u.Voter == "" {
+		return errors.New("gowa: poll vote without voter")
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.MessageID != "" && u.PollID != "" && u.PollID != p.MessageID {
+		return fmt.Errorf("%w: %s", ErrUnknownPoll, u.PollID)
+	}
+	if p.closed {
+		return ErrPollClosed
+	}
+	prev := p.votes[u.Voter]
+	if !u.Timestamp.IsZero() && u.Timestamp.Before(prev.at) {
+		return nil
+	}
+	seen := map[int]bool{}
+	var sel []int
+	for _, o := range u.Options {
+		i, ok := p.index[o]
+		if !ok {
+			i, ok = p.index[strings.ToLower(o)] // hash em maiúsculas
+		}
+		if !ok {
+			return fmt.Errorf("%w: %q", ErrUnknownOption, o)
+		}
+		if !seen[i] {
+			seen[i] = true
+			sel = append(sel, i)
+		}
+	}
+	if len(sel) > p.MaxAnswer {
+		return fmt.Errorf("%w: %d > %d", ErrTooManyAnswers, len(sel), p.MaxAnswer)
+	}
+	// um voto sem Timestamp (Vote) não ganha a hora local, que descartaria
+	// eventos do webhook com hora real anterior; fica a do último evento
+	at, updated := u.Timestamp, u.Timestamp
+	if at.IsZero() {
+		at, updated = prev.at, p.now()
+	}
+	// voto retirado fica com seleção vazia, para descartar eventos antigos
+	// que cheguem depois
+	sort.Ints(sel)
+	p.votes[u.Voter] = pollVote{options: sel, at: at}
+	p.updated = updated
+	return nil
+}
+
+func (p *Poll) now() time.Time {
+	if p.Clock == nil {
+		return time.Now()
+	}
+	return p.Clock.Now()
+}
+
+// Close encerra a enquete: votos posteriores retornam ErrPollClosed e
+// Results passa a refletir o resultado final.
+func (p *Poll) Close() {
+	p.mu.Lock()
+	p.closed = true
+	p.mu.Unlock()
+}
+
+// PollResults é um retrato da apuração, pronto para serializar em painéis.
+type PollResults struct {
+	MessageID string              `json:"message_id,omitempty"`
+	Question  string              `json:"question"`
+	Options   []PollOptionResult  `json:"options"`
+	Voters    int                 `json:"voters"`
+	Closed    bool                `json:"closed"`
+	UpdatedAt time.Time           `json:"updated_at,omitempty"`
+	Ballots   map[string][]string `json:"ballots"` // eleitor → opções escolhidas
+}
+
+type PollOptionResult struct {
+	Option string   `json:"option"`
+	Votes  int      `json:"votes"`
+	Voters []string `json:"voters"`
+}
+
+// Results retorna a apuração atual. As opções seguem a ordem da enquete e os
+// eleitores de cada opção vêm ordenados.
+func (p *Poll) Results() PollResults {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	r := PollResults{
+		MessageID: p.MessageID,
+		Question:  p.Question,
+		Options:   make([]PollOptionResult, len(p.options)),
+		Closed:    p.closed,
+		UpdatedAt: p.updated,
+		Ballots:   map[string][]string{},
+	}
+	for i, o := range p.options {
+		r.Options[i] = PollOptionResult{Option: o, Voters: []string{}}
+	}
+	for voter, v := range p.votes {
+		if len(v.options) > 0 {
+			r.Voters++
+		}
+		for _, i := range v.options {
+			r.Options[i].Votes++
+			r.Options[i].Voters = append(r.Options[i].Voters, voter)
+			r.Ballots[voter] = append(r.Ballots[voter], p.options[i])
+		}
+	}
+	for i := range r.Options {
+		sort.Strings(r.Options[i].Voters)
+	}
+	return r
+}
+
+// Winners retorna as opções mais votadas (mais de uma em caso de empate);
+// vazio se ninguém votou.
+func (r PollResults) Winners() []string {
+	best := 0
+	var out []string
+	for _, o := range r.Options {
+		switch {
+		case o.Votes > best:
+			best, out = o.Votes, []string{o.Option}
+		case o.Votes == best && best > 0:
+			out = append(out, o.Option)
+		}
+	}
+	return out
+}
+
+// PollTracker apura várias enquetes, encaminhando cada evento para a enquete
+// pelo message_id.
+type PollTracker struct {
+	mu    sync.Mutex
+	polls map[string]*Poll
+}
+
+func NewPollTracker() *PollTracker { return &PollTracker{polls: map[string]*Poll{}} }
+
+// Track passa a apurar p, que precisa ter MessageID (veja Client.CreatePoll).
+func (t *PollTracker) Track(p *Poll) error {
+	if p.MessageID == "" {
+		return errors.New("gowa: poll without message id")
+	}
+	t.mu.Lock()
+	t.polls[p.MessageID] = p
+	t.mu.Unlock()
+	return nil
+}
+
+// Forget deixa de apurar a enquete.
+func (t *PollTracker) Forget(messageID string) {
+	t.mu.Lock()
+	delete(t.polls, messageID)
+	t.mu.Unlock()
+}
+
+func (t *PollTracker) Poll(messageID string) (*Poll, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p, ok := t.polls[messageID]
+	return p, ok
+}
+
+// Apply encaminha o evento; retorna ErrUnknownPoll se a enquete não estiver
+// sendo apurada.
+func (t *PollTracker) Apply(u PollUpdate) error {
+	p, ok := t.Poll(u.PollID)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownPoll, u.PollID)
+	}
+	return p.Apply(u)
+}
@@ -0,0 +1,224 @@
+package gowa_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+func optionHash(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestPollApply(t *testing.T) {
+	t0 := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		max     int
+		updates []gowa.PollUpdate
+		err     error // do último update
+		ballots map[string][]string
+	}{
+		{
+			"text and hash",
+			1,
+			[]gowa.PollUpdate{
+				{Voter: "a", Options: []string{"Pizza"}},
+				{Voter: "b", Options: []string{optionHash("Sushi")}},
+			},
+			nil,
+			map[string][]string{"a": {"Pizza"}, "b": {"Sushi"}},
+		},
+		{
+			"vote replaced",
+			2,
+			[]gowa.PollUpdate{
+				{Voter: "a", Options: []string{"Pizza"}, Timestamp: t0},
+				{Voter: "a", Options: []string{"Sushi", "Taco", "Sushi"}, Timestamp: t0.Add(time.Second)},
+			},
+			nil,
+			map[string][]string{"a": {"Sushi", "Taco"}},
+		},
+		{
+			"out of order ignored",
+			1,
+			[]gowa.PollUpdate{
+				{Voter: "a", Options: []string{"Taco"}, Timestamp: t0.Add(time.Minute)},
+				{Voter: "a", Options: []string{"Pizza"}, Timestamp: t0},
+			},
+			nil,
+			map[string][]string{"a": {"Taco"}},
+		},
+		{
+			"vote retracted",
+			1,
+			[]gowa.PollUpdate{
+				{Voter: "a", Options: []string{"Pizza"}, Timestamp: t0},
+				{Voter: "a", Timestamp: t0.Add(time.Second)},
+			},
+			nil,
+			map[string][]string{},
+		},
+		{
+			// Vote não tem hora do WhatsApp: não pode descartar o evento do
+			// webhook com hora real, ainda que anterior ao relógio local
+			"vote without timestamp then webhook event",
+			1,
+			[]gowa.PollUpdate{
+				{Voter: "a", Options: []string{"Pizza"}},
+				{Voter: "a", Options: []string{"Sushi"}, Timestamp: t0},
+			},
+			nil,
+			map[string][]string{"a": {"Sushi"}},
+		},
+		{
+			"stale event after vote without timestamp",
+			1,
+			[]gowa.PollUpdate{
+				{Voter: "a", Options: []string{"Taco"}, Timestamp: t0.Add(time.Minute)},
+				{Voter: "a", Options: []string{"Pizza"}},
+				{Voter: "a", Options: []string{"Sushi"}, Timestamp: t0},
+			},
+			nil,
+			map[string][]string{"a": {"Pizza"}},
+		},
+		{
+			"too many answers keeps previous",
+			1,
+			[]gowa.PollUpdate{
+				{Voter: "a", Options: []string{"Pizza"}},
+				{Voter: "a", Options: []string{"Sushi", "Taco"}},
+			},
+			gowa.ErrTooManyAnswers,
+			map[string][]string{"a": {"Pizza"}},
+		},
+		{
+			"unknown option",
+			1,
+			[]gowa.PollUpdate{{Voter: "a", Options: []string{"Lasanha"}}},
+			gowa.ErrUnknownOption,
+			map[string][]string{},
+		},
+		{
+			"other poll",
+			1,
+			[]gowa.PollUpdate{{PollID: "outra", Voter: "a", Options: []string{"Pizza"}}},
+			gowa.ErrUnknownPoll,
+			map[string][]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := gowa.NewPoll("Almoço?", []string{"Pizza", "Sushi", "Taco"}, tt.max)
+			if err != nil {
+				t.Fatal(err)
+			}
+			p.MessageID = "poll-1"
+			for i, u := range tt.updates {
+				if u.PollID == "" {
+					u.PollID = p.MessageID
+				}
+				err = p.Apply(u)
+				if i < len(tt.updates)-1 && err != nil {
+					t.Fatalf("update %d: %v", i, err)
+				}
+			}
+			if !errors.Is(err, tt.err) {
+				t.Errorf("err = %v, want %v", err, tt.err)
+			}
+			if got := p.Results().Ballots; !reflect.DeepEqual(got, tt.ballots) {
+				t.Errorf("ballots = %v, want %v", got, tt.ballots)
+			}
+		})
+	}
+}
+
+func TestNewPollErrors(t *testing.T) {
+	for _, tt := range []struct {
+		question string
+		options  []string
+		max      int
+	}{
+		{"", []string{"a", "b"}, 1},
+		{"q", []string{"a"}, 1},
+		{"q", []string{"a", "a"}, 1},
+		{"q", []string{"a", ""}, 1},
+		{"q", []string{"a", "b"}, 3},
+	} {
+		if _, err := gowa.NewPoll(tt.question, tt.options, tt.max); err == nil {
+			t.Errorf("NewPoll(%q, %q, %d): expected error", tt.question, tt.options, tt.max)
+		}
+	}
+}
+
+func TestPollResults(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	p, err := srv.GowaClient().CreatePoll(context.Background(), "5511999990000", "Almoço?", []string{"Pizza", "Sushi"}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := gowa.NewPollTracker()
+	if err := tr.Track(p); err != nil {
+		t.Fatal(err)
+	}
+	for _, u := range []gowa.PollUpdate{
+		{PollID: p.MessageID, Voter: "b", Options: []string{"Pizza"}},
+		{PollID: p.MessageID, Voter: "a", Options: []string{"Pizza"}},
+		{PollID: p.MessageID, Voter: "c", Options: []string{"Sushi"}},
+	} {
+		if err := tr.Apply(u); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tr.Apply(gowa.PollUpdate{PollID: "x", Voter: "a"}); !errors.Is(err, gowa.ErrUnknownPoll) {
+		t.Errorf("unknown poll: err = %v", err)
+	}
+	p.Close()
+	if err := p.Vote("d", "Sushi"); !errors.Is(err, gowa.ErrPollClosed) {
+		t.Errorf("vote after close: err = %v", err)
+	}
+	r := p.Results()
+	if r.Voters != 3 || !r.Closed || !reflect.DeepEqual(r.Options[0].Voters, []string{"a", "b"}) {
+		t.Errorf("results = %+v", r)
+	}
+	if w := r.Winners(); !reflect.DeepEqual(w, []string{"Pizza"}) {
+		t.Errorf("winners = %v", w)
+	}
+}
+
+func TestPollLocal(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	options := []string{"Pizza", "Sushi"}
+	p, err := srv.GowaClient().CreatePoll(context.Background(), "5511999990000", "Almoço?", options, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options[0] = "Salada" // o Poll guarda a própria cópia
+	p.Options[1] = "Sopa" // e a apuração não usa o campo exportado
+	t0 := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	p.Clock = gowatest.NewClock(t0)
+	sent := len(srv.Requests())
+	if err := p.Vote("a", "Pizza"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(srv.Requests()); n != sent {
+		t.Errorf("Vote made %d requests, want none", n-sent)
+	}
+	r := p.Results()
+	if r.Options[0].Option != "Pizza" || r.Options[0].Votes != 1 || r.Options[1].Option != "Sushi" {
+		t.Errorf("options = %+v", r.Options)
+	}
+	if !r.UpdatedAt.Equal(t0) {
+		t.Errorf("UpdatedAt = %s, want %s (Clock)", r.UpdatedAt, t0)
+	}
+}
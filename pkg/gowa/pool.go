@@ -0,0 +1,240 @@
+package gowa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PoolAccount é uma instância do servidor gowa (um número de WhatsApp).
+type PoolAccount struct {
+	Name   string
+	Client *Client
+	Backup string // conta usada quando esta estiver deslogada ou fora do ar
+}
+
+// RouteRule direciona destinatários para uma conta. As regras são avaliadas
+// na ordem em que aparecem em PoolConfig.Rules.
+type RouteRule struct {
+	Account string
+	Match   func(recipient string) bool
+}
+
+// MatchPrefix casa destinatários cujo JID começa com algum dos prefixos
+// (ex: código do país ou DDD).
+func MatchPrefix(prefixes ...string) func(string) bool {
+	return func(r string) bool {
+		for _, p := range prefixes {
+			if strings.HasPrefix(r, p) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// MatchGroups casa JIDs de grupo (@g.us).
+func MatchGroups() func(string) bool {
+	return func(r string) bool { return strings.HasSuffix(r, "@g.us") }
+}
+
+type PoolConfig struct {
+	Accounts []PoolAccount
+	Rules    []RouteRule
+	Default  string // conta usada quando nenhuma regra casa; default a primeira
+	// HealthInterval é o intervalo dos Supervisors de cada conta; default 15s.
+	HealthInterval time.Duration
+	OnStateChange  func(account string, ev StateChange)
+}
+
+// Pool reúne Clients nomeados, roteia envios por conta ou destinatário e
+// faz failover para a conta reserva quando uma instância cai.
+type Pool struct {
+	cfg      PoolConfig
+	accounts map[string]*PoolAccount
+	sups     map[string]*Supervisor
+}
+
+var ErrUnknownAccount = errors.New("unknown account")
+
+func NewPool(cfg PoolConfig) (*Pool, error) {
+	if len(cfg.Accounts) == 0 {
+		return nil, errors.New("at least one account is required")
+	}
+	p := &Pool{cfg: cfg, accounts: map[string]*PoolAccount{}, sups: map[string]*Supervisor{}}
+	for _, a := range cfg.Accounts {
+		if a.Name == "" || a.Client == nil {
+			return nil, errors.New("account name and client are required")
+		}
+		if _, dup := p.accounts[a.Name]; dup {
+			return nil, fmt.Errorf("duplicate account %q", a.Name)
+		}
+		p.accounts[a.Name] = &a
+	}
+	for _, a := range p.accounts {
+		if a.Backup != "" && p.accounts[a.Backup] == nil {
+			return nil, fmt.Errorf("account %q: backup %q: %w", a.Name, a.Backup, ErrUnknownAccount)
+		}
+		name := a.Name
+		sup, err := NewSupervisor(a.Client, SupervisorConfig{
+			Interval: cfg.HealthInterval,
+			OnStateChange: func(ev StateChange) {
+				if cfg.OnStateChange != nil {
+					cfg.OnStateChange(name, ev)
+				}
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		p.sups[name] = sup
+	}
+	for _, r := range cfg.Rules {
+		if p.accounts[r.Account] == nil || r.Match == nil {
+			return nil, fmt.Errorf("rule for %q: %w", r.Account, ErrUnknownAccount)
+		}
+	}
+	if p.cfg.Default == "" {
+		p.cfg.Default = cfg.Accounts[0].Name
+	} else if p.accounts[p.cfg.Default] == nil {
+		return nil, fmt.Errorf("default %q: %w", p.cfg.Default, ErrUnknownAccount)
+	}
+	return p, nil
+}
+
+// Run executa os health checks (e reconexões) de todas as contas até ctx
+// terminar.
+func (p *Pool) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, sup := range p.sups {
+		wg.Add(1)
+		go func(s *Supervisor) {
+			defer wg.Done()
+			s.Run(ctx)
+		}(sup)
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+// State retorna o último estado observado da conta.
+func (p *Pool) State(account string) SessionState {
+	if s := p.sups[account]; s != nil {
+		return s.State()
+	}
+	return StateUnknown
+}
+
+// Client retorna o client da conta, sem failover.
+func (p *Pool) Client(account string) (*Client, error) {
+	a := p.accounts[account]
+	if a == nil {
+		return nil, fmt.Errorf("%q: %w", account, ErrUnknownAccount)
+	}
+	return a.Client, nil
+}
+
+// ByAccount retorna o client da conta ou, se ela estiver fora do ar ou
+// deslogada, o da conta reserva. O nome da conta efetivamente usada é
+// retornado junto.
+func (p *Pool) ByAccount(account string) (*Client, string, error) {
+	a := p.accounts[account]
+	if a == nil {
+		return nil, "", fmt.Errorf("%q: %w", account, ErrUnknownAccount)
+	}
+	if a.Backup != "" && !usable(p.State(a.Name)) && usable(p.State(a.Backup)) {
+		return p.accounts[a.Backup].Client, a.Backup, nil
+	}
+	return a.Client, a.Name, nil
+}
+
+// ForRecipient escolhe a conta pelas regras (ou Default) e aplica o failover
+// de ByAccount.
+func (p *Pool) ForRecipient(recipient string) (*Client, string, error) {
+	for _, r := range p.cfg.Rules {
+		if r.Match(recipient) {
+			return p.ByAccount(r.Account)
+		}
+	}
+	return p.ByAccount(p.cfg.Default)
+}
+
+// usable considera Unknown utilizável: antes do primeiro probe não há motivo
+// para desviar o tráfego.
+func usable(s SessionState) bool {
+	return s == StateConnected || s == StateUnknown
+}
+
+// Each executa fn em todas as contas em paralelo e agrega os erros.
+func (p *Pool) Each(ctx context.Context, fn func(ctx context.Context, account string, c *Client) error) error {
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	for name, a := range p.accounts {
+		wg.Add(1)
+		go func(name string, c *Client) {
+			defer wg.Done()
+			if err := fn(ctx, name, c); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", name, err))
+				mu.Unlock()
+			}
+		}(name, a.Client)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// AccountChat é um chat acompanhado da conta que o possui.
+type AccountChat struct {
+	Account string
+	Chat
+}
+
+// ListChats agrega os chats de todas as contas. Contas que falham não
+// impedem o retorno das demais.
+func (p *Pool) ListChats(ctx context.Context) ([]AccountChat, error) {
+	var (
+		mu  sync.Mutex
+		out []AccountChat
+	)
+	err := p.Each(ctx, func(ctx context.Context, account string, c *Client) error {
+		chats, err := c.allChats(ctx, 100)
+		mu.Lock()
+		defer mu.Unlock()
+		for _, ch := range chats {
+			out = append(out, AccountChat{Account: account, Chat: ch})
+		}
+		return err
+	})
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].Account != out[j].Account {
+			return out[i].Account < out[j].Account
+		}
+		return out[i].JID < out[j].JID
+	})
+	return out, err
+}
+
+// Devices agrega os dispositivos conectados de cada conta.
+func (p *Pool) Devices(ctx context.Context) (map[string][]Device, error) {
+	var mu sync.Mutex
+	out := map[string][]Device{}
+	err := p.Each(ctx, func(ctx context.Context, account string, c *Client) error {
+		resp, err := c.Devices(ctx)
+		if err != nil {
+			return err
+		}
+		mu.Lock()
+		out[account] = resp.Results
+		mu.Unlock()
+		return nil
+	})
+	return out, err
+}
@@ -0,0 +1,116 @@
+package gowa_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+func TestPoolFailover(t *testing.T) {
+	tests := []struct {
+		name string
+		fail func(srv *gowatest.Server) // derruba a conta principal
+		heal func(srv *gowatest.Server) // nil quando não volta
+	}{
+		{
+			name: "5xx",
+			fail: func(srv *gowatest.Server) {
+				srv.AddFault(gowatest.Fault{Status: http.StatusBadGateway})
+			},
+			heal: func(srv *gowatest.Server) { srv.ClearFaults() },
+		},
+		{
+			name: "down",
+			fail: func(srv *gowatest.Server) { srv.Close() },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			main := gowatest.NewServer(gowatest.Config{})
+			defer main.Close()
+			backup := gowatest.NewServer(gowatest.Config{})
+			defer backup.Close()
+			pool, err := gowa.NewPool(gowa.PoolConfig{
+				Accounts: []gowa.PoolAccount{
+					{Name: "main", Client: main.GowaClient(), Backup: "backup"},
+					{Name: "backup", Client: backup.GowaClient()},
+				},
+				HealthInterval: 10 * time.Millisecond,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				pool.Run(ctx)
+			}()
+			defer func() {
+				cancel()
+				<-done
+			}()
+
+			eventually(t, "both accounts connected", func() bool {
+				return pool.State("main") == gowa.StateConnected && pool.State("backup") == gowa.StateConnected
+			})
+			if _, name, _ := pool.ForRecipient("5511999990000"); name != "main" {
+				t.Fatalf("healthy pool routed to %s", name)
+			}
+
+			tt.fail(main)
+			eventually(t, "main server down", func() bool { return pool.State("main") == gowa.StateServerDown })
+			c, name, err := pool.ForRecipient("5511999990000")
+			if err != nil || name != "backup" {
+				t.Fatalf("ForRecipient = %s, %v; want backup", name, err)
+			}
+			if _, err := c.SendMessage(ctx, "5511999990000", "oi"); err != nil {
+				t.Fatalf("send through backup: %v", err)
+			}
+			if _, ok := backup.LastRequest("/send/message"); !ok {
+				t.Error("message not sent by the backup server")
+			}
+
+			if tt.heal == nil {
+				return
+			}
+			tt.heal(main)
+			eventually(t, "main server back", func() bool { return pool.State("main") == gowa.StateConnected })
+			if _, name, _ := pool.ForRecipient("5511999990000"); name != "main" {
+				t.Errorf("recovered pool routed to %s, want main", name)
+			}
+		})
+	}
+}
+
+func TestPoolRouting(t *testing.T) {
+	a := gowatest.NewServer(gowatest.Config{})
+	defer a.Close()
+	b := gowatest.NewServer(gowatest.Config{})
+	defer b.Close()
+	pool, err := gowa.NewPool(gowa.PoolConfig{
+		Accounts: []gowa.PoolAccount{{Name: "sp", Client: a.GowaClient()}, {Name: "grupos", Client: b.GowaClient()}},
+		Rules:    []gowa.RouteRule{{Account: "grupos", Match: gowa.MatchGroups()}, {Account: "sp", Match: gowa.MatchPrefix("5511")}},
+		Default:  "grupos",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for recipient, want := range map[string]string{
+		"5511999990000":     "sp",
+		"120363000000@g.us": "grupos",
+		"5521999990000":     "grupos", // Default
+	} {
+		if _, name, _ := pool.ForRecipient(recipient); name != want {
+			t.Errorf("ForRecipient(%s) = %s, want %s", recipient, name, want)
+		}
+	}
+	if _, _, err := pool.ByAccount("rj"); err == nil {
+		t.Error("unknown account accepted")
+	}
+}
@@ -0,0 +1,225 @@
+package gowa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrRateLimited é retornado pelos envios quando RateLimitConfig.FailFast está
+// ligado e não há orçamento disponível. O erro concreto é *RateLimitError.
+var ErrRateLimited = errors.New("gowa: rate limited")
+
+// RateLimitError informa qual orçamento bloqueou o envio e quanto seria
+// preciso esperar.
+type RateLimitError struct {
+	Scope string // global, recipient ou group
+	Wait  time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("%v (%s, retry in %s)", ErrRateLimited, e.Scope, e.Wait.Round(time.Millisecond))
+}
+
+func (e *RateLimitError) Unwrap() error { return ErrRateLimited }
+
+// RateLimit é um token bucket: Rate envios por Every, acumulando até Burst.
+// O valor zero não limita.
+type RateLimit struct {
+	Rate  int
+	Every time.Duration
+	Burst int // default Rate
+}
+
+// PerMinute é um atalho para RateLimit{Rate: n, Every: time.Minute, Burst: burst}.
+func PerMinute(n, burst int) RateLimit {
+	return RateLimit{Rate: n, Every: time.Minute, Burst: burst}
+}
+
+func (l RateLimit) enabled() bool { return l.Rate > 0 && l.Every > 0 }
+
+// RateLimitConfig controla o ritmo dos envios (POST /send/*, exceto
+// /send/presence e /send/chat-presence, que não entregam mensagem). Os
+// orçamentos são combinados: um envio para um grupo consome do global e do
+// grupo; para um contato, do global e do destinatário. O destinatário é
+// identificado pelo número: "5511…", "+5511…" e "5511…@s.whatsapp.net"
+// dividem o mesmo orçamento.
+type RateLimitConfig struct {
+	Global       RateLimit
+	PerRecipient RateLimit // por JID de usuário
+	PerGroup     RateLimit // por JID de grupo (@g.us)
+
+	// Jitter soma um atraso aleatório em [0, Jitter) a cada envio, para que
+	// rajadas não saiam em intervalos perfeitamente regulares.
+	Jitter time.Duration
+
+	// FailFast retorna ErrRateLimited em vez de esperar pelo orçamento. Sem
+	// ele, o envio bloqueia até haver tokens ou o ctx ser cancelado.
+	FailFast bool
+}
+
+// rateLimited diz se a chamada consome orçamento: só os envios de mensagem.
+// Os indicadores de presença ("digitando…") acompanham os envios e não podem
+// disputar o orçamento com eles.
+func rateLimited(call *Call) bool {
+	switch call.Path {
+	case "/send/presence", "/send/chat-presence":
+		return false
+	}
+	return strings.HasPrefix(call.Path, "/send/")
+}
+
+// recipientKey normaliza o destinatário para a chave do bucket: grupos pelo
+// JID, contatos pelo número (sem "+", servidor ou sufixo de dispositivo).
+func recipientKey(recipient string) string {
+	recipient = strings.TrimSpace(recipient)
+	if strings.HasSuffix(recipient, "@g.us") {
+		return recipient
+	}
+	return mentionUser(recipient)
+}
+
+// rateLimiter aplica RateLimitConfig. Os buckets por destinatário são criados
+// sob demanda e descartados quando voltam a ficar cheios.
+type rateLimiter struct {
+	cfg RateLimitConfig
+	now func() time.Time
+
+	mu      sync.Mutex
+	global  *bucket
+	buckets map[string]*bucket
+	calls   int
+}
+
+func newRateLimiter(cfg RateLimitConfig) *rateLimiter {
+	l := &rateLimiter{cfg: cfg, now: time.Now, buckets: map[string]*bucket{}}
+	if cfg.Global.enabled() {
+		l.global = newBucket(cfg.Global, l.now())
+	}
+	return l
+}
+
+// sweepEvery define a cada quantas reservas os buckets cheios são removidos.
+const sweepEvery = 256
+
+// wait reserva um token em cada orçamento aplicável ao destinatário e espera
+// o necessário. Retorna quanto esperou.
+func (l *rateLimiter) wait(ctx context.Context, recipient string) (time.Duration, error) {
+	l.mu.Lock()
+	now := l.now()
+	type hold struct {
+		scope string
+		b     *bucket
+	}
+	var holds []hold
+	if l.global != nil {
+		holds = append(holds, hold{"global", l.global})
+	}
+	if recipient = recipientKey(recipient); recipient != "" {
+		scope, lim := "recipient", l.cfg.PerRecipient
+		if strings.HasSuffix(recipient, "@g.us") {
+			scope, lim = "group", l.cfg.PerGroup
+		}
+		if lim.enabled() {
+			b := l.buckets[recipient]
+			if b == nil {
+				b = newBucket(lim, now)
+				l.buckets[recipient] = b
+			}
+			holds = append(holds, hold{scope, b})
+		}
+	}
+
+	var delay time.Duration
+	var scope string
+	for _, h := range holds {
+		if d := h.b.delay(now); d > delay {
+			delay, scope = d, h.scope
+		}
+	}
+	if delay > 0 && l.cfg.FailFast {
+		l.mu.Unlock()
+		return 0, &RateLimitError{Scope: scope, Wait: delay}
+	}
+	for _, h := range holds {
+		h.b.take()
+	}
+	if l.calls++; l.calls%sweepEvery == 0 {
+		l.sweep(now)
+	}
+	l.mu.Unlock()
+
+	if l.cfg.Jitter > 0 {
+		delay += rand.N(l.cfg.Jitter)
+	}
+	if delay <= 0 {
+		return 0, nil
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return delay, nil
+	case <-ctx.Done():
+		// devolve os tokens reservados: o envio não vai acontecer
+		l.mu.Lock()
+		for _, h := range holds {
+			h.b.give()
+		}
+		l.mu.Unlock()
+		return 0, ctx.Err()
+	}
+}
+
+func (l *rateLimiter) sweep(now time.Time) {
+	for k, b := range l.buckets {
+		if b.full(now) {
+			delete(l.buckets, k)
+		}
+	}
+}
+
+// bucket é um token bucket que aceita reservas: tokens pode ficar negativo,
+// e o déficit é o tempo de espera dos próximos envios.
+type bucket struct {
+	perToken time.Duration
+	burst    float64
+	tokens   float64
+	last     time.Time
+}
+
+func newBucket(l RateLimit, now time.Time) *bucket {
+	burst := l.Burst
+	if burst <= 0 {
+		burst = l.Rate
+	}
+	return &bucket{perToken: l.Every / time.Duration(l.Rate), burst: float64(burst), tokens: float64(burst), last: now}
+}
+
+func (b *bucket) refill(now time.Time) {
+	if now.After(b.last) {
+		b.tokens = min(b.burst, b.tokens+float64(now.Sub(b.last))/float64(b.perToken))
+		b.last = now
+	}
+}
+
+// delay diz quanto falta para haver um token livre.
+func (b *bucket) delay(now time.Time) time.Duration {
+	b.refill(now)
+	if b.tokens >= 1 {
+		return 0
+	}
+	return time.Duration((1 - b.tokens) * float64(b.perToken))
+}
+
+func (b *bucket) take() { b.tokens-- }
+func (b *bucket) give() { b.tokens = min(b.burst, b.tokens+1) }
+
+func (b *bucket) full(now time.Time) bool {
+	b.refill(now)
+	return b.tokens >= b.burst
+}
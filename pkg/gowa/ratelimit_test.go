@@ -0,0 +1,135 @@
+package gowa
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	type step struct {
+		advance   time.Duration
+		recipient string
+		scope     string // vazio: o envio passa
+		wait      time.Duration
+	}
+	tests := []struct {
+		name  string
+		cfg   RateLimitConfig
+		steps []step
+	}{
+		{
+			"global burst then refill",
+			RateLimitConfig{Global: PerMinute(2, 2)},
+			[]step{
+				{0, "a", "", 0},
+				{0, "b", "", 0},
+				{0, "c", "global", 30 * time.Second},
+				{20 * time.Second, "c", "global", 10 * time.Second},
+				{10 * time.Second, "c", "", 0},
+			},
+		},
+		{
+			"per recipient",
+			RateLimitConfig{PerRecipient: PerMinute(1, 1)},
+			[]step{
+				{0, "a", "", 0},
+				{0, "a", "recipient", time.Minute},
+				{0, "b", "", 0},
+				{0, "", "", 0}, // sem destinatário só o global se aplica
+				{time.Minute, "a", "", 0},
+			},
+		},
+		{
+			"group scope",
+			RateLimitConfig{PerRecipient: PerMinute(10, 10), PerGroup: PerMinute(1, 1)},
+			[]step{
+				{0, "123@g.us", "", 0},
+				{0, "123@g.us", "group", time.Minute},
+				{0, "a", "", 0},
+				{0, "a", "", 0},
+			},
+		},
+		{
+			"same number in any form",
+			RateLimitConfig{PerRecipient: PerMinute(1, 1)},
+			[]step{
+				{0, "5511999990000", "", 0},
+				{0, "5511999990000@s.whatsapp.net", "recipient", time.Minute},
+				{0, "+5511999990000", "recipient", time.Minute},
+				{0, "5511999990000:12@s.whatsapp.net", "recipient", time.Minute},
+				{0, "5511888880000@s.whatsapp.net", "", 0},
+			},
+		},
+		{
+			"strictest budget wins",
+			RateLimitConfig{Global: PerMinute(60, 1), PerRecipient: PerMinute(1, 1)},
+			[]step{
+				{0, "a", "", 0},
+				{0, "a", "recipient", time.Minute},
+				{2 * time.Second, "b", "", 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+			tt.cfg.FailFast = true
+			l := newRateLimiter(tt.cfg)
+			l.now = func() time.Time { return now }
+			if l.global != nil {
+				l.global.last = now // criado com o relógio real
+			}
+			for i, s := range tt.steps {
+				now = now.Add(s.advance)
+				_, err := l.wait(context.Background(), s.recipient)
+				var rle *RateLimitError
+				switch {
+				case s.scope == "" && err != nil:
+					t.Fatalf("step %d: unexpected error %v", i, err)
+				case s.scope == "":
+				case !errors.As(err, &rle) || !errors.Is(err, ErrRateLimited):
+					t.Fatalf("step %d: err = %v, want *RateLimitError", i, err)
+				case rle.Scope != s.scope || rle.Wait != s.wait:
+					t.Fatalf("step %d: got %s/%s, want %s/%s", i, rle.Scope, rle.Wait, s.scope, s.wait)
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterCancelReturnsTokens(t *testing.T) {
+	l := newRateLimiter(RateLimitConfig{Global: PerMinute(1, 1)})
+	if _, err := l.wait(context.Background(), "a"); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.wait(ctx, "a"); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	// o envio cancelado não pode atrasar os seguintes além do primeiro
+	if d := l.global.delay(l.global.last); d > time.Minute {
+		t.Errorf("delay after cancel = %s, want <= 1m", d)
+	}
+}
+
+func TestRateLimited(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/send/message", true},
+		{"/send/image", true},
+		{"/send/presence", false},
+		{"/send/chat-presence", false},
+		{"/message/abc/read", false},
+		{"/user/info", false},
+	}
+	for _, tt := range tests {
+		if got := rateLimited(&Call{Path: tt.path}); got != tt.want {
+			t.Errorf("rateLimited(%s) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+package gowa
+
+//go:generate go run ../../cmd/gowagen -spec ../../doc/openapi.yaml -out api_gen.go
+
+// RawAPI expõe cada operação do OpenAPI um para um, com os tipos gerados em
+// api_gen.go e sem validação ou conveniências. Os métodos de Client são
+// construídos sobre ele; use-o para endpoints que ainda não têm wrapper.
+type RawAPI struct {
+	c *Client
+}
+
+// Raw retorna a camada gerada do client.
+func (c *Client) Raw() *RawAPI {
+	return &RawAPI{c: c}
+}
@@ -0,0 +1,29 @@
+package gowa
+
+import "fmt"
+
+// CodeSuccess é o code do envelope em respostas sem ressalvas.
+const CodeSuccess = "SUCCESS"
+
+// Response é o envelope comum das respostas do gowa. T é o tipo de Results
+// de cada endpoint (SendResult, ChatListResult...).
+type Response[T any] struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Results T      `json:"results"`
+}
+
+// Success informa se o envelope veio com code SUCCESS (ou sem code).
+// Respostas HTTP 2xx com outro code são sucessos com aviso: a operação foi
+// aceita, mas o servidor sinalizou algo em Code/Message.
+func (r *Response[T]) Success() bool {
+	return r.Code == "" || r.Code == CodeSuccess
+}
+
+// Warning descreve o aviso de um sucesso com ressalva; vazio quando Success.
+func (r *Response[T]) Warning() string {
+	if r.Success() {
+		return ""
+	}
+	return fmt.Sprintf("%s: %s", r.Code, r.Message)
+}
@@ -0,0 +1,607 @@
+package gowa
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Clock abstrai o relógio do Scheduler para que os testes controlem o tempo
+// (veja gowatest.NewClock).
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// MissedPolicy define o que fazer com execuções perdidas enquanto o processo
+// esteve parado (atraso maior que SchedulerConfig.Tolerance).
+type MissedPolicy int
+
+const (
+	MissedDefault MissedPolicy = iota // usa SchedulerConfig.Missed
+	MissedRunOnce                     // executa uma vez ao voltar, mesmo que várias tenham sido perdidas
+	MissedSkip                        // descarta as perdidas e segue para a próxima
+	MissedRunAll                      // executa cada perdida, em ordem
+)
+
+// ScheduleStatus é o estado de um agendamento.
+type ScheduleStatus string
+
+const (
+	ScheduleActive   ScheduleStatus = "active"
+	ScheduleDone     ScheduleStatus = "done"     // envio único já disparado
+	ScheduleMissed   ScheduleStatus = "missed"   // envio único perdido com MissedSkip
+	ScheduleCanceled ScheduleStatus = "canceled" // cancelado por Cancel
+	ScheduleFailed   ScheduleStatus = "failed"   // envio único que falhou; o erro fica em LastError
+)
+
+// ScheduledJob é um envio agendado: único (At) ou recorrente (Cron).
+type ScheduledJob struct {
+	ID        string          `json:"id"`
+	Kind      string          `json:"kind"`
+	Request   json.RawMessage `json:"request"`
+	File      string          `json:"file,omitempty"`
+	At        time.Time       `json:"at,omitempty"`
+	Cron      string          `json:"cron,omitempty"`
+	Timezone  string          `json:"timezone"`
+	UTCOffset *int            `json:"utc_offset,omitempty"` // em segundos; só para fusos fora da base IANA
+	Missed    MissedPolicy    `json:"missed"`
+	Status    ScheduleStatus  `json:"status"`
+	NextRun   time.Time       `json:"next_run"`
+	LastRun   time.Time       `json:"last_run,omitempty"`
+	Runs      int             `json:"runs"`
+	LastError string          `json:"last_error,omitempty"`
+	MessageID string          `json:"message_id,omitempty"` // do último envio direto
+	OutboxJob string          `json:"outbox_job,omitempty"` // do último disparo via Outbox
+	CreatedAt time.Time       `json:"created_at"`
+	UpdatedAt time.Time       `json:"updated_at"`
+	Version   int64           `json:"version"` // incrementada a cada gravação
+
+	// Firing marca um envio direto em andamento; se o processo cair antes de
+	// gravar o resultado, o disparo é considerado feito (at-most-once).
+	Firing time.Time `json:"firing,omitempty"`
+}
+
+// Decode reconstrói o request tipado do agendamento.
+func (j ScheduledJob) Decode() (Sendable, error) {
+	return OutboxJob{Kind: j.Kind, Request: j.Request, File: j.File}.Decode()
+}
+
+type SchedulerConfig struct {
+	// Path é o journal dos agendamentos (JSON Lines). Vazio mantém tudo só
+	// em memória.
+	Path string
+
+	// Outbox, se definido, recebe os disparos como jobs (com retry e entrega
+	// persistida), usando uma chave por execução para não duplicar. Sem ele
+	// o Scheduler envia direto pelo Client.
+	Outbox *Outbox
+
+	Clock     Clock          // default relógio do sistema
+	Location  *time.Location // fuso padrão das expressões cron; default time.Local
+	Missed    MissedPolicy   // política padrão; default MissedRunOnce
+	Tolerance time.Duration  // atraso que ainda não conta como perdido; default 1min
+
+	// OnFire é chamado após cada disparo (ou execução descartada, com
+	// ErrMissedRun).
+	OnFire func(job ScheduledJob, err error)
+}
+
+// ErrMissedRun é passado a OnFire quando uma execução perdida é descartada
+// por MissedSkip.
+var ErrMissedRun = errors.New("gowa: scheduled run missed")
+
+// ScheduleOptions ajusta um agendamento.
+type ScheduleOptions struct {
+	ID string // default gerado; um ID existente é recusado
+	// Location é o fuso da expressão cron; default SchedulerConfig.Location.
+	// Fusos fora da base IANA são aceitos se tiverem deslocamento fixo
+	// (time.FixedZone); com horário de verão, são recusados.
+	Location *time.Location
+	Missed   MissedPolicy
+}
+
+// Scheduler dispara envios em horários definidos (At) ou recorrentes (Cron),
+// com cancelamento e reagendamento por ID e política para execuções perdidas.
+type Scheduler struct {
+	c   *Client
+	cfg SchedulerConfig
+
+	mu   sync.Mutex
+	jr   *journal[schedulerRecord]
+	jobs map[string]*ScheduledJob
+	wake chan struct{}
+}
+
+type schedulerRecord struct {
+	Job *ScheduledJob `json:"job"`
+}
+
+// NewScheduler cria o Scheduler e, com cfg.Path, recupera os agendamentos
+// gravados.
+func NewScheduler(c *Client, cfg SchedulerConfig) (*Scheduler, error) {
+	if c == nil && cfg.Outbox == nil {
+		return nil, errors.New("scheduler: client or outbox is required")
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = systemClock{}
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.Local
+	}
+	if cfg.Missed == MissedDefault {
+		cfg.Missed = MissedRunOnce
+	}
+	if cfg.Tolerance <= 0 {
+		cfg.Tolerance = time.Minute
+	}
+	s := &Scheduler{c: c, cfg: cfg, jobs: map[string]*ScheduledJob{}, wake: make(chan struct{}, 1)}
+	if cfg.Path == "" {
+		return s, nil
+	}
+	jr, err := openJournal(cfg.Path, func(rec schedulerRecord) {
+		if rec.Job != nil {
+			s.jobs[rec.Job.ID] = rec.Job
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.jr = jr
+	// disparos diretos interrompidos pela queda contam como feitos
+	var recs []*ScheduledJob
+	for _, j := range s.jobs {
+		if j.Firing.IsZero() {
+			continue
+		}
+		cp := *j
+		cp.Version++
+		cp.LastError = "interrupted while sending; delivery not confirmed"
+		if err := s.advance(&cp, cp.Firing, cp.Firing); err != nil {
+			cp.LastError = err.Error()
+		}
+		recs = append(recs, &cp)
+	}
+	if err := s.save(recs...); err != nil {
+		jr.close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// At agenda um envio único.
+func (s *Scheduler) At(req Sendable, at time.Time, opts ...ScheduleOptions) (ScheduledJob, error) {
+	j, err := s.newJob(req, opts)
+	if err != nil {
+		return ScheduledJob{}, err
+	}
+	j.At, j.NextRun = at, at
+	return s.add(j)
+}
+
+// Cron agenda um envio recorrente. A expressão é avaliada no fuso de
+// opts.Location (ou SchedulerConfig.Location).
+func (s *Scheduler) Cron(req Sendable, expr string, opts ...ScheduleOptions) (ScheduledJob, error) {
+	j, err := s.newJob(req, opts)
+	if err != nil {
+		return ScheduledJob{}, err
+	}
+	next, err := j.nextCron(expr, s.cfg.Clock.Now())
+	if err != nil {
+		return ScheduledJob{}, err
+	}
+	j.Cron, j.NextRun = expr, next
+	return s.add(j)
+}
+
+func (s *Scheduler) newJob(req Sendable, opts []ScheduleOptions) (*ScheduledJob, error) {
+	var opt ScheduleOptions
+	if len(opts) > 0 {
+		opt = opts[0]
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	loc := opt.Location
+	if loc == nil {
+		loc = s.cfg.Location
+	}
+	if opt.ID == "" {
+		opt.ID = newJobID()
+	}
+	now := s.cfg.Clock.Now()
+	offset, err := fixedOffset(loc, now)
+	if err != nil {
+		return nil, err
+	}
+	return &ScheduledJob{
+		ID:        opt.ID,
+		Kind:      req.sendKind(),
+		Request:   b,
+		File:      req.sendFile(),
+		Timezone:  loc.String(),
+		UTCOffset: offset,
+		Missed:    opt.Missed,
+		Status:    ScheduleActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}, nil
+}
+
+func (s *Scheduler) add(j *ScheduledJob) (ScheduledJob, error) {
+	s.mu.Lock()
+	if _, ok := s.jobs[j.ID]; ok {
+		s.mu.Unlock()
+		return ScheduledJob{}, fmt.Errorf("scheduler: job %s already exists", j.ID)
+	}
+	err := s.save(j)
+	s.mu.Unlock()
+	if err != nil {
+		return ScheduledJob{}, err
+	}
+	s.signal()
+	return *j, nil
+}
+
+// fixedOffset devolve nil para fusos que time.LoadLocation recarrega pelo
+// nome e o deslocamento dos demais, desde que não mude ao longo do ano.
+func fixedOffset(loc *time.Location, now time.Time) (*int, error) {
+	if _, err := time.LoadLocation(loc.String()); err == nil {
+		return nil, nil
+	}
+	year := now.In(loc).Year()
+	_, jan := time.Date(year, time.January, 1, 0, 0, 0, 0, loc).Zone()
+	_, jul := time.Date(year, time.July, 1, 0, 0, 0, 0, loc).Zone()
+	if jan != jul {
+		return nil, fmt.Errorf("scheduler: location %q is not in the IANA database and its offset changes during the year", loc)
+	}
+	return &jan, nil
+}
+
+// location recarrega o fuso do job: pelo nome ou, para fusos fora da base
+// IANA (time.FixedZone), que time.LoadLocation não conhece, pelo UTCOffset.
+func (j *ScheduledJob) location() (*time.Location, error) {
+	if j.UTCOffset != nil {
+		return time.FixedZone(j.Timezone, *j.UTCOffset), nil
+	}
+	return time.LoadLocation(j.Timezone)
+}
+
+// nextCron calcula a próxima execução de expr depois de after, no fuso do job.
+func (j *ScheduledJob) nextCron(expr string, after time.Time) (time.Time, error) {
+	c, err := ParseCron(expr)
+	if err != nil {
+		return time.Time{}, err
+	}
+	loc, err := j.location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	next := c.Next(after.In(loc))
+	if next.IsZero() {
+		return time.Time{}, fmt.Errorf("cron %q: no run in the next 5 years", expr)
+	}
+	return next, nil
+}
+
+// Cancel cancela um agendamento ativo.
+func (s *Scheduler) Cancel(id string) error {
+	return s.update(id, func(j *ScheduledJob) error {
+		j.Status, j.NextRun = ScheduleCanceled, time.Time{}
+		return nil
+	})
+}
+
+// Reschedule muda o horário de um envio único, reativando-o se já tiver sido
+// disparado, perdido, cancelado ou se tiver falhado.
+func (s *Scheduler) Reschedule(id string, at time.Time) error {
+	return s.update(id, func(j *ScheduledJob) error {
+		if j.Cron != "" {
+			return fmt.Errorf("scheduler: job %s is recurring; use SetCron", id)
+		}
+		j.At, j.NextRun, j.Status = at, at, ScheduleActive
+		return nil
+	})
+}
+
+// SetCron troca a expressão de um agendamento recorrente (ou transforma um
+// envio único em recorrente) e o reativa.
+func (s *Scheduler) SetCron(id, expr string) error {
+	now := s.cfg.Clock.Now()
+	return s.update(id, func(j *ScheduledJob) error {
+		next, err := j.nextCron(expr, now)
+		if err != nil {
+			return err
+		}
+		j.Cron, j.At, j.NextRun, j.Status = expr, time.Time{}, next, ScheduleActive
+		return nil
+	})
+}
+
+func (s *Scheduler) update(id string, fn func(*ScheduledJob) error) error {
+	s.mu.Lock()
+	cur, ok := s.jobs[id]
+	if !ok {
+		s.mu.Unlock()
+		return fmt.Errorf("scheduler: job %s not found", id)
+	}
+	cp := *cur
+	if err := fn(&cp); err != nil {
+		s.mu.Unlock()
+		return err
+	}
+	cp.UpdatedAt, cp.Version = s.cfg.Clock.Now(), cur.Version+1
+	err := s.save(&cp)
+	s.mu.Unlock()
+	if err == nil {
+		s.signal()
+	}
+	return err
+}
+
+// Job retorna o estado atual de um agendamento.
+func (s *Scheduler) Job(id string) (ScheduledJob, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j, ok := s.jobs[id]
+	if !ok {
+		return ScheduledJob{}, false
+	}
+	return *j, true
+}
+
+// Jobs lista os agendamentos ordenados pela próxima execução.
+func (s *Scheduler) Jobs() []ScheduledJob {
+	s.mu.Lock()
+	out := make([]ScheduledJob, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		out = append(out, *j)
+	}
+	s.mu.Unlock()
+	sort.Slice(out, func(a, b int) bool {
+		if !out[a].NextRun.Equal(out[b].NextRun) {
+			return out[a].NextRun.Before(out[b].NextRun)
+		}
+		return out[a].ID < out[b].ID
+	})
+	return out
+}
+
+// save grava e aplica os jobs. Chamado com s.mu travado.
+func (s *Scheduler) save(jobs ...*ScheduledJob) error {
+	if s.jr != nil && len(jobs) > 0 {
+		recs := make([]schedulerRecord, len(jobs))
+		for i, j := range jobs {
+			recs[i] = schedulerRecord{Job: j}
+		}
+		if err := s.jr.append(recs...); err != nil {
+			return err
+		}
+	}
+	for _, j := range jobs {
+		s.jobs[j.ID] = j
+	}
+	return nil
+}
+
+func (s *Scheduler) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run dispara os agendamentos vencidos até o ctx ser cancelado.
+func (s *Scheduler) Run(ctx context.Context) error {
+	for {
+		now := s.cfg.Clock.Now()
+		for _, j := range s.due(now) {
+			if err := s.fire(ctx, j, now); err != nil {
+				return err
+			}
+		}
+		var timer <-chan time.Time
+		if next, ok := s.nextRun(); ok {
+			timer = s.cfg.Clock.After(max(next.Sub(s.cfg.Clock.Now()), 0))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.wake:
+		case <-timer:
+		}
+	}
+}
+
+func (s *Scheduler) due(now time.Time) []ScheduledJob {
+	s.mu.Lock()
+	var out []ScheduledJob
+	for _, j := range s.jobs {
+		if j.Status == ScheduleActive && !j.NextRun.After(now) {
+			out = append(out, *j)
+		}
+	}
+	s.mu.Unlock()
+	sort.Slice(out, func(a, b int) bool { return out[a].NextRun.Before(out[b].NextRun) })
+	return out
+}
+
+func (s *Scheduler) nextRun() (time.Time, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var next time.Time
+	for _, j := range s.jobs {
+		if j.Status == ScheduleActive && (next.IsZero() || j.NextRun.Before(next)) {
+			next = j.NextRun
+		}
+	}
+	return next, !next.IsZero()
+}
+
+// fire trata uma execução vencida conforme a política de perdidas. Só retorna
+// erro se o journal não puder ser gravado.
+func (s *Scheduler) fire(ctx context.Context, j ScheduledJob, now time.Time) error {
+	policy := j.Missed
+	if policy == MissedDefault {
+		policy = s.cfg.Missed
+	}
+	scheduled := j.NextRun
+	late := now.Sub(scheduled) > s.cfg.Tolerance
+
+	if late && policy == MissedSkip {
+		j.LastError = fmt.Sprintf("missed run at %s", scheduled.Format(time.RFC3339))
+		if j.Cron == "" {
+			j.Status, j.NextRun = ScheduleMissed, time.Time{}
+		} else if err := s.advance(&j, scheduled, now); err != nil {
+			j.LastError = err.Error()
+		}
+		if err := s.commit(&j); err != nil {
+			return err
+		}
+		s.notify(j, ErrMissedRun)
+		return nil
+	}
+
+	sendErr, err := s.deliver(ctx, &j, scheduled)
+	if err != nil {
+		return err
+	}
+	j.LastRun, j.Runs = now, j.Runs+1
+	j.LastError = ""
+	if sendErr != nil {
+		j.LastError = sendErr.Error()
+	}
+	// MissedRunAll avança a partir do horário agendado, para que as demais
+	// perdidas venham na próxima volta; as outras políticas, a partir de agora.
+	from := now
+	if policy == MissedRunAll {
+		from = scheduled
+	}
+	if err := s.advance(&j, scheduled, from); err != nil {
+		j.LastError = err.Error()
+	}
+	if sendErr != nil && j.Status == ScheduleDone && j.Cron == "" {
+		// não é tentado de novo aqui (use o Outbox para retry); Reschedule reativa
+		j.Status = ScheduleFailed
+	}
+	if err := s.commit(&j); err != nil {
+		return err
+	}
+	s.notify(j, sendErr)
+	return nil
+}
+
+// deliver envia pelo Outbox (chave por execução) ou direto pelo Client.
+// sendErr é a falha do envio; err, a falha ao gravar o journal.
+func (s *Scheduler) deliver(ctx context.Context, j *ScheduledJob, scheduled time.Time) (sendErr, err error) {
+	req, err := j.Decode()
+	if err != nil {
+		return err, nil
+	}
+	if s.cfg.Outbox != nil {
+		job, err := s.cfg.Outbox.Enqueue(req, EnqueueOptions{Key: fmt.Sprintf("schedule:%s:%d", j.ID, scheduled.Unix())})
+		j.OutboxJob = job.ID
+		return err, nil
+	}
+	j.Firing = scheduled
+	if err := s.commit(j); err != nil {
+		return nil, err
+	}
+	resp, err := outboxKinds[j.Kind].send(ctx, s.c.Raw(), req)
+	j.Firing = time.Time{}
+	if err == nil && resp != nil {
+		j.MessageID = resp.Results.MessageID
+	}
+	return err, nil
+}
+
+// advance calcula a próxima execução: envios únicos são concluídos; os
+// recorrentes seguem para a primeira execução depois de from.
+func (s *Scheduler) advance(j *ScheduledJob, scheduled, from time.Time) error {
+	j.Firing = time.Time{}
+	if j.Cron == "" {
+		if j.Status == ScheduleActive {
+			j.Status = ScheduleDone
+		}
+		j.NextRun = time.Time{}
+		return nil
+	}
+	if from.Before(scheduled) {
+		from = scheduled
+	}
+	next, err := j.nextCron(j.Cron, from)
+	if err != nil {
+		j.Status, j.NextRun = ScheduleDone, time.Time{}
+		return err
+	}
+	j.NextRun = next
+	return nil
+}
+
+// commit grava j, a menos que o agendamento tenha sido cancelado ou alterado
+// durante o disparo (nesse caso só o resultado do envio é registrado). A
+// comparação é pela Version, que não depende da resolução do relógio.
+func (s *Scheduler) commit(j *ScheduledJob) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp := *j
+	cp.UpdatedAt = s.cfg.Clock.Now()
+	if cur, ok := s.jobs[j.ID]; ok {
+		if cur.Version != j.Version {
+			merged := *cur
+			merged.LastRun, merged.Runs, merged.LastError = cp.LastRun, cp.Runs, cp.LastError
+			merged.MessageID, merged.OutboxJob, merged.Firing = cp.MessageID, cp.OutboxJob, cp.Firing
+			merged.UpdatedAt = cp.UpdatedAt
+			cp = merged
+		}
+		cp.Version = cur.Version + 1
+	}
+	j.UpdatedAt, j.Version = cp.UpdatedAt, cp.Version
+	return s.save(&cp)
+}
+
+func (s *Scheduler) notify(j ScheduledJob, err error) {
+	if s.cfg.OnFire != nil {
+		s.cfg.OnFire(j, err)
+	}
+}
+
+// Compact reescreve o journal só com o estado atual, removendo agendamentos
+// concluídos, perdidos ou cancelados atualizados antes de olderThan (zero
+// mantém todos).
+func (s *Scheduler) Compact(olderThan time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var recs []schedulerRecord
+	for id, j := range s.jobs {
+		if j.Status != ScheduleActive && !olderThan.IsZero() && j.UpdatedAt.Before(olderThan) {
+			delete(s.jobs, id)
+			continue
+		}
+		recs = append(recs, schedulerRecord{Job: j})
+	}
+	if s.jr == nil {
+		return nil
+	}
+	return s.jr.rewrite(recs)
+}
+
+// Close fecha o journal.
+func (s *Scheduler) Close() error {
+	if s.jr == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.jr.close()
+}
@@ -0,0 +1,283 @@
+package gowa_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+type fired struct {
+	job gowa.ScheduledJob
+	err error
+}
+
+// startScheduler executa o Run em segundo plano até o fim do teste e devolve
+// os disparos notificados por OnFire.
+func startScheduler(t *testing.T, c *gowa.Client, cfg gowa.SchedulerConfig) (*gowa.Scheduler, <-chan fired) {
+	t.Helper()
+	ch := make(chan fired, 16)
+	cfg.OnFire = func(j gowa.ScheduledJob, err error) { ch <- fired{j, err} }
+	s, err := gowa.NewScheduler(c, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		s.Close()
+	})
+	return s, ch
+}
+
+func waitFired(t *testing.T, ch <-chan fired) fired {
+	t.Helper()
+	select {
+	case f := <-ch:
+		return f
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for OnFire")
+		return fired{}
+	}
+}
+
+func TestSchedulerAt(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{})
+	t.Cleanup(srv.Close)
+	t0 := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	clock := gowatest.NewClock(t0)
+	s, ch := startScheduler(t, srv.GowaClient(), gowa.SchedulerConfig{Clock: clock})
+
+	j, err := s.At(gowa.SendMessageRequest{Phone: "5511999990000", Message: "lembrete"}, t0.Add(time.Hour), gowa.ScheduleOptions{ID: "lembrete"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.At(gowa.SendMessageRequest{Phone: "1"}, t0, gowa.ScheduleOptions{ID: "lembrete"}); err == nil {
+		t.Error("duplicate ID accepted")
+	}
+	eventually(t, "timer", func() bool { return clock.Waiters() > 0 })
+	clock.Advance(time.Hour)
+	f := waitFired(t, ch)
+	if f.err != nil || f.job.ID != j.ID || f.job.Status != gowa.ScheduleDone || f.job.MessageID == "" {
+		t.Errorf("fired = %+v, %v", f.job, f.err)
+	}
+	var body gowa.SendMessageRequest
+	if req, ok := srv.LastRequest("/send/message"); !ok || req.JSON(&body) != nil || body.Message != "lembrete" {
+		t.Errorf("request = %+v", body)
+	}
+}
+
+func TestSchedulerCancelAndReschedule(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{})
+	t.Cleanup(srv.Close)
+	t0 := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	clock := gowatest.NewClock(t0)
+	s, ch := startScheduler(t, srv.GowaClient(), gowa.SchedulerConfig{Clock: clock})
+
+	j, err := s.At(gowa.SendMessageRequest{Phone: "1", Message: "x"}, t0.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Cancel(j.ID); err != nil {
+		t.Fatal(err)
+	}
+	clock.Advance(2 * time.Hour)
+	if err := s.Reschedule(j.ID, t0.Add(3*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "timer", func() bool { return clock.Waiters() > 0 })
+	if n := len(srv.Requests()); n != 0 {
+		t.Fatalf("canceled job sent %d requests", n)
+	}
+	clock.Advance(time.Hour)
+	if f := waitFired(t, ch); f.err != nil || f.job.Runs != 1 {
+		t.Errorf("fired = %+v, %v", f.job, f.err)
+	}
+	if err := s.Reschedule("nope", t0); err == nil {
+		t.Error("Reschedule of unknown job succeeded")
+	}
+}
+
+// TestSchedulerMissed agenda um envio de hora em hora, "desliga" o processo
+// por 3h e confere cada política ao religar.
+func TestSchedulerMissed(t *testing.T) {
+	tests := []struct {
+		policy gowa.MissedPolicy
+		sends  int
+		fires  int
+	}{
+		{gowa.MissedRunOnce, 1, 1},
+		{gowa.MissedSkip, 0, 1},
+		{gowa.MissedRunAll, 3, 3},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("policy=%d", tt.policy), func(t *testing.T) {
+			srv := gowatest.NewServer(gowatest.Config{})
+			t.Cleanup(srv.Close)
+			t0 := time.Date(2025, 3, 10, 0, 30, 0, 0, time.UTC)
+			clock := gowatest.NewClock(t0)
+			path := filepath.Join(t.TempDir(), "schedule.jsonl")
+			cfg := gowa.SchedulerConfig{Path: path, Clock: clock, Location: time.UTC}
+
+			s, err := gowa.NewScheduler(srv.GowaClient(), cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s.Cron(gowa.SendMessageRequest{Phone: "1", Message: "hora"}, "0 * * * *", gowa.ScheduleOptions{ID: "h", Missed: tt.policy}); err != nil {
+				t.Fatal(err)
+			}
+			s.Close()
+			clock.Advance(3 * time.Hour)
+
+			s, ch := startScheduler(t, srv.GowaClient(), cfg)
+			for i := 0; i < tt.fires; i++ {
+				f := waitFired(t, ch)
+				if skip := tt.policy == gowa.MissedSkip; skip != errors.Is(f.err, gowa.ErrMissedRun) {
+					t.Errorf("fire %d err = %v", i, f.err)
+				}
+			}
+			eventually(t, "timer", func() bool { return clock.Waiters() > 0 })
+			if n := len(srv.Requests()); n != tt.sends {
+				t.Errorf("%d sends, want %d", n, tt.sends)
+			}
+			if j, _ := s.Job("h"); !j.NextRun.Equal(t0.Add(3*time.Hour + 30*time.Minute)) {
+				t.Errorf("NextRun = %s, want 04:00", j.NextRun)
+			}
+		})
+	}
+}
+
+// TestSchedulerFixedZone usa um fuso fora da base IANA, que precisa
+// sobreviver à reabertura do journal.
+func TestSchedulerFixedZone(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{})
+	t.Cleanup(srv.Close)
+	t0 := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	clock := gowatest.NewClock(t0)
+	brt := time.FixedZone("BRT", -3*3600)
+	cfg := gowa.SchedulerConfig{Path: filepath.Join(t.TempDir(), "schedule.jsonl"), Clock: clock, Location: brt}
+	s, err := gowa.NewScheduler(srv.GowaClient(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	j, err := s.Cron(gowa.SendMessageRequest{Phone: "1", Message: "bom dia"}, "0 9 * * *", gowa.ScheduleOptions{ID: "bom-dia"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	if !j.NextRun.Equal(want) {
+		t.Errorf("NextRun = %s, want %s", j.NextRun, want)
+	}
+	s.Close()
+
+	s, ch := startScheduler(t, srv.GowaClient(), cfg)
+	eventually(t, "timer", func() bool { return clock.Waiters() > 0 })
+	clock.Advance(12 * time.Hour)
+	if f := waitFired(t, ch); f.err != nil || !f.job.NextRun.Equal(want.Add(24*time.Hour)) {
+		t.Errorf("fired = %+v, %v", f.job, f.err)
+	}
+	if err := s.SetCron("bom-dia", "0 18 * * *"); err != nil {
+		t.Errorf("SetCron after reopen: %v", err)
+	}
+
+	// fuso sem nome IANA e com horário de verão não pode ser recarregado
+	tzdata, err := os.ReadFile("/usr/share/zoneinfo/America/New_York")
+	if err != nil {
+		t.Skip("no tzdata")
+	}
+	custom, err := time.LoadLocationFromTZData("Empresa/Matriz", tzdata)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Cron(gowa.SendMessageRequest{Phone: "1"}, "0 9 * * *", gowa.ScheduleOptions{Location: custom}); err == nil {
+		t.Error("custom zone with DST accepted")
+	}
+}
+
+// TestSchedulerCancelWhileFiring cancela o agendamento durante o envio, com o
+// relógio parado: o cancelamento não pode ser sobrescrito pelo disparo.
+func TestSchedulerCancelWhileFiring(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{})
+	t.Cleanup(srv.Close)
+	t0 := time.Date(2025, 3, 10, 8, 0, 0, 0, time.UTC)
+	clock := gowatest.NewClock(t0)
+	var s *gowa.Scheduler
+	c, err := gowa.New(gowa.Config{
+		BaseURL:    srv.URL,
+		Username:   "admin",
+		Password:   "admin",
+		HTTPClient: srv.Server.Client(),
+		RetryMax:   -1,
+		Middlewares: []gowa.Middleware{func(next gowa.Doer) gowa.Doer {
+			return gowa.DoerFunc(func(ctx context.Context, call *gowa.Call) error {
+				if err := s.Cancel("diario"); err != nil {
+					t.Errorf("Cancel: %v", err)
+				}
+				return next.Do(ctx, call)
+			})
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, ch := startScheduler(t, c, gowa.SchedulerConfig{Clock: clock, Location: time.UTC})
+	if _, err := s.Cron(gowa.SendMessageRequest{Phone: "1", Message: "x"}, "0 9 * * *", gowa.ScheduleOptions{ID: "diario"}); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "timer", func() bool { return clock.Waiters() > 0 })
+	clock.Advance(time.Hour)
+	f := waitFired(t, ch)
+	if f.err != nil || f.job.Runs != 1 {
+		t.Errorf("fired = %+v, %v", f.job, f.err)
+	}
+	if j, _ := s.Job("diario"); j.Status != gowa.ScheduleCanceled || !j.NextRun.IsZero() {
+		t.Errorf("job = %s next %s, want canceled", j.Status, j.NextRun)
+	}
+}
+
+// TestSchedulerAtFailed confere que um envio único direto que falha não fica
+// como feito, e que Reschedule o reativa.
+func TestSchedulerAtFailed(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{})
+	t.Cleanup(srv.Close)
+	srv.AddFault(gowatest.Fault{Path: "/send/message", Status: 500, Times: 1})
+	t0 := time.Date(2025, 3, 10, 9, 0, 0, 0, time.UTC)
+	clock := gowatest.NewClock(t0)
+	s, ch := startScheduler(t, srv.GowaClient(), gowa.SchedulerConfig{Clock: clock})
+
+	j, err := s.At(gowa.SendMessageRequest{Phone: "5511999990000", Message: "lembrete"}, t0.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "timer", func() bool { return clock.Waiters() > 0 })
+	clock.Advance(time.Hour)
+	f := waitFired(t, ch)
+	var apiErr *gowa.APIError
+	if !errors.As(f.err, &apiErr) || f.job.Status != gowa.ScheduleFailed || f.job.LastError == "" || f.job.MessageID != "" {
+		t.Fatalf("fired = %+v, %v; want failed with the error", f.job, f.err)
+	}
+	if cur, _ := s.Job(j.ID); cur.Status != gowa.ScheduleFailed {
+		t.Errorf("stored status = %s, want failed", cur.Status)
+	}
+
+	if err := s.Reschedule(j.ID, t0.Add(2*time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	eventually(t, "timer", func() bool { return clock.Waiters() > 0 })
+	clock.Advance(time.Hour)
+	if f := waitFired(t, ch); f.err != nil || f.job.Status != gowa.ScheduleDone || f.job.LastError != "" {
+		t.Errorf("rescheduled = %+v, %v", f.job, f.err)
+	}
+}
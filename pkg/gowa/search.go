@@ -0,0 +1,123 @@
+package gowa
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+type SearchOptions struct {
+	Workers        int           // chats consultados em paralelo; default 4
+	PerChatTimeout time.Duration // timeout de cada GetChatMessages; default 10s
+	LimitPerChat   int           // máximo de resultados por chat; default 50
+	Chats          []Chat        // opcional: evita o ListChats e busca só nesses chats
+}
+
+// SearchHit é uma mensagem encontrada, acompanhada do chat de origem.
+type SearchHit struct {
+	Chat    Chat
+	Message ChatMessage
+	Time    time.Time
+}
+
+// SearchBatch é o resultado parcial de um chat, entregue assim que ele termina.
+type SearchBatch struct {
+	Chat Chat
+	Hits []SearchHit
+	Err  error
+}
+
+// SearchMessagesStream busca query em todos os chats e entrega um SearchBatch
+// por chat no canal retornado, na ordem em que terminam. O canal é fechado ao
+// final; uma falha no ListChats chega como um único SearchBatch com Err.
+// Para abandonar a leitura antes do fim, cancele ctx.
+func (c *Client) SearchMessagesStream(ctx context.Context, query string, opts SearchOptions) <-chan SearchBatch {
+	if opts.Workers <= 0 {
+		opts.Workers = 4
+	}
+	if opts.PerChatTimeout <= 0 {
+		opts.PerChatTimeout = 10 * time.Second
+	}
+	if opts.LimitPerChat <= 0 || opts.LimitPerChat > 100 {
+		opts.LimitPerChat = 50
+	}
+	out := make(chan SearchBatch)
+	go func() {
+		defer close(out)
+		if strings.TrimSpace(query) == "" {
+			out <- SearchBatch{Err: errors.New("query is required")}
+			return
+		}
+		chats := opts.Chats
+		if len(chats) == 0 {
+			var err error
+			if chats, err = c.allChats(ctx, 100); err != nil {
+				out <- SearchBatch{Err: err}
+				return
+			}
+		}
+		jobs := make(chan Chat)
+		var wg sync.WaitGroup
+		for w := 0; w < opts.Workers; w++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for ch := range jobs {
+					b := c.searchChat(ctx, ch, query, opts)
+					select {
+					case out <- b:
+					case <-ctx.Done():
+					}
+				}
+			}()
+		}
+	feed:
+		for _, ch := range chats {
+			select {
+			case jobs <- ch:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+		close(jobs)
+		wg.Wait()
+	}()
+	return out
+}
+
+func (c *Client) searchChat(ctx context.Context, ch Chat, query string, opts SearchOptions) SearchBatch {
+	ctx, cancel := context.WithTimeout(ctx, opts.PerChatTimeout)
+	defer cancel()
+	resp, err := c.GetChatMessages(ctx, ch.JID, GetChatMessagesParams{Limit: opts.LimitPerChat, Search: query})
+	if err != nil {
+		return SearchBatch{Chat: ch, Err: fmt.Errorf("search %s: %w", ch.JID, err)}
+	}
+	b := SearchBatch{Chat: ch}
+	for _, m := range resp.Results.Data {
+		b.Hits = append(b.Hits, SearchHit{Chat: ch, Message: m, Time: parseTimestamp(m.Timestamp)})
+	}
+	return b
+}
+
+// SearchMessages busca query em todos os chats e retorna os resultados
+// mesclados, do mais recente para o mais antigo. Chats que falham não impedem
+// o retorno dos demais: os resultados vêm junto com os erros agregados.
+func (c *Client) SearchMessages(ctx context.Context, query string, opts SearchOptions) ([]SearchHit, error) {
+	var hits []SearchHit
+	var errs []error
+	for b := range c.SearchMessagesStream(ctx, query, opts) {
+		hits = append(hits, b.Hits...)
+		if b.Err != nil {
+			errs = append(errs, b.Err)
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		errs = append(errs, err)
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Time.After(hits[j].Time) })
+	return hits, errors.Join(errs...)
+}
@@ -0,0 +1,124 @@
+package gowa_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+// searchServer cria n chats com uma mensagem "boleto" cada, a do chat i no
+// minuto i depois de t0.
+func searchServer(t *testing.T, n int, t0 time.Time) *gowatest.Server {
+	t.Helper()
+	srv := gowatest.NewServer(gowatest.Config{})
+	t.Cleanup(srv.Close)
+	for i := range n {
+		jid := fmt.Sprintf("55119%08d@s.whatsapp.net", i)
+		srv.AddChat(gowa.Chat{JID: jid, Name: fmt.Sprintf("chat %d", i)})
+		srv.AddMessage(gowa.ChatMessage{ChatJID: jid, Content: "segue o boleto", Timestamp: t0.Add(time.Duration(i) * time.Minute).Format(time.RFC3339)})
+		srv.AddMessage(gowa.ChatMessage{ChatJID: jid, Content: "oi", Timestamp: t0.Format(time.RFC3339)})
+	}
+	return srv
+}
+
+func TestSearchMessages(t *testing.T) {
+	ctx := context.Background()
+	t0 := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	// mais chats que uma página do ListChats (100)
+	srv := searchServer(t, 130, t0)
+	hits, err := srv.GowaClient().SearchMessages(ctx, "BOLETO", gowa.SearchOptions{Workers: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 130 {
+		t.Fatalf("%d hits, want 130 (all chat pages)", len(hits))
+	}
+	for i := 1; i < len(hits); i++ {
+		if hits[i].Time.After(hits[i-1].Time) {
+			t.Fatalf("hit %d newer than hit %d: not sorted newest first", i, i-1)
+		}
+	}
+	if hits[0].Chat.Name != "chat 129" {
+		t.Errorf("newest hit from %q, want chat 129", hits[0].Chat.Name)
+	}
+}
+
+func TestSearchMessagesLimit(t *testing.T) {
+	ctx := context.Background()
+	t0 := time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)
+	srv := searchServer(t, 1, t0)
+	jid := "5511900000000@s.whatsapp.net"
+	for i := range 5 {
+		srv.AddMessage(gowa.ChatMessage{ChatJID: jid, Content: "outro boleto", Timestamp: t0.Add(time.Duration(i+1) * time.Hour).Format(time.RFC3339)})
+	}
+	c := srv.GowaClient()
+	tests := []struct {
+		limit, want int
+		param       string
+	}{
+		{2, 2, "2"},
+		{0, 6, "50"},   // default
+		{500, 6, "50"}, // acima do máximo da API volta ao default
+	}
+	for _, tt := range tests {
+		srv.ResetRequests()
+		hits, err := c.SearchMessages(ctx, "boleto", gowa.SearchOptions{LimitPerChat: tt.limit})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(hits) != tt.want {
+			t.Errorf("LimitPerChat %d: %d hits, want %d", tt.limit, len(hits), tt.want)
+		}
+		req, _ := srv.LastRequest("/chat/" + jid + "/messages")
+		if got := req.Query.Get("limit"); got != tt.param {
+			t.Errorf("LimitPerChat %d: limit=%s, want %s", tt.limit, got, tt.param)
+		}
+	}
+}
+
+func TestSearchMessagesErrors(t *testing.T) {
+	ctx := context.Background()
+	srv := searchServer(t, 2, time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC))
+	c := srv.GowaClient()
+	if _, err := c.SearchMessages(ctx, "  ", gowa.SearchOptions{}); err == nil {
+		t.Error("empty query accepted")
+	}
+	// um chat que falha não esconde os resultados dos demais
+	chats := []gowa.Chat{{JID: "5511900000000@s.whatsapp.net"}, {JID: "nao-existe@s.whatsapp.net"}}
+	hits, err := c.SearchMessages(ctx, "boleto", gowa.SearchOptions{Chats: chats})
+	if err == nil || len(hits) != 1 {
+		t.Errorf("hits = %d, err = %v; want 1 hit and the failed chat's error", len(hits), err)
+	}
+}
+
+func TestSearchMessagesStreamCancel(t *testing.T) {
+	srv := searchServer(t, 40, time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := srv.GowaClient().SearchMessagesStream(ctx, "boleto", gowa.SearchOptions{Workers: 2})
+	if b := <-stream; b.Err != nil {
+		t.Fatal(b.Err)
+	}
+	cancel()
+	// o canal precisa fechar sem que o resto seja lido
+	n := 1
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case _, ok := <-stream:
+			if !ok {
+				if n >= 40 {
+					t.Errorf("read %d batches, want fewer than 40 after cancel", n)
+				}
+				return
+			}
+			n++
+		case <-timeout:
+			t.Fatal("stream not closed after cancel")
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package gowa
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// DefaultMaxMessageLen é o tamanho padrão, em caracteres, de cada parte
+// gerada por SplitText. O WhatsApp aceita textos maiores, mas acima disso
+// a mensagem aparece truncada ("Ler mais") ou é rejeitada por alguns clientes.
+const DefaultMaxMessageLen = 4096
+
+type SplitOptions struct {
+	// MaxLen é o limite de caracteres por parte, incluindo a numeração e os
+	// marcadores reabertos; default DefaultMaxMessageLen. Valores abaixo de
+	// MinSplitLen valem MinSplitLen.
+	MaxLen int
+	Number bool // acrescenta " (1/3)" ao fim de cada parte quando há mais de uma
+}
+
+// MinSplitLen é o menor MaxLen aceito por SplitText: abaixo disso a
+// numeração e os marcadores reabertos não caberiam nas partes.
+const MinSplitLen = 16
+
+// SplitText divide text em partes de no máximo MaxLen caracteres, cortando de
+// preferência entre parágrafos, depois entre linhas e depois entre palavras.
+// Uma palavra maior que o limite é cortada sem separar emojis compostos,
+// acentos combinantes ou bandeiras. Formatação aberta no corte (*, _, ~ ou
+// ```) é fechada no fim da parte e reaberta na seguinte.
+func SplitText(text string, opts SplitOptions) []string {
+	maxLen := opts.MaxLen
+	if maxLen <= 0 {
+		maxLen = DefaultMaxMessageLen
+	}
+	maxLen = max(maxLen, MinSplitLen)
+	text = strings.TrimSpace(text)
+	rs := []rune(text)
+	if len(rs) <= maxLen {
+		return []string{text}
+	}
+	if !opts.Number {
+		return splitRunes(rs, maxLen)
+	}
+	// a numeração depende do total de partes, que depende do espaço que
+	// sobra: reserva para d dígitos e refaz se o total precisar de mais
+	var parts []string
+	for d := 1; ; d++ {
+		limit := maxLen - len(" (/)") - 2*d
+		if limit < 1 {
+			return splitRunes(rs, maxLen) // texto enorme para um MaxLen mínimo: sem numeração
+		}
+		parts = splitRunes(rs, limit)
+		if len(strconv.Itoa(len(parts))) <= d {
+			break
+		}
+	}
+	for i := range parts {
+		parts[i] += " (" + strconv.Itoa(i+1) + "/" + strconv.Itoa(len(parts)) + ")"
+	}
+	return parts
+}
+
+// splitRunes corta rs em partes de no máximo limit caracteres, já contando
+// os marcadores fechados no fim de cada parte e reabertos na seguinte.
+func splitRunes(rs []rune, limit int) []string {
+	var parts []string
+	rest, prefix := rs, ""
+	for len(rest) > 0 {
+		if runeLen(prefix)+len(rest) <= limit {
+			parts = append(parts, prefix+string(rest))
+			break
+		}
+		if runeLen(prefix) >= limit {
+			prefix = "" // sem espaço para reabrir a formatação
+		}
+		// os marcadores a fechar só são conhecidos depois do corte: se não
+		// couberem, corta mais cedo
+		avail := limit - runeLen(prefix)
+		chunk, next, nextPrefix := cutChunk(rest, prefix, avail, true)
+		for runeLen(chunk) > limit && avail > 1 {
+			avail = max(avail-(runeLen(chunk)-limit), 1)
+			chunk, next, nextPrefix = cutChunk(rest, prefix, avail, true)
+		}
+		if runeLen(chunk) > limit {
+			// nem o menor corte comporta os marcadores: corta sem eles
+			chunk, next, nextPrefix = cutChunk(rest, "", limit, false)
+		}
+		parts = append(parts, chunk)
+		rest, prefix = next, nextPrefix
+	}
+	return parts
+}
+
+// cutChunk tira de rest uma parte com até avail caracteres de conteúdo
+// (além de prefix) e devolve a parte com os marcadores abertos já fechados,
+// o restante e os marcadores a reabrir nele. Sem format, corta só o texto.
+func cutChunk(rest []rune, prefix string, avail int, format bool) (chunk string, next []rune, nextPrefix string) {
+	cut := splitPoint(rest, avail)
+	chunk = prefix + strings.TrimRightFunc(string(rest[:cut]), unicode.IsSpace)
+	next = []rune(strings.TrimLeftFunc(string(rest[cut:]), unicode.IsSpace))
+	if !format {
+		return chunk, next, ""
+	}
+
+	open := openMarkers(chunk)
+	if n := len(open); n > 0 && open[n-1] == "```" && strings.HasSuffix(chunk, "```") && strings.TrimSpace(chunk) != "```" {
+		// corte logo depois de abrir um bloco: o bloco vai inteiro para a
+		// próxima parte
+		chunk = strings.TrimRightFunc(strings.TrimSuffix(chunk, "```"), unicode.IsSpace)
+		next = append([]rune("```\n"), next...)
+		open = open[:n-1]
+	}
+	// *, _ e ~ não atravessam linhas: só reabre se o fechamento estiver na
+	// primeira linha do restante
+	nextLine, _, _ := strings.Cut(string(next), "\n")
+	open = slices.DeleteFunc(open, func(m string) bool {
+		return m != "```" && !strings.Contains(nextLine, m)
+	})
+	for i := len(open) - 1; i >= 0; i-- {
+		chunk += open[i]
+	}
+	return chunk, next, strings.Join(open, "")
+}
+
+func runeLen(s string) int { return utf8.RuneCountInString(s) }
+
+// splitPoint escolhe onde cortar rs para que a parte tenha no máximo limit
+// caracteres. Quebras muito próximas do início são ignoradas para não gerar
+// partes minúsculas.
+func splitPoint(rs []rune, limit int) int {
+	window := rs[:limit+1] // inclui o caractere seguinte: cortar antes dele também vale
+	minCut := max(limit/3, 1)
+	for _, sep := range []string{"\n\n", "\n"} {
+		if i := strings.LastIndex(string(window), sep); i >= 0 {
+			if at := len([]rune(string(window)[:i])); at >= minCut {
+				return at
+			}
+		}
+	}
+	for i := limit; i >= minCut; i-- {
+		if unicode.IsSpace(window[i]) {
+			return i
+		}
+	}
+	return graphemeCut(rs, limit)
+}
+
+// graphemeCut recua a partir de i até um ponto que não separe um cluster de
+// grafemas (aproximação sem tabelas Unicode completas).
+func graphemeCut(rs []rune, i int) int {
+	for j := i; j > 0; j-- {
+		if !joinsPrevious(rs, j) {
+			return j
+		}
+	}
+	return i
+}
+
+// joinsPrevious diz se rs[i] faz parte do mesmo grafema que rs[i-1].
+func joinsPrevious(rs []rune, i int) bool {
+	r, prev := rs[i], rs[i-1]
+	switch {
+	case unicode.In(r, unicode.Mn, unicode.Me, unicode.Mc):
+		return true // acentos combinantes
+	case r == 0x200D || prev == 0x200D:
+		return true // zero width joiner (👨‍👩‍👧)
+	case r >= 0xFE00 && r <= 0xFE0F:
+		return true // seletores de variação
+	case r >= 0x1F3FB && r <= 0x1F3FF:
+		return true // tons de pele
+	case r >= 0xE0020 && r <= 0xE007F:
+		return true // tags (bandeiras de subdivisões)
+	case r == 0x20E3:
+		return true // keycap
+	case isRegional(r) && isRegional(prev):
+		// bandeiras são pares de indicadores regionais: junta se prev abre um par
+		n := 0
+		for k := i - 1; k >= 0 && isRegional(rs[k]); k-- {
+			n++
+		}
+		return n%2 == 1
+	}
+	return false
+}
+
+func isRegional(r rune) bool { return r >= 0x1F1E6 && r <= 0x1F1FF }
+
+// openMarkers retorna os marcadores de formatação abertos ao fim de s, na
+// ordem em que foram abertos. Segue as regras do WhatsApp: o marcador abre
+// no início de palavra, fecha no fim e não atravessa linhas; dentro de ```
+// nada é interpretado.
+func openMarkers(s string) []string {
+	var stack []string
+	rs := []rune(s)
+	inCode := false
+	for i := 0; i < len(rs); i++ {
+		if i+2 < len(rs) && rs[i] == '`' && rs[i+1] == '`' && rs[i+2] == '`' {
+			inCode = !inCode
+			i += 2
+			continue
+		}
+		if !inCode && rs[i] == '\n' {
+			stack = stack[:0]
+		}
+		if inCode || !strings.ContainsRune("*_~", rs[i]) {
+			continue
+		}
+		if i+1 < len(rs) && rs[i+1] == zwspRune {
+			continue // escapado por Escape
+		}
+		m := string(rs[i])
+		before := i == 0 || !isWordRune(rs[i-1])
+		after := i+1 == len(rs) || !isWordRune(rs[i+1])
+		nextSpace := i+1 == len(rs) || unicode.IsSpace(rs[i+1])
+		prevSpace := i == 0 || unicode.IsSpace(rs[i-1])
+		if k := lastIndexOf(stack, m); k >= 0 && after && !prevSpace {
+			stack = stack[:k]
+			continue
+		}
+		if before && !nextSpace {
+			stack = append(stack, m)
+		}
+	}
+	if inCode {
+		stack = append(stack, "```")
+	}
+	return stack
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+const zwspRune = '\u200b'
+
+func lastIndexOf(stack []string, m string) int {
+	for i := len(stack) - 1; i >= 0; i-- {
+		if stack[i] == m {
+			return i
+		}
+	}
+	return -1
+}
+
+// SendLongMessage divide text com SplitText e envia as partes em ordem, uma
+// por vez: cada parte só sai depois que o servidor confirmou a anterior.
+// Retorna os message_id na ordem das partes; em caso de erro, os já enviados
+// e um erro indicando a parte que falhou.
+func (c *Client) SendLongMessage(ctx context.Context, phone, text string, split SplitOptions, opts ...SendOption) ([]string, error) {
+	parts := SplitText(text, split)
+	ids := make([]string, 0, len(parts))
+	for i, part := range parts {
+		resp, err := c.SendMessage(ctx, phone, part, opts...)
+		if err != nil {
+			return ids, fmt.Errorf("gowa: send part %d/%d: %w", i+1, len(parts), err)
+		}
+		ids = append(ids, resp.Results.MessageID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,84 @@
+package gowa_test
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+)
+
+func TestSplitText(t *testing.T) {
+	para := strings.Repeat("palavra ", 10) // 80 caracteres
+	tests := []struct {
+		name string
+		text string
+		opts gowa.SplitOptions
+		want []string
+	}{
+		{"short", "  oi  ", gowa.SplitOptions{}, []string{"oi"}},
+		{"exact", strings.Repeat("a", 50), gowa.SplitOptions{MaxLen: 50}, []string{strings.Repeat("a", 50)}},
+		{"below minimum", strings.Repeat("a", 16), gowa.SplitOptions{MaxLen: 5}, []string{strings.Repeat("a", 16)}},
+		{
+			"paragraphs",
+			"primeiro parágrafo\n\nsegundo parágrafo, um pouco maior",
+			gowa.SplitOptions{MaxLen: 50},
+			[]string{"primeiro parágrafo", "segundo parágrafo, um pouco maior"},
+		},
+		{
+			"numbered",
+			"primeiro parágrafo\nsegunda linha\n\nterceiro parágrafo",
+			gowa.SplitOptions{MaxLen: 50, Number: true},
+			[]string{"primeiro parágrafo\nsegunda linha (1/2)", "terceiro parágrafo (2/2)"},
+		},
+		{
+			"bold across cut",
+			"*" + strings.TrimSpace(para) + "*",
+			gowa.SplitOptions{MaxLen: 50},
+			[]string{
+				"*palavra palavra palavra palavra palavra palavra*",
+				"*palavra palavra palavra palavra*",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := gowa.SplitText(tt.text, tt.opts)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("SplitText = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitTextLimits(t *testing.T) {
+	text := strings.Repeat("Olá 👨\u200d👩\u200d👧 *mundo* _itálico_ 🇧🇷🇵🇹 e\u0301 ❤\ufe0f ", 60) +
+		"\n\n```\n" + strings.Repeat("código ", 40) + "\n```\n" +
+		strings.Repeat("x", 300)
+	text += "\n" + strings.Repeat("*negrito com várias palavras* _itálico_ ~riscado~ ```mono``` ", 30)
+	for _, maxLen := range []int{gowa.MinSplitLen, 20, 25, 40, 64, 100, 257, 1000} {
+		for _, number := range []bool{false, true} {
+			parts := gowa.SplitText(text, gowa.SplitOptions{MaxLen: maxLen, Number: number})
+			if len(parts) < 2 {
+				t.Fatalf("MaxLen %d: expected several parts, got %d", maxLen, len(parts))
+			}
+			for i, p := range parts {
+				if n := utf8.RuneCountInString(p); n > maxLen {
+					t.Errorf("MaxLen %d number=%v: part %d has %d chars", maxLen, number, i, n)
+				}
+				// nenhum corte separa ZWJ, seletor de variação, acento
+				// combinante ou bandeira do caractere anterior
+				first, _ := utf8.DecodeRuneInString(p)
+				if first == '\u200d' || first == '\ufe0f' || first == '\u0301' {
+					t.Errorf("MaxLen %d: part %d starts inside a grapheme: %q", maxLen, i, p)
+				}
+				if strings.Count(p, "🇧🇷")+strings.Count(p, "🇵🇹") != strings.Count(p, "\U0001F1E7")+strings.Count(p, "\U0001F1F5") {
+					t.Errorf("MaxLen %d: part %d splits a flag: %q", maxLen, i, p)
+				}
+				if strings.Count(p, "```")%2 != 0 {
+					t.Errorf("MaxLen %d: part %d leaves a code block open: %q", maxLen, i, p)
+				}
+			}
+		}
+	}
+}
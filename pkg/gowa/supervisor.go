@@ -0,0 +1,208 @@
+package gowa
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+type SessionState string
+
+const (
+	StateUnknown      SessionState = "unknown"
+	StateConnected    SessionState = "connected"    // há dispositivo conectado
+	StateDisconnected SessionState = "disconnected" // sessão existe mas caiu; Reconnect pode resolver
+	StateLoggedOut    SessionState = "logged_out"   // sem dispositivo pareado; requer novo login
+	StateServerDown   SessionState = "server_down"  // servidor gowa inacessível ou com erro
+)
+
+// StateChange é emitido a cada transição de estado da sessão.
+type StateChange struct {
+	From SessionState
+	To   SessionState
+	Err  error // erro do probe que levou ao novo estado, se houver
+	At   time.Time
+}
+
+// Pausable é implementado por filas de envio que devem parar enquanto a
+// sessão não está conectada.
+type Pausable interface {
+	Pause()
+	Resume()
+}
+
+type SupervisorConfig struct {
+	Interval      time.Duration // intervalo entre probes com a sessão conectada; default 15s
+	ProbeTimeout  time.Duration // default 5s
+	BackoffMin    time.Duration // primeiro intervalo entre Reconnects; default 1s
+	BackoffMax    time.Duration // default 1m
+	OnStateChange func(StateChange)
+	Pausables     []Pausable
+	Clock         Clock // esperas entre probes e hora dos StateChange; default relógio do sistema
+}
+
+// Supervisor monitora a sessão via /app/devices, chama Reconnect com backoff
+// exponencial quando ela cai e pausa as filas registradas enquanto não está
+// conectada.
+type Supervisor struct {
+	c   *Client
+	cfg SupervisorConfig
+
+	mu      sync.Mutex
+	state   SessionState
+	changed chan struct{} // fechado e recriado a cada transição
+	paused  bool
+}
+
+func NewSupervisor(c *Client, cfg SupervisorConfig) (*Supervisor, error) {
+	if c == nil {
+		return nil, errors.New("client is required")
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = 15 * time.Second
+	}
+	if cfg.ProbeTimeout <= 0 {
+		cfg.ProbeTimeout = 5 * time.Second
+	}
+	if cfg.BackoffMin <= 0 {
+		cfg.BackoffMin = time.Second
+	}
+	if cfg.BackoffMax < cfg.BackoffMin {
+		cfg.BackoffMax = time.Minute
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = systemClock{}
+	}
+	return &Supervisor{c: c, cfg: cfg, state: StateUnknown, changed: make(chan struct{})}, nil
+}
+
+// Probe consulta o servidor uma vez e classifica o estado da sessão, sem
+// alterar o estado do Supervisor.
+func (s *Supervisor) Probe(ctx context.Context) (SessionState, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.ProbeTimeout)
+	defer cancel()
+	resp, err := s.c.Devices(ctx)
+	if err != nil {
+		return classifyProbeError(err), err
+	}
+	if len(resp.Results) == 0 {
+		return StateLoggedOut, nil
+	}
+	return StateConnected, nil
+}
+
+func classifyProbeError(err error) SessionState {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return StateServerDown
+	}
+	msg := strings.ToLower(apiErr.Message + " " + apiErr.Body)
+	for _, s := range []string{"not loggin", "not login", "not logged", "not connected", "disconnected"} {
+		if strings.Contains(msg, s) {
+			return StateDisconnected
+		}
+	}
+	// demais erros (inclusive 401 do BasicAuth) não dizem respeito à sessão
+	return StateServerDown
+}
+
+// Run executa o ciclo de monitoramento até ctx terminar.
+func (s *Supervisor) Run(ctx context.Context) error {
+	backoff := s.cfg.BackoffMin
+	for {
+		st, err := s.Probe(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		s.setState(st, err)
+
+		wait := s.cfg.Interval
+		switch st {
+		case StateConnected:
+			backoff = s.cfg.BackoffMin
+		case StateDisconnected:
+			if rerr := s.reconnect(ctx); rerr == nil {
+				// verifica logo em seguida se a sessão voltou
+				wait = s.cfg.BackoffMin
+				break
+			}
+			wait = jitter(backoff)
+			backoff = min(backoff*2, s.cfg.BackoffMax)
+		case StateServerDown:
+			wait = min(jitter(backoff), s.cfg.Interval)
+			backoff = min(backoff*2, s.cfg.BackoffMax)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.cfg.Clock.After(wait):
+		}
+	}
+}
+
+func (s *Supervisor) reconnect(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.ProbeTimeout)
+	defer cancel()
+	_, err := s.c.Reconnect(ctx)
+	return err
+}
+
+func jitter(d time.Duration) time.Duration {
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func (s *Supervisor) setState(st SessionState, err error) {
+	s.mu.Lock()
+	from := s.state
+	if from == st {
+		s.mu.Unlock()
+		return
+	}
+	s.state = st
+	close(s.changed)
+	s.changed = make(chan struct{})
+	pause := st != StateConnected && !s.paused
+	resume := st == StateConnected && s.paused
+	s.paused = st != StateConnected
+	s.mu.Unlock()
+
+	for _, p := range s.cfg.Pausables {
+		switch {
+		case pause:
+			p.Pause()
+		case resume:
+			p.Resume()
+		}
+	}
+	if s.cfg.OnStateChange != nil {
+		s.cfg.OnStateChange(StateChange{From: from, To: st, Err: err, At: s.cfg.Clock.Now()})
+	}
+}
+
+// State retorna o último estado observado.
+func (s *Supervisor) State() SessionState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// WaitConnected bloqueia até a sessão estar conectada ou ctx terminar.
+func (s *Supervisor) WaitConnected(ctx context.Context) error {
+	for {
+		s.mu.Lock()
+		st, ch := s.state, s.changed
+		s.mu.Unlock()
+		if st == StateConnected {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ch:
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package gowa_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+func TestSupervisorProbe(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		session gowa.SessionState
+		fault   *gowatest.Fault
+		want    gowa.SessionState
+	}{
+		{"connected", gowa.StateConnected, nil, gowa.StateConnected},
+		{"logged out", gowa.StateLoggedOut, nil, gowa.StateLoggedOut},
+		{"disconnected", gowa.StateDisconnected, nil, gowa.StateDisconnected},
+		{"disconnected body", gowa.StateConnected, &gowatest.Fault{Path: "/app/devices", Status: 500, Body: `{"code":"INTERNAL_SERVER_ERROR","message":"websocket disconnected"}`}, gowa.StateDisconnected},
+		{"server error", gowa.StateConnected, &gowatest.Fault{Path: "/app/devices", Status: 503}, gowa.StateServerDown},
+		// 401 do BasicAuth não é problema de sessão
+		{"unauthorized", gowa.StateConnected, &gowatest.Fault{Path: "/app/devices", Status: 401, Body: `{"code":"401","message":"Unauthorized"}`}, gowa.StateServerDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := gowatest.NewServer(gowatest.Config{Session: tt.session})
+			defer srv.Close()
+			if tt.fault != nil {
+				srv.AddFault(*tt.fault)
+			}
+			sup, err := gowa.NewSupervisor(srv.GowaClient(), gowa.SupervisorConfig{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, _ := sup.Probe(ctx); got != tt.want {
+				t.Errorf("Probe = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("network error", func(t *testing.T) {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		addr := l.Addr().String()
+		l.Close()
+		c, err := gowa.New(gowa.Config{BaseURL: "http://" + addr, RetryMax: -1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sup, _ := gowa.NewSupervisor(c, gowa.SupervisorConfig{})
+		if got, err := sup.Probe(ctx); got != gowa.StateServerDown || err == nil {
+			t.Errorf("Probe = %s, %v; want server_down with the dial error", got, err)
+		}
+	})
+}
+
+// supervisorRun roda o Supervisor com o relógio manual e devolve um contador
+// das chamadas a /app/reconnect.
+func supervisorRun(t *testing.T, srv *gowatest.Server, cfg gowa.SupervisorConfig) func() int {
+	t.Helper()
+	sup, err := gowa.NewSupervisor(srv.GowaClient(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		sup.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+	})
+	return func() int {
+		n := 0
+		for _, r := range srv.Requests() {
+			if r.Path == "/app/reconnect" {
+				n++
+			}
+		}
+		return n
+	}
+}
+
+// nextWait confere que a próxima espera do Supervisor fica em [lo, hi]: o
+// relógio avança até logo antes de lo sem disparar e depois até hi.
+func nextWait(t *testing.T, clk *gowatest.Clock, lo, hi time.Duration) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for clk.Waiters() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("supervisor not waiting on the clock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	clk.Advance(lo - time.Millisecond)
+	if clk.Waiters() == 0 {
+		t.Fatalf("wait shorter than %s", lo)
+	}
+	clk.Advance(hi - lo + time.Millisecond)
+	if clk.Waiters() != 0 {
+		t.Fatalf("wait longer than %s", hi)
+	}
+}
+
+func TestSupervisorDecisions(t *testing.T) {
+	const (
+		minB     = time.Second
+		interval = 10 * time.Second
+	)
+	tests := []struct {
+		name      string
+		session   gowa.SessionState
+		fault     *gowatest.Fault
+		reconnect bool // Reconnect resolve; deslogado exige novo login
+		lo, hi    time.Duration
+		state     gowa.SessionState
+	}{
+		{"connected", gowa.StateConnected, nil, false, interval, interval, gowa.StateConnected},
+		{"logged out", gowa.StateLoggedOut, nil, false, interval, interval, gowa.StateLoggedOut},
+		{"disconnected", gowa.StateDisconnected, nil, true, minB, minB, gowa.StateDisconnected},
+		{"server down", gowa.StateConnected, &gowatest.Fault{Path: "/app/devices", Status: 503}, false, minB / 2, minB, gowa.StateServerDown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := gowatest.NewServer(gowatest.Config{Session: tt.session})
+			defer srv.Close()
+			if tt.fault != nil {
+				srv.AddFault(*tt.fault)
+			}
+			clk := gowatest.NewClock(time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC))
+			var mu sync.Mutex
+			var changes []gowa.StateChange
+			reconnects := supervisorRun(t, srv, gowa.SupervisorConfig{
+				Interval: interval, BackoffMin: minB, BackoffMax: 8 * minB, Clock: clk,
+				OnStateChange: func(ev gowa.StateChange) {
+					mu.Lock()
+					changes = append(changes, ev)
+					mu.Unlock()
+				},
+			})
+			nextWait(t, clk, tt.lo, tt.hi)
+			if got := reconnects() > 0; got != tt.reconnect {
+				t.Errorf("reconnect called = %v, want %v", got, tt.reconnect)
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			if len(changes) == 0 || changes[0].To != tt.state || !changes[0].At.Equal(time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC)) {
+				t.Errorf("changes = %+v, want first to %s at the clock time", changes, tt.state)
+			}
+		})
+	}
+}
+
+func TestSupervisorBackoff(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{Session: gowa.StateDisconnected})
+	defer srv.Close()
+	srv.AddFault(gowatest.Fault{Path: "/app/reconnect", Status: http.StatusInternalServerError})
+	clk := gowatest.NewClock(time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC))
+	reconnects := supervisorRun(t, srv, gowa.SupervisorConfig{
+		Interval: time.Minute, BackoffMin: time.Second, BackoffMax: 4 * time.Second, Clock: clk,
+	})
+
+	// Reconnect falhando: 1s, 2s, 4s e então o teto, sempre com jitter [d/2, d]
+	for i, d := range []time.Duration{1, 2, 4, 4} {
+		d *= time.Second
+		nextWait(t, clk, d/2, d)
+		if n := reconnects(); n != i+1 {
+			t.Fatalf("attempt %d: %d reconnects", i+1, n)
+		}
+	}
+	// Reconnect volta a funcionar: confere logo em seguida, depois no Interval
+	srv.ClearFaults()
+	nextWait(t, clk, time.Second, time.Second)
+	nextWait(t, clk, time.Minute, time.Minute)
+
+	// nova queda recomeça do BackoffMin
+	srv.SetSession(gowa.StateDisconnected)
+	srv.AddFault(gowatest.Fault{Path: "/app/reconnect", Status: http.StatusInternalServerError})
+	nextWait(t, clk, time.Second/2, time.Second)
+}
+
+func TestSupervisorWaitConnected(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{Session: gowa.StateLoggedOut})
+	defer srv.Close()
+	clk := gowatest.NewClock(time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC))
+	sup, err := gowa.NewSupervisor(srv.GowaClient(), gowa.SupervisorConfig{Interval: time.Second, Clock: clk})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go sup.Run(ctx)
+
+	short, scancel := context.WithTimeout(ctx, 20*time.Millisecond)
+	defer scancel()
+	if err := sup.WaitConnected(short); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitConnected while logged out = %v", err)
+	}
+	srv.CompleteLogin()
+	nextWait(t, clk, time.Second, time.Second)
+	wctx, wcancel := context.WithTimeout(ctx, 5*time.Second)
+	defer wcancel()
+	if err := sup.WaitConnected(wctx); err != nil || sup.State() != gowa.StateConnected {
+		t.Errorf("WaitConnected = %v, state %s", err, sup.State())
+	}
+}
@@ -0,0 +1,217 @@
+package gowa
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	retryablehttp "github.com/hashicorp/go-retryablehttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifica o client nos traces e métricas.
+const instrumentationName = "github.com/drksbr/gowa-client/pkg/gowa"
+
+// Atributos dos spans e métricas. Os de HTTP seguem as convenções semânticas
+// do OpenTelemetry; os demais usam o prefixo gowa.
+const (
+	attrOperation     = attribute.Key("gowa.operation")
+	attrRecipientKind = attribute.Key("gowa.recipient.kind")
+	attrRetries       = attribute.Key("gowa.retry.count")
+	attrSendType      = attribute.Key("gowa.send.type")
+	attrErrorCode     = attribute.Key("gowa.error.code")
+	attrLimitScope    = attribute.Key("gowa.ratelimit.scope")
+	attrMethod        = attribute.Key("http.request.method")
+	attrRoute         = attribute.Key("http.route")
+	attrStatus        = attribute.Key("http.response.status_code")
+)
+
+// telemetry agrupa o tracer e os instrumentos do client. Sem TracerProvider
+// ou MeterProvider configurados (em Config ou globalmente via otel.Set*),
+// tudo é no-op.
+type telemetry struct {
+	tracer   trace.Tracer
+	duration metric.Float64Histogram
+	sends    metric.Int64Counter
+	errors   metric.Int64Counter
+	limited  metric.Float64Histogram
+	rejected metric.Int64Counter
+}
+
+func newTelemetry(tp trace.TracerProvider, mp metric.MeterProvider) *telemetry {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	if mp == nil {
+		mp = otel.GetMeterProvider()
+	}
+	meter := mp.Meter(instrumentationName)
+	t := &telemetry{tracer: tp.Tracer(instrumentationName)}
+	// Erros na criação dos instrumentos só ocorrem com nomes inválidos; nesse
+	// caso o OTel devolve instrumentos no-op e o client segue funcionando.
+	t.duration, _ = meter.Float64Histogram("gowa.client.request.duration",
+		metric.WithUnit("s"), metric.WithDescription("Duração das chamadas à API gowa, incluindo retries"))
+	t.sends, _ = meter.Int64Counter("gowa.client.sends",
+		metric.WithUnit("{message}"), metric.WithDescription("Envios (/send/*) aceitos pelo servidor, por tipo"))
+	t.errors, _ = meter.Int64Counter("gowa.client.errors",
+		metric.WithUnit("{error}"), metric.WithDescription("Chamadas com erro, por código do APIError"))
+	t.limited, _ = meter.Float64Histogram("gowa.client.ratelimit.wait",
+		metric.WithUnit("s"), metric.WithDescription("Espera imposta pelo rate limit antes de cada envio"))
+	t.rejected, _ = meter.Int64Counter("gowa.client.ratelimit.rejected",
+		metric.WithUnit("{message}"), metric.WithDescription("Envios recusados com ErrRateLimited, por orçamento"))
+	return t
+}
+
+// start abre o span da chamada. O contexto retornado carrega o contador de
+// tentativas alimentado por countAttempt.
+func (t *telemetry) start(ctx context.Context, call *Call) (context.Context, *callSpan) {
+	attrs := []attribute.KeyValue{
+		attrOperation.String(call.Operation),
+		attrMethod.String(call.Method),
+	}
+	// o template, não o path montado: JIDs e nomes de arquivo no path
+	// explodiriam a cardinalidade
+	if call.Route != "" {
+		attrs = append(attrs, attrRoute.String(call.Route))
+	}
+	if kind := recipientKind(call); kind != "" {
+		attrs = append(attrs, attrRecipientKind.String(kind))
+	}
+	ctx, span := t.tracer.Start(ctx, call.Operation, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+	cs := &callSpan{t: t, span: span, call: call, begin: time.Now()}
+	return context.WithValue(ctx, attemptsKey{}, &cs.attempts), cs
+}
+
+type callSpan struct {
+	t        *telemetry
+	span     trace.Span
+	call     *Call
+	begin    time.Time
+	attempts atomic.Int64
+}
+
+// end fecha o span e registra as métricas. status é 0 quando não houve
+// resposta HTTP (erro de rede, contexto cancelado).
+func (cs *callSpan) end(ctx context.Context, status int, err error) {
+	defer cs.span.End()
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		status = apiErr.StatusCode
+	}
+	metricAttrs := []attribute.KeyValue{attrOperation.String(cs.call.Operation), attrMethod.String(cs.call.Method)}
+	if status != 0 {
+		cs.span.SetAttributes(attrStatus.Int(status))
+		metricAttrs = append(metricAttrs, attrStatus.Int(status))
+	}
+	if n := cs.attempts.Load(); n > 1 {
+		cs.span.SetAttributes(attrRetries.Int64(n - 1))
+	}
+	cs.t.duration.Record(ctx, time.Since(cs.begin).Seconds(), metric.WithAttributes(metricAttrs...))
+
+	if err != nil {
+		code := errorCode(err)
+		cs.span.RecordError(err)
+		cs.span.SetStatus(codes.Error, code)
+		cs.span.SetAttributes(attrErrorCode.String(code))
+		cs.t.errors.Add(ctx, 1, metric.WithAttributes(attrOperation.String(cs.call.Operation), attrErrorCode.String(code)))
+		return
+	}
+	if typ, ok := strings.CutPrefix(cs.call.Path, "/send/"); ok {
+		sendAttrs := []attribute.KeyValue{attrSendType.String(typ)}
+		if kind := recipientKind(cs.call); kind != "" {
+			sendAttrs = append(sendAttrs, attrRecipientKind.String(kind))
+		}
+		cs.t.sends.Add(ctx, 1, metric.WithAttributes(sendAttrs...))
+	}
+}
+
+// rateLimit registra a passagem pelo rate limit: a espera (inclusive zero) ou
+// a recusa com ErrRateLimited.
+func (cs *callSpan) rateLimit(ctx context.Context, waited time.Duration, err error) {
+	op := metric.WithAttributes(attrOperation.String(cs.call.Operation))
+	var rl *RateLimitError
+	if errors.As(err, &rl) {
+		cs.span.SetAttributes(attrLimitScope.String(rl.Scope))
+		cs.t.rejected.Add(ctx, 1, metric.WithAttributes(attrOperation.String(cs.call.Operation), attrLimitScope.String(rl.Scope)))
+		return
+	}
+	if err == nil {
+		if waited > 0 {
+			cs.span.AddEvent("rate limit wait", trace.WithAttributes(attribute.Float64("gowa.ratelimit.wait", waited.Seconds())))
+		}
+		cs.t.limited.Record(ctx, waited.Seconds(), op)
+	}
+}
+
+// errorCode resume o erro para atributos de baixa cardinalidade: o Code do
+// APIError, "http_<status>" se o servidor não enviou um, ou a categoria do
+// erro de transporte.
+func errorCode(err error) string {
+	var apiErr *APIError
+	switch {
+	case errors.As(err, &apiErr) && apiErr.Code != "":
+		return apiErr.Code
+	case apiErr != nil:
+		return "http_" + strconv.Itoa(apiErr.StatusCode)
+	case errors.Is(err, ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	default:
+		return "transport"
+	}
+}
+
+// recipientKind classifica o destinatário da chamada (campo phone) em user
+// ou group. Retorna "" quando a operação não tem destinatário.
+func recipientKind(call *Call) string {
+	phone := callRecipient(call)
+	switch {
+	case phone == "":
+		return ""
+	case strings.HasSuffix(phone, "@g.us"):
+		return "group"
+	default:
+		return "user"
+	}
+}
+
+// callRecipient extrai o campo phone da chamada (multipart, query ou request
+// tipado).
+func callRecipient(call *Call) string {
+	phone := call.Form["phone"]
+	if phone == "" {
+		phone = call.Query.Get("phone")
+	}
+	if phone == "" && call.Request != nil {
+		v := reflect.Indirect(reflect.ValueOf(call.Request))
+		if v.Kind() == reflect.Struct {
+			if f := v.FieldByName("Phone"); f.IsValid() && f.Kind() == reflect.String {
+				phone = f.String()
+			}
+		}
+	}
+	return phone
+}
+
+type attemptsKey struct{}
+
+// countAttempt é chamado a cada tentativa do retryablehttp (via
+// Client.requestHook) e conta as tentativas no contador guardado no contexto
+// por telemetry.start.
+func countAttempt(_ retryablehttp.Logger, req *http.Request, _ int) {
+	if n, ok := req.Context().Value(attemptsKey{}).(*atomic.Int64); ok {
+		n.Add(1)
+	}
+}
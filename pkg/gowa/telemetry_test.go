@@ -0,0 +1,80 @@
+package gowa_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// spanRecorder guarda os atributos de início de cada span.
+type spanRecorder struct {
+	noop.TracerProvider
+	mu    sync.Mutex
+	spans map[string][]attribute.KeyValue
+}
+
+func (r *spanRecorder) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return recordingTracer{r: r}
+}
+
+type recordingTracer struct {
+	noop.Tracer
+	r *spanRecorder
+}
+
+func (t recordingTracer) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	cfg := trace.NewSpanStartConfig(opts...)
+	t.r.mu.Lock()
+	t.r.spans[name] = cfg.Attributes()
+	t.r.mu.Unlock()
+	return t.Tracer.Start(ctx, name, opts...)
+}
+
+func (r *spanRecorder) attr(span string, key attribute.Key) (string, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, kv := range r.spans[span] {
+		if kv.Key == key {
+			return kv.Value.AsString(), true
+		}
+	}
+	return "", false
+}
+
+func TestTelemetryRoute(t *testing.T) {
+	ctx := context.Background()
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	rec := &spanRecorder{spans: map[string][]attribute.KeyValue{}}
+	var routes []string
+	c, err := gowa.New(gowa.Config{
+		BaseURL:        srv.URL,
+		HTTPClient:     srv.Server.Client(),
+		RetryMax:       -1,
+		TracerProvider: rec,
+		Middlewares: []gowa.Middleware{func(next gowa.Doer) gowa.Doer {
+			return gowa.DoerFunc(func(ctx context.Context, call *gowa.Call) error {
+				routes = append(routes, call.Route)
+				return next.Do(ctx, call)
+			})
+		}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, _ = c.Raw().GetChatMessages(ctx, gowa.GetChatMessagesRequest{ChatJID: "5511999990000@s.whatsapp.net"})
+	const want = "/chat/{chat_jid}/messages"
+	if got, _ := rec.attr("getChatMessages", "http.route"); got != want {
+		t.Errorf("http.route = %q, want %q", got, want)
+	}
+	if len(routes) != 1 || routes[0] != want {
+		t.Errorf("Call.Route = %q, want %q", routes, want)
+	}
+}
@@ -0,0 +1,502 @@
+package gowa
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/quotedprintable"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// VCard é um contato no formato vCard 3.0 ou 4.0, com os campos usados pelo
+// WhatsApp (telefones com waid) e por agendas comuns.
+type VCard struct {
+	Version       string // "3.0" (default ao gerar) ou "4.0"
+	FormattedName string // FN; default montado a partir de Name
+	Name          VCardName
+	Nickname      string
+	Org           string   // nome da organização (1º componente de ORG)
+	OrgUnits      []string // departamentos (demais componentes de ORG)
+	Title         string
+	Phones        []VCardPhone
+	Emails        []VCardEmail
+	Addresses     []VCardAddress
+	URLs          []string
+	Birthday      string // como no arquivo, ex.: 1990-05-17
+	Note          string
+}
+
+// VCardName é a propriedade N (componentes estruturados).
+type VCardName struct {
+	Family, Given, Additional, Prefix, Suffix string
+}
+
+// VCardPhone é um telefone. WAID é o número no WhatsApp (só dígitos); com ele
+// o contato aparece com os botões de conversa no app.
+type VCardPhone struct {
+	Number string
+	WAID   string
+	Types  []string // ex.: CELL, WORK, HOME, VOICE
+}
+
+type VCardEmail struct {
+	Address string
+	Types   []string
+}
+
+// VCardAddress é a propriedade ADR.
+type VCardAddress struct {
+	POBox, Extended, Street, Locality, Region, PostalCode, Country string
+	Types                                                          []string
+}
+
+var nonDigitRe = regexp.MustCompile(`\D`)
+
+// AddPhone acrescenta um telefone com WAID derivado dos dígitos do número.
+func (v *VCard) AddPhone(number string, types ...string) *VCard {
+	v.Phones = append(v.Phones, VCardPhone{Number: number, WAID: nonDigitRe.ReplaceAllString(number, ""), Types: types})
+	return v
+}
+
+// DisplayName retorna FN ou, na falta dele, o nome montado de N.
+func (v *VCard) DisplayName() string {
+	if v.FormattedName != "" {
+		return v.FormattedName
+	}
+	var parts []string
+	for _, p := range []string{v.Name.Prefix, v.Name.Given, v.Name.Additional, v.Name.Family, v.Name.Suffix} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	if len(parts) == 0 {
+		return v.Org
+	}
+	return strings.Join(parts, " ")
+}
+
+// ContactParams monta os parâmetros de SendContact. O endpoint /send/contact
+// só aceita nome e um telefone: usa DisplayName e o primeiro telefone com
+// WAID (ou o primeiro). Para enviar o cartão completo, use Client.SendVCard.
+func (v *VCard) ContactParams(phone string) SendContactParams {
+	p := SendContactParams{Phone: phone, ContactName: v.DisplayName()}
+	for _, ph := range v.Phones {
+		if ph.WAID != "" {
+			p.ContactPhone = ph.WAID
+			return p
+		}
+	}
+	if len(v.Phones) > 0 {
+		p.ContactPhone = nonDigitRe.ReplaceAllString(v.Phones[0].Number, "")
+	}
+	return p
+}
+
+// String serializa o cartão (CRLF, linhas dobradas em 75 bytes).
+func (v *VCard) String() string {
+	var b strings.Builder
+	v.write(&b)
+	return b.String()
+}
+
+func (v *VCard) write(b *strings.Builder) {
+	version := v.Version
+	if version == "" {
+		version = "3.0"
+	}
+	v4 := version == "4.0"
+	line := func(name string, params []string, value string) {
+		s := name
+		for _, p := range params {
+			s += ";" + p
+		}
+		writeFolded(b, s+":"+value)
+	}
+	types := func(ts []string) []string {
+		var out []string
+		for _, t := range ts {
+			if v4 {
+				out = append(out, "TYPE="+strings.ToLower(t))
+			} else {
+				out = append(out, "TYPE="+strings.ToUpper(t))
+			}
+		}
+		return out
+	}
+
+	line("BEGIN", nil, "VCARD")
+	line("VERSION", nil, version)
+	line("FN", nil, vcardEscape(v.DisplayName()))
+	n := v.Name
+	if n != (VCardName{}) || !v4 {
+		line("N", nil, vcardJoin(n.Family, n.Given, n.Additional, n.Prefix, n.Suffix))
+	}
+	if v.Nickname != "" {
+		line("NICKNAME", nil, vcardEscape(v.Nickname))
+	}
+	if v.Org != "" || len(v.OrgUnits) > 0 {
+		line("ORG", nil, vcardJoin(append([]string{v.Org}, v.OrgUnits...)...))
+	}
+	if v.Title != "" {
+		line("TITLE", nil, vcardEscape(v.Title))
+	}
+	for _, p := range v.Phones {
+		params := types(p.Types)
+		if p.WAID != "" {
+			params = append(params, "waid="+p.WAID)
+		}
+		if v4 {
+			// no 4.0 o TEL é uma URI (RFC 6350, 6.4.1)
+			line("TEL", append([]string{"VALUE=uri"}, params...), telURI(p))
+		} else {
+			line("TEL", params, vcardEscape(p.Number))
+		}
+	}
+	for _, e := range v.Emails {
+		line("EMAIL", types(e.Types), vcardEscape(e.Address))
+	}
+	for _, a := range v.Addresses {
+		line("ADR", types(a.Types), vcardJoin(a.POBox, a.Extended, a.Street, a.Locality, a.Region, a.PostalCode, a.Country))
+	}
+	for _, u := range v.URLs {
+		line("URL", nil, u)
+	}
+	if v.Birthday != "" {
+		line("BDAY", nil, v.Birthday)
+	}
+	if v.Note != "" {
+		line("NOTE", nil, vcardEscape(v.Note))
+	}
+	line("END", nil, "VCARD")
+}
+
+// telURI monta a URI tel: do telefone: "tel:+<dígitos>" para números
+// internacionais (com "+" ou WAID), "tel:<dígitos>" para os demais.
+func telURI(p VCardPhone) string {
+	digits := nonDigitRe.ReplaceAllString(p.Number, "")
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(p.Number), "+") && digits != "":
+		return "tel:+" + digits
+	case p.WAID != "":
+		return "tel:+" + p.WAID
+	}
+	return "tel:" + digits
+}
+
+// writeFolded grava a linha dobrando em 75 bytes sem partir caracteres UTF-8.
+func writeFolded(b *strings.Builder, s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = 74 // o espaço da continuação conta
+	}
+	b.WriteString(s + "\r\n")
+}
+
+var vcardEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, ",", `\,`, ";", `\;`)
+
+func vcardEscape(s string) string {
+	return vcardEscaper.Replace(strings.ReplaceAll(s, "\r\n", "\n"))
+}
+
+func vcardJoin(parts ...string) string {
+	for i, p := range parts {
+		parts[i] = vcardEscape(p)
+	}
+	return strings.Join(parts, ";")
+}
+
+// vcardSplit separa um valor estruturado nos ';' não escapados e desfaz os
+// escapes de cada componente.
+func vcardSplit(s string) []string {
+	var out []string
+	var cur strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '\\' && i+1 < len(s):
+			i++
+			if s[i] == 'n' || s[i] == 'N' {
+				cur.WriteByte('\n')
+			} else {
+				cur.WriteByte(s[i])
+			}
+		case c == ';':
+			out = append(out, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	return append(out, cur.String())
+}
+
+func vcardUnescape(s string) string { return strings.Join(vcardSplit(s), ";") }
+
+// WriteVCards grava os cartões em w, um após o outro (formato .vcf).
+func WriteVCards(w io.Writer, cards ...*VCard) error {
+	var b strings.Builder
+	for _, c := range cards {
+		c.write(&b)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// ParseVCard interpreta um único cartão. Aceita vCard 2.1 (inclusive valores
+// em QUOTED-PRINTABLE com CHARSET UTF-8, US-ASCII ou ISO-8859-1), 3.0 e 4.0,
+// linhas com LF ou CRLF e propriedades agrupadas (item1.TEL).
+func ParseVCard(s string) (*VCard, error) {
+	cards, err := ParseVCards(strings.NewReader(s))
+	if err != nil {
+		return nil, err
+	}
+	if len(cards) == 0 {
+		return nil, errors.New("vcard: no BEGIN:VCARD found")
+	}
+	return cards[0], nil
+}
+
+// ParseVCards lê todos os cartões de um arquivo .vcf. Propriedades
+// desconhecidas são ignoradas.
+func ParseVCards(r io.Reader) ([]*VCard, error) {
+	lines, err := unfoldVCard(r)
+	if err != nil {
+		return nil, err
+	}
+	var out []*VCard
+	var cur *VCard
+	for _, l := range lines {
+		name, params, value, ok := parseVCardLine(l)
+		if !ok {
+			continue
+		}
+		if value, err = decodeVCardValue(params, value); err != nil {
+			return out, fmt.Errorf("vcard: %s: %w", name, err)
+		}
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCARD"):
+			cur = &VCard{}
+			continue
+		case cur == nil:
+			continue
+		case name == "END":
+			out = append(out, cur)
+			cur = nil
+			continue
+		}
+		types := params["TYPE"]
+		switch name {
+		case "VERSION":
+			cur.Version = value
+		case "FN":
+			cur.FormattedName = vcardUnescape(value)
+		case "N":
+			p := append(vcardSplit(value), make([]string, 5)...)
+			cur.Name = VCardName{Family: p[0], Given: p[1], Additional: p[2], Prefix: p[3], Suffix: p[4]}
+		case "NICKNAME":
+			cur.Nickname = vcardUnescape(value)
+		case "ORG":
+			p := vcardSplit(value)
+			for len(p) > 1 && p[len(p)-1] == "" {
+				p = p[:len(p)-1] // ";" final de alguns exportadores
+			}
+			cur.Org, cur.OrgUnits = p[0], p[1:]
+			if len(cur.OrgUnits) == 0 {
+				cur.OrgUnits = nil
+			}
+		case "TITLE":
+			cur.Title = vcardUnescape(value)
+		case "TEL":
+			num := strings.TrimPrefix(vcardUnescape(value), "tel:")
+			waid := ""
+			if w := params["WAID"]; len(w) > 0 {
+				waid = w[0]
+			}
+			cur.Phones = append(cur.Phones, VCardPhone{Number: num, WAID: waid, Types: types})
+		case "EMAIL":
+			cur.Emails = append(cur.Emails, VCardEmail{Address: vcardUnescape(value), Types: types})
+		case "ADR":
+			p := append(vcardSplit(value), make([]string, 7)...)
+			cur.Addresses = append(cur.Addresses, VCardAddress{
+				POBox: p[0], Extended: p[1], Street: p[2], Locality: p[3], Region: p[4], PostalCode: p[5], Country: p[6],
+				Types: types,
+			})
+		case "URL":
+			cur.URLs = append(cur.URLs, vcardUnescape(value))
+		case "BDAY":
+			cur.Birthday = value
+		case "NOTE":
+			cur.Note = vcardUnescape(value)
+		}
+	}
+	if cur != nil {
+		return out, errors.New("vcard: missing END:VCARD")
+	}
+	return out, nil
+}
+
+// unfoldVCard junta as linhas de continuação (iniciadas por espaço ou tab) e
+// as quebras suaves do QUOTED-PRINTABLE do 2.1 (linha terminada em "=").
+func unfoldVCard(r io.Reader) ([]string, error) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+	var lines []string
+	softBreak := false
+	for sc.Scan() {
+		l := strings.TrimRight(sc.Text(), "\r")
+		if softBreak {
+			lines[len(lines)-1] += "\r\n" + l // o decoder remove "=\r\n"
+			softBreak = strings.HasSuffix(l, "=")
+			continue
+		}
+		if (strings.HasPrefix(l, " ") || strings.HasPrefix(l, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += l[1:]
+			continue
+		}
+		if head, _, _ := cutUnquoted(l, ':'); strings.Contains(strings.ToUpper(head), "QUOTED-PRINTABLE") {
+			softBreak = strings.HasSuffix(l, "=")
+		}
+		if strings.TrimSpace(l) != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines, sc.Err()
+}
+
+// parseVCardLine separa "grupo.NOME;PARAM=a,b;PARAM2=c:valor". Os nomes de
+// propriedade e parâmetro voltam em maiúsculas; parâmetros sem nome (vCard
+// 2.1, ex.: TEL;CELL) viram TYPE, exceto as codificações (NOTE;QUOTED-PRINTABLE),
+// que viram ENCODING.
+func parseVCardLine(l string) (name string, params map[string][]string, value string, ok bool) {
+	head, value, ok := cutUnquoted(l, ':')
+	if !ok {
+		return "", nil, "", false
+	}
+	fields := strings.Split(head, ";")
+	name = strings.ToUpper(fields[0])
+	if _, after, grouped := strings.Cut(name, "."); grouped {
+		name = after
+	}
+	params = map[string][]string{}
+	for _, f := range fields[1:] {
+		k, v, hasValue := strings.Cut(f, "=")
+		if !hasValue {
+			k, v = "TYPE", f
+			if vcardEncodings[strings.ToUpper(f)] {
+				k = "ENCODING"
+			}
+		}
+		k = strings.ToUpper(k)
+		for _, item := range strings.Split(strings.Trim(v, `"`), ",") {
+			if item != "" {
+				if k == "TYPE" {
+					item = strings.ToUpper(item)
+				}
+				params[k] = append(params[k], item)
+			}
+		}
+	}
+	return name, params, value, true
+}
+
+var vcardEncodings = map[string]bool{"QUOTED-PRINTABLE": true, "BASE64": true, "8BIT": true, "7BIT": true}
+
+// decodeVCardValue desfaz o ENCODING=QUOTED-PRINTABLE e o CHARSET do vCard
+// 2.1; os demais valores voltam como vieram.
+func decodeVCardValue(params map[string][]string, value string) (string, error) {
+	var enc, charset string
+	if e := params["ENCODING"]; len(e) > 0 {
+		enc = strings.ToUpper(e[0])
+	}
+	if c := params["CHARSET"]; len(c) > 0 {
+		charset = strings.ToUpper(c[0])
+	}
+	if enc != "QUOTED-PRINTABLE" && charset == "" {
+		return value, nil
+	}
+	b := []byte(value)
+	if enc == "QUOTED-PRINTABLE" {
+		var err error
+		if b, err = io.ReadAll(quotedprintable.NewReader(strings.NewReader(value))); err != nil {
+			return "", err
+		}
+	}
+	switch charset {
+	case "", "UTF-8", "US-ASCII":
+		return string(b), nil
+	case "ISO-8859-1", "LATIN1":
+		rs := make([]rune, len(b))
+		for i, c := range b {
+			rs[i] = rune(c) // Latin-1 coincide com os primeiros 256 code points
+		}
+		return string(rs), nil
+	}
+	return "", fmt.Errorf("unsupported charset %q", charset)
+}
+
+// cutUnquoted é strings.Cut ignorando separadores entre aspas (valores de
+// parâmetro podem conter ':').
+func cutUnquoted(s string, sep byte) (string, string, bool) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case sep:
+			if !quoted {
+				return s[:i], s[i+1:], true
+			}
+		}
+	}
+	return s, "", false
+}
+
+// VCardFromMessage extrai o cartão de uma mensagem de contato recebida, cujo
+// conteúdo traz o vCard gerado pelo WhatsApp.
+func VCardFromMessage(m ChatMessage) (*VCard, error) {
+	// a busca é no próprio Content: ToUpper pode mudar o tamanho em bytes de
+	// letras antes do cartão e deslocar o índice
+	loc := vcardBeginRe.FindStringIndex(m.Content)
+	if loc == nil {
+		return nil, fmt.Errorf("vcard: message %s has no vCard", m.ID)
+	}
+	return ParseVCard(m.Content[loc[0]:])
+}
+
+var vcardBeginRe = regexp.MustCompile(`(?i)BEGIN:VCARD`)
+
+// SendVCard envia o cartão completo como arquivo .vcf (o /send/contact só
+// aceita nome e um telefone; veja VCard.ContactParams).
+func (c *Client) SendVCard(ctx context.Context, phone string, card *VCard, opts ...SendOption) (*SendResponse, error) {
+	dir, err := os.MkdirTemp("", "gowa-vcard-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	// barras viram "_" antes do safeFilename, que ficaria só com o que vem
+	// depois delas; o começo do nome é o que identifica o contato
+	r := []rune(strings.NewReplacer("/", "_", `\`, "_").Replace(card.DisplayName()))
+	if len(r) > 60 {
+		r = r[:60]
+	}
+	name := safeFilename(string(r))
+	if name == "" {
+		name = "contact"
+	}
+	path := filepath.Join(dir, name+".vcf")
+	if err := os.WriteFile(path, []byte(card.String()), 0o600); err != nil {
+		return nil, err
+	}
+	return c.SendFile(ctx, SendFileParams{Phone: phone, FilePath: path}, opts...)
+}
@@ -0,0 +1,176 @@
+package gowa_test
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+func TestParseVCard(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want gowa.VCard
+	}{
+		{
+			"whatsapp 3.0",
+			"BEGIN:VCARD\r\nVERSION:3.0\r\nN:Silva;Ana;;;\r\nFN:Ana Silva\r\n" +
+				"item1.TEL;waid=5511999990000:+55 11 99999-0000\r\nitem1.X-ABLabel:Celular\r\nEND:VCARD\r\n",
+			gowa.VCard{
+				Version:       "3.0",
+				FormattedName: "Ana Silva",
+				Name:          gowa.VCardName{Family: "Silva", Given: "Ana"},
+				Phones:        []gowa.VCardPhone{{Number: "+55 11 99999-0000", WAID: "5511999990000"}},
+			},
+		},
+		{
+			"2.1 bare types and folding",
+			"BEGIN:VCARD\nVERSION:2.1\nFN:João\nTEL;CELL;VOICE:123\nNOTE:linha um\\nlinha\n  dois\\, fim\nEND:VCARD\n",
+			gowa.VCard{
+				Version:       "2.1",
+				FormattedName: "João",
+				Phones:        []gowa.VCardPhone{{Number: "123", Types: []string{"CELL", "VOICE"}}},
+				Note:          "linha um\nlinha dois, fim",
+			},
+		},
+		{
+			"2.1 quoted-printable",
+			"BEGIN:VCARD\nVERSION:2.1\nN;CHARSET=UTF-8;ENCODING=QUOTED-PRINTABLE:Concei=C3=A7=C3=A3o;Jo=C3=A3o;;;\n" +
+				"FN;CHARSET=ISO-8859-1;QUOTED-PRINTABLE:Jo=E3o Concei=E7=E3o\n" +
+				"TEL;CELL;ENCODING=QUOTED-PRINTABLE:=2B55 11 99999-0000\n" +
+				"NOTE;ENCODING=QUOTED-PRINTABLE:linha um=0D=0Alinha =\ndois\nORG:Acme;Vendas;\nEND:VCARD\n",
+			gowa.VCard{
+				Version:       "2.1",
+				FormattedName: "João Conceição",
+				Name:          gowa.VCardName{Family: "Conceição", Given: "João"},
+				Org:           "Acme",
+				OrgUnits:      []string{"Vendas"},
+				Phones:        []gowa.VCardPhone{{Number: "+55 11 99999-0000", Types: []string{"CELL"}}},
+				Note:          "linha um\r\nlinha dois",
+			},
+		},
+		{
+			"4.0",
+			"BEGIN:VCARD\nVERSION:4.0\nFN:Bia\nTEL;TYPE=\"cell,voice\":tel:+551133334444\n" +
+				"ORG:Acme\nEMAIL;TYPE=work:bia@x.io\nADR;TYPE=home:;;Rua A\\, 1;São Paulo;SP;01000-000;Brasil\nBDAY:1990-05-17\nEND:VCARD\n",
+			gowa.VCard{
+				Version:       "4.0",
+				FormattedName: "Bia",
+				Org:           "Acme",
+				Phones:        []gowa.VCardPhone{{Number: "+551133334444", Types: []string{"CELL", "VOICE"}}},
+				Emails:        []gowa.VCardEmail{{Address: "bia@x.io", Types: []string{"WORK"}}},
+				Addresses: []gowa.VCardAddress{{
+					Street: "Rua A, 1", Locality: "São Paulo", Region: "SP", PostalCode: "01000-000", Country: "Brasil",
+					Types: []string{"HOME"},
+				}},
+				Birthday: "1990-05-17",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := gowa.ParseVCard(tt.in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(*got, tt.want) {
+				t.Errorf("ParseVCard =\n%+v\nwant\n%+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseVCardErrors(t *testing.T) {
+	for _, in := range []string{"", "FN:x", "BEGIN:VCARD\nFN:x\n", "BEGIN:VCARD\nFN;CHARSET=KOI8-R:x\nEND:VCARD\n"} {
+		if _, err := gowa.ParseVCard(in); err == nil {
+			t.Errorf("ParseVCard(%q): expected error", in)
+		}
+	}
+}
+
+func TestVCardRoundTrip(t *testing.T) {
+	for _, version := range []string{"3.0", "4.0"} {
+		card := &gowa.VCard{
+			Version:  version,
+			Name:     gowa.VCardName{Family: "Souza; Lima", Given: "Carlos"},
+			Nickname: "Cadu",
+			Org:      "Acme; Ltda",
+			OrgUnits: []string{"Vendas", "Sul"},
+			Title:    "Gerente, vendas",
+			Emails:   []gowa.VCardEmail{{Address: "c@x.io"}},
+			URLs:     []string{"https://x.io"},
+			Note:     strings.Repeat("nota longa com acentuação ", 8),
+		}
+		card.AddPhone("+55 (11) 98888-7777", "CELL")
+		s := card.String()
+		for _, l := range strings.Split(strings.TrimSuffix(s, "\r\n"), "\r\n") {
+			if len(l) > 75 {
+				t.Errorf("%s: line longer than 75 bytes: %q", version, l)
+			}
+		}
+		if !strings.Contains(s, "\r\nORG:Acme\\; Ltda;Vendas;Sul\r\n") {
+			t.Errorf("%s: ORG components not kept:\n%s", version, s)
+		}
+		got, err := gowa.ParseVCard(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		card.FormattedName = card.DisplayName()
+		if version == "4.0" {
+			// TEL é uma URI no 4.0; o número volta normalizado
+			if !strings.Contains(s, "\r\nTEL;VALUE=uri;TYPE=cell;waid=5511988887777:tel:+5511988887777\r\n") {
+				t.Errorf("4.0 TEL is not a tel: URI:\n%s", s)
+			}
+			card.Phones[0].Number = "+5511988887777"
+		}
+		if !reflect.DeepEqual(got, card) {
+			t.Errorf("%s round trip =\n%+v\nwant\n%+v", version, got, card)
+		}
+	}
+}
+
+func TestVCardFromMessage(t *testing.T) {
+	card := "begin:vcard\nVERSION:3.0\nFN:Ana Silva\nTEL:123\nEND:VCARD\n"
+	tests := []struct{ name, content string }{
+		{"only card", card},
+		// ɐ tem 2 bytes e o maiúsculo Ɐ tem 3: um índice calculado sobre
+		// ToUpper(content) cairia no meio do cartão
+		{"prefix that grows in upper case", "ɐɐɐɐ contato: " + card},
+	}
+	for _, tt := range tests {
+		v, err := gowa.VCardFromMessage(gowa.ChatMessage{ID: "m1", Content: tt.content})
+		if err != nil || v.FormattedName != "Ana Silva" {
+			t.Errorf("%s: %+v, %v", tt.name, v, err)
+		}
+	}
+	if _, err := gowa.VCardFromMessage(gowa.ChatMessage{ID: "m1", Content: "oi"}); err == nil {
+		t.Error("message without vCard accepted")
+	}
+}
+
+func TestSendVCardFilename(t *testing.T) {
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	c := srv.GowaClient()
+	tests := []struct{ name, want string }{
+		{"Ana Silva", "Ana Silva.vcf"},
+		{"João/Maria", "João_Maria.vcf"},
+		{"a\x00b\nc\x1b", "a_b_c_.vcf"},
+		{"../..", "_.vcf"},
+		{" ..", "contact.vcf"},
+		{strings.Repeat("ç", 200), strings.Repeat("ç", 60) + ".vcf"},
+	}
+	for _, tt := range tests {
+		if _, err := c.SendVCard(context.Background(), "5511999990000", &gowa.VCard{FormattedName: tt.name}); err != nil {
+			t.Fatalf("%q: %v", tt.name, err)
+		}
+		req, _ := srv.LastRequest("/send/file")
+		if got := req.Files["file"].Filename; got != tt.want {
+			t.Errorf("SendVCard(%q) filename = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
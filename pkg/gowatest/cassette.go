@@ -0,0 +1,440 @@
+package gowatest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"gopkg.in/yaml.v3"
+)
+
+type CassetteMode int
+
+const (
+	// ModeReplay responde apenas a partir do cassette; requisições sem
+	// interação correspondente falham.
+	ModeReplay CassetteMode = iota
+	// ModeRecord envia tudo ao servidor real e regrava o cassette em Stop.
+	ModeRecord
+	// ModeAuto grava se o arquivo do cassette não existir; caso contrário, reproduz.
+	ModeAuto
+)
+
+// Interaction é um par requisição/resposta gravado.
+type Interaction struct {
+	Request  CassetteRequest  `json:"request" yaml:"request"`
+	Response CassetteResponse `json:"response" yaml:"response"`
+}
+
+// Em CassetteRequest e CassetteResponse, Body guarda o corpo como veio. No
+// arquivo, corpos que não são UTF-8 válido (mídia, QR em PNG, multipart com
+// arquivo binário) vão em BodyBase64, porque JSON e YAML trocariam os bytes
+// inválidos por U+FFFD; LoadCassette os devolve a Body.
+type CassetteRequest struct {
+	Method     string      `json:"method" yaml:"method"`
+	URL        string      `json:"url" yaml:"url"`
+	Header     http.Header `json:"header,omitempty" yaml:"header,omitempty"`
+	Body       string      `json:"body,omitempty" yaml:"body,omitempty"`
+	BodyBase64 string      `json:"body_base64,omitempty" yaml:"body_base64,omitempty"`
+}
+
+type CassetteResponse struct {
+	Status     int         `json:"status" yaml:"status"`
+	Header     http.Header `json:"header,omitempty" yaml:"header,omitempty"`
+	Body       string      `json:"body,omitempty" yaml:"body,omitempty"`
+	BodyBase64 string      `json:"body_base64,omitempty" yaml:"body_base64,omitempty"`
+}
+
+// encodeBody move para BodyBase64 o corpo que não é UTF-8 válido.
+func encodeBody(body, b64 *string) {
+	if !utf8.ValidString(*body) {
+		*body, *b64 = "", base64.StdEncoding.EncodeToString([]byte(*body))
+	}
+}
+
+// decodeBody faz o caminho inverso de encodeBody.
+func decodeBody(body, b64 *string) error {
+	if *b64 == "" {
+		return nil
+	}
+	b, err := base64.StdEncoding.DecodeString(*b64)
+	if err != nil {
+		return err
+	}
+	*body, *b64 = string(b), ""
+	return nil
+}
+
+type Cassette struct {
+	Interactions []Interaction `json:"interactions" yaml:"interactions"`
+}
+
+// Matcher decide se a requisição (já redigida) corresponde à interação gravada.
+type Matcher func(req CassetteRequest, rec Interaction) bool
+
+func MatchMethod(req CassetteRequest, rec Interaction) bool {
+	return req.Method == rec.Request.Method
+}
+
+func MatchPath(req CassetteRequest, rec Interaction) bool {
+	a, errA := url.Parse(req.URL)
+	b, errB := url.Parse(rec.Request.URL)
+	return errA == nil && errB == nil && a.Path == b.Path
+}
+
+func MatchQuery(req CassetteRequest, rec Interaction) bool {
+	a, errA := url.Parse(req.URL)
+	b, errB := url.Parse(rec.Request.URL)
+	return errA == nil && errB == nil && reflect.DeepEqual(a.Query(), b.Query())
+}
+
+// MatchBody compara os corpos normalizados: JSON ignora ordem das chaves e
+// espaços; multipart ignora o boundary e compara campos e arquivos.
+func MatchBody(req CassetteRequest, rec Interaction) bool {
+	return reflect.DeepEqual(normalizeBody(req), normalizeBody(rec.Request))
+}
+
+// MatchAll combina matchers; todos precisam casar.
+func MatchAll(ms ...Matcher) Matcher {
+	return func(req CassetteRequest, rec Interaction) bool {
+		for _, m := range ms {
+			if !m(req, rec) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// DefaultMatcher casa método, path, query e corpo normalizado.
+var DefaultMatcher = MatchAll(MatchMethod, MatchPath, MatchQuery, MatchBody)
+
+// RedactPhones substitui os números de telefone (os mesmos que os logs do
+// gowa mascaram, inclusive dentro de JIDs) por zeros do mesmo tamanho.
+func RedactPhones(s string) string {
+	return gowa.MaskPhones(s, func(d string) string { return strings.Repeat("0", len(d)) })
+}
+
+type RecorderConfig struct {
+	Path string // .yaml/.yml grava em YAML; qualquer outra extensão, JSON
+	Mode CassetteMode
+	// Transport é usado para falar com o servidor real ao gravar; default
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+	Matcher   Matcher // default DefaultMatcher
+	// Redact é aplicado a URLs e corpos de texto antes de gravar e antes de
+	// comparar; default RedactPhones. Em multipart, só os campos passam por
+	// ele (arquivos ficam intactos), e corpos binários não são alterados.
+	// Headers de credenciais são sempre removidos (gowa.RedactHeader).
+	Redact func(string) string
+}
+
+// Recorder é um http.RoundTripper que grava ou reproduz cassettes. Use-o via
+// gowa.Config{HTTPClient: rec.HTTPClient()}.
+type Recorder struct {
+	cfg    RecorderConfig
+	record bool
+
+	mu       sync.Mutex
+	cassette Cassette
+	used     []bool
+}
+
+func NewRecorder(cfg RecorderConfig) (*Recorder, error) {
+	if cfg.Path == "" {
+		return nil, errors.New("cassette path is required")
+	}
+	if cfg.Transport == nil {
+		cfg.Transport = http.DefaultTransport
+	}
+	if cfg.Matcher == nil {
+		cfg.Matcher = DefaultMatcher
+	}
+	if cfg.Redact == nil {
+		cfg.Redact = RedactPhones
+	}
+	r := &Recorder{cfg: cfg, record: cfg.Mode == ModeRecord}
+	if cfg.Mode == ModeAuto {
+		if _, err := os.Stat(cfg.Path); errors.Is(err, os.ErrNotExist) {
+			r.record = true
+		}
+	}
+	if !r.record {
+		c, err := LoadCassette(cfg.Path)
+		if err != nil {
+			return nil, err
+		}
+		r.cassette = *c
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// LoadCassette lê um cassette gravado pelo Recorder (YAML ou JSON, conforme a
+// extensão), com os corpos em BodyBase64 já decodificados em Body.
+func LoadCassette(path string) (*Cassette, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	if isYAML(path) {
+		err = yaml.Unmarshal(b, &c)
+	} else {
+		err = json.Unmarshal(b, &c)
+	}
+	for i := range c.Interactions {
+		in := &c.Interactions[i]
+		if err == nil {
+			err = decodeBody(&in.Request.Body, &in.Request.BodyBase64)
+		}
+		if err == nil {
+			err = decodeBody(&in.Response.Body, &in.Response.BodyBase64)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cassette %s: %w", path, err)
+	}
+	return &c, nil
+}
+
+func isYAML(p string) bool {
+	ext := strings.ToLower(filepath.Ext(p))
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// HTTPClient retorna um http.Client que usa o Recorder como transporte.
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// Recording informa se o Recorder está gravando (e não reproduzindo).
+func (r *Recorder) Recording() bool {
+	return r.record
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		if body, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	creq := CassetteRequest{
+		Method: req.Method,
+		URL:    r.cfg.Redact(redactURL(req.URL)),
+		Header: gowa.RedactHeader(req.Header),
+		Body:   r.redactBody(req.Header, body),
+	}
+	if r.record {
+		return r.recordTrip(req, creq)
+	}
+	return r.replay(req, creq)
+}
+
+func (r *Recorder) recordTrip(req *http.Request, creq CassetteRequest) (*http.Response, error) {
+	resp, err := r.cfg.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(b))
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Request:  creq,
+		Response: CassetteResponse{Status: resp.StatusCode, Header: gowa.RedactHeader(resp.Header), Body: r.redactBody(resp.Header, b)},
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// replay entrega a primeira interação ainda não usada que casar com creq.
+func (r *Recorder) replay(req *http.Request, creq CassetteRequest) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || !r.cfg.Matcher(creq, in) {
+			continue
+		}
+		r.used[i] = true
+		h := in.Response.Header.Clone()
+		if h == nil {
+			h = http.Header{}
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+			StatusCode:    in.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        h,
+			Body:          io.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("cassette: no interaction matches %s %s", creq.Method, creq.URL)
+}
+
+// Unused retorna as interações do cassette que não foram reproduzidas; útil
+// para garantir que o teste fez todas as chamadas esperadas.
+func (r *Recorder) Unused() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Interaction
+	for i, u := range r.used {
+		if !u {
+			out = append(out, r.cassette.Interactions[i])
+		}
+	}
+	return out
+}
+
+// Stop grava o cassette em disco quando em modo de gravação.
+func (r *Recorder) Stop() error {
+	if !r.record {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// cópia: as interações em memória continuam com o corpo original
+	out := Cassette{Interactions: slices.Clone(r.cassette.Interactions)}
+	for i := range out.Interactions {
+		in := &out.Interactions[i]
+		encodeBody(&in.Request.Body, &in.Request.BodyBase64)
+		encodeBody(&in.Response.Body, &in.Response.BodyBase64)
+	}
+	var (
+		b   []byte
+		err error
+	)
+	if isYAML(r.cfg.Path) {
+		b, err = yaml.Marshal(out)
+	} else {
+		b, err = json.MarshalIndent(out, "", "  ")
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.cfg.Path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.cfg.Path, b, 0o644)
+}
+
+func redactURL(u *url.URL) string {
+	cp := *u
+	if cp.User != nil {
+		cp.User = url.User("REDACTED")
+	}
+	return cp.String()
+}
+
+// redactBody aplica Redact só ao que é texto. Trocar dígitos dentro de uma
+// imagem ou de outro conteúdo binário corromperia o arquivo gravado.
+func (r *Recorder) redactBody(h http.Header, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mt, params, _ := mime.ParseMediaType(h.Get("Content-Type"))
+	if mt == "multipart/form-data" {
+		if s, ok := r.redactMultipart(body, params["boundary"]); ok {
+			return s
+		}
+	}
+	if !utf8.Valid(body) {
+		return string(body) // Stop grava em BodyBase64
+	}
+	return r.cfg.Redact(string(body))
+}
+
+// redactMultipart remonta o corpo com o mesmo boundary, redigindo os campos
+// de texto e mantendo os arquivos como vieram.
+func (r *Recorder) redactMultipart(body []byte, boundary string) (string, bool) {
+	var out bytes.Buffer
+	w := multipart.NewWriter(&out)
+	if w.SetBoundary(boundary) != nil {
+		return "", false
+	}
+	mr := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", false
+		}
+		b, err := io.ReadAll(p)
+		if err != nil {
+			return "", false
+		}
+		if p.FileName() == "" && utf8.Valid(b) {
+			b = []byte(r.cfg.Redact(string(b)))
+		}
+		pw, err := w.CreatePart(p.Header)
+		if err != nil {
+			return "", false
+		}
+		pw.Write(b)
+	}
+	if w.Close() != nil {
+		return "", false
+	}
+	return out.String(), true
+}
+
+// normalizeBody devolve uma representação comparável do corpo.
+func normalizeBody(req CassetteRequest) any {
+	if req.Body == "" {
+		return nil
+	}
+	mt, params, _ := mime.ParseMediaType(req.Header.Get("Content-Type"))
+	if mt == "multipart/form-data" {
+		parts := map[string]string{}
+		mr := multipart.NewReader(strings.NewReader(req.Body), params["boundary"])
+		for {
+			p, err := mr.NextPart()
+			if err != nil {
+				break
+			}
+			b, _ := io.ReadAll(p)
+			if p.FileName() != "" {
+				sum := sha256.Sum256(b)
+				parts[p.FormName()] = p.FileName() + ":" + hex.EncodeToString(sum[:])
+			} else {
+				parts[p.FormName()] = string(b)
+			}
+		}
+		return parts
+	}
+	var v any
+	if json.Unmarshal([]byte(req.Body), &v) == nil {
+		return v
+	}
+	return req.Body
+}
@@ -0,0 +1,105 @@
+package gowatest_test
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+func TestRecorderRedaction(t *testing.T) {
+	for _, name := range []string{"cassette.yaml", "cassette.json"} {
+		t.Run(name, func(t *testing.T) { testRecorderRedaction(t, name) })
+	}
+}
+
+func testRecorderRedaction(t *testing.T, name string) {
+	ctx := context.Background()
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	dir := t.TempDir()
+	img := filepath.Join(dir, "foto.jpg")
+	// conteúdo binário com uma sequência que parece telefone
+	data := append([]byte{0xff, 0xd8, 0xff, 0x00}, []byte("5511999990000\x80\x81")...)
+	if err := os.WriteFile(img, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	media := srv.AddMediaMessage(gowa.ChatMessage{ChatJID: "5511988887777@s.whatsapp.net"}, "image", "qr.png", data)
+	path := filepath.Join(dir, name)
+	client := func(rec *gowatest.Recorder) *gowa.Client {
+		c, err := gowa.New(gowa.Config{BaseURL: srv.URL, Username: "admin", Password: "admin", HTTPClient: rec.HTTPClient(), RetryMax: -1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	calls := func(c *gowa.Client) {
+		if _, err := c.SendMessage(ctx, "5511988887777", "oi"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := c.SendImageFile(ctx, "5511988887777", "legenda", img, false, false); err != nil {
+			t.Fatal(err)
+		}
+		// resposta binária
+		f, err := c.DownloadMedia(ctx, media, t.TempDir())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, err := os.ReadFile(f.Path); err != nil || !bytes.Equal(got, data) {
+			t.Errorf("downloaded media = %q, %v; want %q", got, err, data)
+		}
+	}
+
+	rec, err := gowatest.NewRecorder(gowatest.RecorderConfig{Path: path, Mode: gowatest.ModeRecord})
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls(client(rec))
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(raw, []byte("\uFFFD")) || bytes.Contains(raw, []byte("\ufffd")) || !utf8.Valid(raw) {
+		t.Error("binary bytes written to the cassette as text")
+	}
+	cas, err := gowatest.LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cas.Interactions) != 3 {
+		t.Fatalf("%d interactions recorded, want 3", len(cas.Interactions))
+	}
+	for _, in := range cas.Interactions[:2] {
+		req := in.Request
+		if strings.Contains(req.URL+req.Body, "5511988887777") || req.Header.Get("Authorization") != "REDACTED" {
+			t.Errorf("%s: phone or credentials not redacted", req.URL)
+		}
+		if !strings.Contains(req.Body, "0000000000000") {
+			t.Errorf("%s: phone not replaced by zeros", req.URL)
+		}
+	}
+	if !strings.Contains(cas.Interactions[1].Request.Body, string(data)) {
+		t.Error("binary file part was altered by redaction")
+	}
+	if got := cas.Interactions[2].Response.Body; got != string(data) {
+		t.Errorf("binary response = %q, want %q", got, data)
+	}
+
+	rec, err = gowatest.NewRecorder(gowatest.RecorderConfig{Path: path, Mode: gowatest.ModeReplay})
+	if err != nil {
+		t.Fatal(err)
+	}
+	calls(client(rec))
+	if u := rec.Unused(); len(u) != 0 {
+		t.Errorf("%d interactions not replayed", len(u))
+	}
+}
@@ -0,0 +1,81 @@
+package gowatest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+)
+
+var _ gowa.Clock = (*Clock)(nil)
+
+// Clock é um relógio manual que implementa gowa.Clock: o tempo só anda com
+// Advance ou Set, e os canais de After disparam quando o prazo é alcançado.
+type Clock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []clockWaiter
+}
+
+type clockWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+// NewClock cria um relógio parado em t.
+func NewClock(t time.Time) *Clock { return &Clock{now: t} }
+
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, clockWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+// Advance avança o relógio em d.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	t := c.now.Add(d)
+	c.mu.Unlock()
+	c.Set(t)
+}
+
+// Set move o relógio para t (nunca para trás) e dispara os After vencidos,
+// em ordem.
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if t.After(c.now) {
+		c.now = t
+	}
+	sort.Slice(c.waiters, func(a, b int) bool { return c.waiters[a].at.Before(c.waiters[b].at) })
+	n := 0
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			break
+		}
+		w.ch <- c.now
+		n++
+	}
+	c.waiters = c.waiters[n:]
+}
+
+// Waiters informa quantos After ainda aguardam; útil para sincronizar o teste
+// com a goroutine que espera o relógio.
+func (c *Clock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
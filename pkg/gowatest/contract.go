@@ -0,0 +1,499 @@
+package gowatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+)
+
+// Spec é o subconjunto do OpenAPI (doc/openapi.yaml) usado na verificação de
+// contrato: paths, parâmetros, corpos de requisição e schemas de resposta.
+type Spec struct {
+	Paths      map[string]map[string]*specOperation `yaml:"paths"`
+	Security   []map[string][]string                `yaml:"security"`
+	Components struct {
+		Schemas map[string]*specSchema `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+type specOperation struct {
+	OperationID string      `yaml:"operationId"`
+	Parameters  []specParam `yaml:"parameters"`
+	RequestBody *struct {
+		Required bool                     `yaml:"required"`
+		Content  map[string]specMediaType `yaml:"content"`
+	} `yaml:"requestBody"`
+	Responses map[string]struct {
+		Content map[string]specMediaType `yaml:"content"`
+	} `yaml:"responses"`
+}
+
+type specParam struct {
+	Name     string      `yaml:"name"`
+	In       string      `yaml:"in"`
+	Required bool        `yaml:"required"`
+	Schema   *specSchema `yaml:"schema"`
+}
+
+type specMediaType struct {
+	Schema *specSchema `yaml:"schema"`
+}
+
+type specSchema struct {
+	Ref        string                 `yaml:"$ref"`
+	Type       string                 `yaml:"type"`
+	Format     string                 `yaml:"format"`
+	Properties map[string]*specSchema `yaml:"properties"`
+	Items      *specSchema            `yaml:"items"`
+	Required   []string               `yaml:"required"`
+	Enum       []any                  `yaml:"enum"`
+	Nullable   bool                   `yaml:"nullable"`
+	Example    any                    `yaml:"example"`
+}
+
+func LoadSpec(path string) (*Spec, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseSpec(b)
+}
+
+func ParseSpec(b []byte) (*Spec, error) {
+	var s Spec
+	if err := yaml.Unmarshal(b, &s); err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	if len(s.Paths) == 0 {
+		return nil, fmt.Errorf("parse spec: no paths")
+	}
+	return &s, nil
+}
+
+// resolve segue $ref locais (#/components/schemas/Nome).
+func (s *Spec) resolve(sc *specSchema) *specSchema {
+	for i := 0; sc != nil && sc.Ref != "" && i < 16; i++ {
+		sc = s.Components.Schemas[strings.TrimPrefix(sc.Ref, "#/components/schemas/")]
+	}
+	return sc
+}
+
+// match encontra a operação do spec para method e path concreto, preenchendo
+// os parâmetros de path ({message_id}, {chat_jid}).
+func (s *Spec) match(method, path string) (string, *specOperation, map[string]string) {
+	segs := strings.Split(strings.Trim(path, "/"), "/")
+	tmpls := make([]string, 0, len(s.Paths))
+	for t := range s.Paths {
+		tmpls = append(tmpls, t)
+	}
+	sort.Strings(tmpls)
+	for _, t := range tmpls {
+		op := s.Paths[t][strings.ToLower(method)]
+		if op == nil {
+			continue
+		}
+		tsegs := strings.Split(strings.Trim(t, "/"), "/")
+		if len(tsegs) != len(segs) {
+			continue
+		}
+		params := map[string]string{}
+		ok := true
+		for i, ts := range tsegs {
+			if strings.HasPrefix(ts, "{") && strings.HasSuffix(ts, "}") {
+				params[ts[1:len(ts)-1]] = segs[i]
+			} else if ts != segs[i] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return t, op, params
+		}
+	}
+	return "", nil, nil
+}
+
+// Violation é uma divergência entre o client e o spec.
+type Violation struct {
+	Case      string // método do Client exercitado
+	Operation string // "POST /send/message"
+	Detail    string
+}
+
+func (v Violation) String() string {
+	if v.Operation == "" {
+		return v.Case + ": " + v.Detail
+	}
+	return fmt.Sprintf("%s (%s): %s", v.Case, v.Operation, v.Detail)
+}
+
+// ContractServer é um stub que valida cada requisição contra o spec (path,
+// parâmetros, content type e corpo) e responde com uma amostra gerada a partir
+// do schema de resposta 200 da operação.
+type ContractServer struct {
+	*httptest.Server
+	spec *Spec
+
+	mu         sync.Mutex
+	caseName   string
+	violations []Violation
+	lastSample any
+}
+
+func NewContractServer(spec *Spec) *ContractServer {
+	cs := &ContractServer{spec: spec}
+	cs.Server = httptest.NewServer(http.HandlerFunc(cs.serve))
+	return cs
+}
+
+// GowaClient retorna um gowa.Client apontado para o stub, sem retries
+// (como Server.GowaClient, não esconde o Client() do httptest.Server).
+func (cs *ContractServer) GowaClient() *gowa.Client {
+	c, err := gowa.New(gowa.Config{
+		BaseURL:    cs.URL,
+		Username:   "admin",
+		Password:   "admin",
+		HTTPClient: cs.Server.Client(),
+		RetryMax:   -1,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Violations retorna as divergências registradas até agora.
+func (cs *ContractServer) Violations() []Violation {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return append([]Violation(nil), cs.violations...)
+}
+
+func (cs *ContractServer) begin(name string) {
+	cs.mu.Lock()
+	cs.caseName, cs.lastSample = name, nil
+	cs.mu.Unlock()
+}
+
+func (cs *ContractServer) sample() any {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+	return cs.lastSample
+}
+
+func (cs *ContractServer) report(op, format string, args ...any) {
+	cs.mu.Lock()
+	cs.violations = append(cs.violations, Violation{Case: cs.caseName, Operation: op, Detail: fmt.Sprintf(format, args...)})
+	cs.mu.Unlock()
+}
+
+func (cs *ContractServer) serve(w http.ResponseWriter, r *http.Request) {
+	tmpl, op, pathParams := cs.spec.match(r.Method, r.URL.Path)
+	if op == nil {
+		cs.report(r.Method+" "+r.URL.Path, "operation not in spec")
+		writeJSON(w, http.StatusNotFound, map[string]any{"code": "404", "message": "not in spec"})
+		return
+	}
+	name := r.Method + " " + tmpl
+	if len(cs.spec.Security) > 0 {
+		if _, _, ok := r.BasicAuth(); !ok {
+			cs.report(name, "missing basic auth")
+		}
+	}
+	for _, p := range op.Parameters {
+		if p.In == "path" && pathParams[p.Name] == "" {
+			cs.report(name, "empty path parameter %q", p.Name)
+		}
+	}
+	cs.checkQuery(name, op, r)
+	cs.checkBody(name, op, r)
+
+	resp, ok := op.Responses["200"]
+	if !ok || resp.Content["application/json"].Schema == nil {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	s := cs.spec.sample(resp.Content["application/json"].Schema, 0)
+	cs.mu.Lock()
+	cs.lastSample = s
+	cs.mu.Unlock()
+	writeJSON(w, http.StatusOK, s)
+}
+
+func (cs *ContractServer) checkQuery(name string, op *specOperation, r *http.Request) {
+	declared := map[string]*specParam{}
+	for i, p := range op.Parameters {
+		if p.In == "query" {
+			declared[p.Name] = &op.Parameters[i]
+		}
+	}
+	q := r.URL.Query()
+	for k, vs := range q {
+		p := declared[k]
+		if p == nil {
+			cs.report(name, "undeclared query parameter %q", k)
+			continue
+		}
+		for _, v := range vs {
+			for _, e := range cs.spec.validateString(p.Schema, v, "query."+k) {
+				cs.report(name, "%s", e)
+			}
+		}
+	}
+	for k, p := range declared {
+		if p.Required && q.Get(k) == "" {
+			cs.report(name, "missing required query parameter %q", k)
+		}
+	}
+}
+
+func (cs *ContractServer) checkBody(name string, op *specOperation, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if op.RequestBody == nil {
+		if len(body) > 0 {
+			cs.report(name, "body sent to operation without requestBody")
+		}
+		return
+	}
+	if len(body) == 0 {
+		if op.RequestBody.Required {
+			cs.report(name, "missing required body")
+		}
+		return
+	}
+	mt, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		cs.report(name, "invalid content type %q", r.Header.Get("Content-Type"))
+		return
+	}
+	media, ok := op.RequestBody.Content[mt]
+	if !ok {
+		accepted := make([]string, 0, len(op.RequestBody.Content))
+		for k := range op.RequestBody.Content {
+			accepted = append(accepted, k)
+		}
+		sort.Strings(accepted)
+		cs.report(name, "content type %s not accepted (spec: %s)", mt, strings.Join(accepted, ", "))
+		return
+	}
+	schema := cs.spec.resolve(media.Schema)
+	switch mt {
+	case "application/json":
+		var v any
+		if err := json.Unmarshal(body, &v); err != nil {
+			cs.report(name, "invalid json body: %v", err)
+			return
+		}
+		for _, e := range cs.spec.validate(schema, v, "body") {
+			cs.report(name, "%s", e)
+		}
+	case "multipart/form-data":
+		form, err := multipart.NewReader(strings.NewReader(string(body)), params["boundary"]).ReadForm(32 << 20)
+		if err != nil {
+			cs.report(name, "invalid multipart body: %v", err)
+			return
+		}
+		for _, e := range cs.spec.validateForm(schema, form) {
+			cs.report(name, "%s", e)
+		}
+	}
+}
+
+// validate confere um valor JSON decodificado contra o schema. Propriedades
+// não declaradas contam como divergência: é assim que o drift aparece.
+func (s *Spec) validate(sc *specSchema, v any, at string) []string {
+	sc = s.resolve(sc)
+	if sc == nil {
+		return nil
+	}
+	if v == nil {
+		if sc.Nullable || sc.Type == "" {
+			return nil
+		}
+		return []string{at + ": null not allowed"}
+	}
+	var errs []string
+	switch sc.Type {
+	case "object":
+		m, ok := v.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected object, got %T", at, v)}
+		}
+		for _, k := range sortedKeys(m) {
+			p, ok := sc.Properties[k]
+			if !ok {
+				if len(sc.Properties) > 0 {
+					errs = append(errs, fmt.Sprintf("%s.%s: property not in spec", at, k))
+				}
+				continue
+			}
+			errs = append(errs, s.validate(p, m[k], at+"."+k)...)
+		}
+		for _, k := range sc.Required {
+			if _, ok := m[k]; !ok {
+				errs = append(errs, fmt.Sprintf("%s.%s: required property missing", at, k))
+			}
+		}
+	case "array":
+		a, ok := v.([]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected array, got %T", at, v)}
+		}
+		for i, it := range a {
+			errs = append(errs, s.validate(sc.Items, it, fmt.Sprintf("%s[%d]", at, i))...)
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return []string{fmt.Sprintf("%s: expected string, got %T", at, v)}
+		}
+		errs = append(errs, checkEnum(sc, str, at)...)
+	case "integer":
+		f, ok := v.(float64)
+		if !ok || f != float64(int64(f)) {
+			return []string{fmt.Sprintf("%s: expected integer, got %v", at, v)}
+		}
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return []string{fmt.Sprintf("%s: expected number, got %T", at, v)}
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return []string{fmt.Sprintf("%s: expected boolean, got %T", at, v)}
+		}
+	}
+	return errs
+}
+
+// validateString confere valores textuais (query e campos multipart).
+func (s *Spec) validateString(sc *specSchema, v, at string) []string {
+	sc = s.resolve(sc)
+	if sc == nil {
+		return nil
+	}
+	var err error
+	switch sc.Type {
+	case "integer":
+		_, err = strconv.ParseInt(v, 10, 64)
+	case "number":
+		_, err = strconv.ParseFloat(v, 64)
+	case "boolean":
+		_, err = strconv.ParseBool(v)
+	case "string":
+		if sc.Format == "binary" {
+			return []string{at + ": expected file part, got field"}
+		}
+		return checkEnum(sc, v, at)
+	}
+	if err != nil {
+		return []string{fmt.Sprintf("%s: %q is not a valid %s", at, v, sc.Type)}
+	}
+	return nil
+}
+
+func (s *Spec) validateForm(sc *specSchema, form *multipart.Form) []string {
+	var errs []string
+	for _, k := range sortedKeys(form.Value) {
+		p, ok := sc.Properties[k]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("form.%s: field not in spec", k))
+			continue
+		}
+		for _, v := range form.Value[k] {
+			errs = append(errs, s.validateString(p, v, "form."+k)...)
+		}
+	}
+	for _, k := range sortedKeys(form.File) {
+		p := s.resolve(sc.Properties[k])
+		if p == nil || p.Format != "binary" {
+			errs = append(errs, fmt.Sprintf("form.%s: file part not in spec", k))
+		}
+	}
+	for _, k := range sc.Required {
+		if len(form.Value[k]) == 0 && len(form.File[k]) == 0 {
+			errs = append(errs, fmt.Sprintf("form.%s: required field missing", k))
+		}
+	}
+	return errs
+}
+
+func checkEnum(sc *specSchema, v, at string) []string {
+	if len(sc.Enum) == 0 {
+		return nil
+	}
+	for _, e := range sc.Enum {
+		if fmt.Sprint(e) == v {
+			return nil
+		}
+	}
+	return []string{fmt.Sprintf("%s: %q not in enum %v", at, v, sc.Enum)}
+}
+
+// sample gera uma resposta de exemplo a partir do schema, usando os examples
+// do spec quando compatíveis com o tipo declarado.
+func (s *Spec) sample(sc *specSchema, depth int) any {
+	sc = s.resolve(sc)
+	if sc == nil || depth > 8 {
+		return nil
+	}
+	switch sc.Type {
+	case "object":
+		if len(sc.Properties) == 0 {
+			return sc.Example
+		}
+		m := map[string]any{}
+		for k, p := range sc.Properties {
+			m[k] = s.sample(p, depth+1)
+		}
+		return m
+	case "array":
+		return []any{s.sample(sc.Items, depth+1)}
+	case "string":
+		if sc.Example != nil {
+			return fmt.Sprint(sc.Example)
+		}
+		if sc.Format == "date-time" {
+			return "2024-01-15T10:30:00Z"
+		}
+		return "string"
+	case "integer":
+		if n, ok := sc.Example.(int); ok {
+			return n
+		}
+		return 1
+	case "number":
+		switch n := sc.Example.(type) {
+		case int, float64:
+			return n
+		}
+		return 1.5
+	case "boolean":
+		if b, ok := sc.Example.(bool); ok {
+			return b
+		}
+		return true
+	}
+	return sc.Example
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
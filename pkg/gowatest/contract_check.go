@@ -0,0 +1,189 @@
+package gowatest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+)
+
+// contractCase exercita um método do Client com argumentos válidos. file é um
+// arquivo local para os envios multipart.
+type contractCase struct {
+	Method string
+	Call   func(ctx context.Context, c *gowa.Client, file string) (any, error)
+}
+
+const contractJID = "6289685028129@s.whatsapp.net"
+
+var contractCases = []contractCase{
+	{"Login", func(ctx context.Context, c *gowa.Client, _ string) (any, error) { return c.Login(ctx) }},
+	{"LoginWithCode", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.LoginWithCode(ctx, "628912344551")
+	}},
+	{"Logout", func(ctx context.Context, c *gowa.Client, _ string) (any, error) { return c.Logout(ctx) }},
+	{"Reconnect", func(ctx context.Context, c *gowa.Client, _ string) (any, error) { return c.Reconnect(ctx) }},
+	{"LeaveGroup", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.LeaveGroup(ctx, "120363025246125486@g.us")
+	}},
+	{"Devices", func(ctx context.Context, c *gowa.Client, _ string) (any, error) { return c.Devices(ctx) }},
+	{"UserInfo", func(ctx context.Context, c *gowa.Client, _ string) (any, error) { return c.UserInfo(ctx, contractJID) }},
+	{"SendPresence", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.SendPresence(ctx, "available", gowa.WithForwarded(false))
+	}},
+	{"ListChats", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		hasMedia := true
+		return c.ListChats(ctx, gowa.ListChatsParams{Limit: 10, Offset: 5, Search: "john", HasMedia: &hasMedia})
+	}},
+	{"GetChatMessages", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		yes, no := true, false
+		return c.GetChatMessages(ctx, contractJID, gowa.GetChatMessagesParams{
+			Limit: 10, Offset: 5, StartTime: "2024-01-01T00:00:00Z", EndTime: "2024-02-01T00:00:00Z",
+			MediaOnly: &yes, IsFromMe: &no, Search: "hello",
+		})
+	}},
+	{"SendMessage", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.SendMessage(ctx, contractJID, "hi", gowa.WithReplyMessageID("3EB0"), gowa.WithForwarded(true), gowa.WithDisappearingDuration(3600))
+	}},
+	{"SendTextMessage", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.SendTextMessage(ctx, gowa.SendTextParams{Phone: contractJID, Message: "hi", ReplyMessageID: "3EB0", IsForwarded: true, Duration: 3600})
+	}},
+	{"SendImageFile", func(ctx context.Context, c *gowa.Client, file string) (any, error) {
+		return c.SendImageFile(ctx, contractJID, "caption", file, true, true, gowa.WithDisappearingDuration(3600), gowa.WithForwarded(true))
+	}},
+	{"SendImageURL", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.SendImageURL(ctx, contractJID, "caption", "https://example.com/image.jpg", false, true, gowa.WithDisappearingDuration(3600))
+	}},
+	{"SendAudio", func(ctx context.Context, c *gowa.Client, file string) (any, error) {
+		return c.SendAudio(ctx, gowa.SendAudioParams{Phone: contractJID, AudioPath: file, IsForwarded: true, Duration: 3600})
+	}},
+	{"SendAudio", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.SendAudio(ctx, gowa.SendAudioParams{Phone: contractJID, AudioURL: "https://example.com/audio.ogg", Duration: 3600})
+	}},
+	{"SendFile", func(ctx context.Context, c *gowa.Client, file string) (any, error) {
+		return c.SendFile(ctx, gowa.SendFileParams{Phone: contractJID, Caption: "doc", FilePath: file, Duration: 3600})
+	}},
+	{"SendVideo", func(ctx context.Context, c *gowa.Client, file string) (any, error) {
+		return c.SendVideo(ctx, gowa.SendVideoParams{Phone: contractJID, Caption: "video", VideoPath: file, ViewOnce: true, Duration: 3600})
+	}},
+	{"SendVideo", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.SendVideo(ctx, gowa.SendVideoParams{Phone: contractJID, VideoURL: "https://example.com/video.mp4"}, gowa.WithCompress(true), gowa.WithViewOnce(false))
+	}},
+	{"SendContact", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.SendContact(ctx, gowa.SendContactParams{Phone: contractJID, ContactName: "Ana", ContactPhone: "628900000000", Duration: 3600})
+	}},
+	{"SendLink", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.SendLink(ctx, gowa.SendLinkParams{Phone: contractJID, Link: "https://example.com", Caption: "veja", Duration: 3600})
+	}},
+	{"SendLocation", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.SendLocation(ctx, gowa.SendLocationParams{Phone: contractJID, Latitude: "-7.79", Longitude: "110.36", Duration: 3600})
+	}},
+	{"SendPoll", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.SendPoll(ctx, gowa.SendPollParams{Phone: contractJID, Question: "?", Options: []string{"a", "b"}, MaxAnswer: 1, Duration: 3600})
+	}},
+	{"SendChatPresence", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.SendChatPresence(ctx, gowa.SendChatPresenceParams{Phone: contractJID, Action: "start"})
+	}},
+	{"RevokeMessage", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.RevokeMessage(ctx, gowa.MessageActionParams{MessageID: "3EB0", Phone: contractJID})
+	}},
+	{"DeleteMessage", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.DeleteMessage(ctx, gowa.MessageActionParams{MessageID: "3EB0", Phone: contractJID})
+	}},
+	{"ReactMessage", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.ReactMessage(ctx, gowa.MessageActionParams{MessageID: "3EB0", Phone: contractJID, Emoji: "🙏"})
+	}},
+	{"UpdateMessage", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.UpdateMessage(ctx, gowa.MessageActionParams{MessageID: "3EB0", Phone: contractJID, Message: "editada"})
+	}},
+	{"ReadMessage", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.ReadMessage(ctx, gowa.MessageActionParams{MessageID: "3EB0", Phone: contractJID})
+	}},
+	{"StarMessage", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.StarMessage(ctx, gowa.MessageActionParams{MessageID: "3EB0", Phone: contractJID})
+	}},
+	{"UnstarMessage", func(ctx context.Context, c *gowa.Client, _ string) (any, error) {
+		return c.UnstarMessage(ctx, gowa.MessageActionParams{MessageID: "3EB0", Phone: contractJID})
+	}},
+}
+
+// CheckClient exercita os métodos do Client contra um ContractServer e
+// retorna as divergências encontradas: requisições fora do spec e respostas de
+// exemplo que não decodificam nos structs ou que perdem campos. O erro só é
+// retornado para falhas do próprio harness. Que todo método que chama a API
+// tenha um caso é verificado por TestContractCoverage, a partir do código.
+func CheckClient(ctx context.Context, spec *Spec) ([]Violation, error) {
+	dir, err := os.MkdirTemp("", "gowa-contract-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+	file := filepath.Join(dir, "sample.bin")
+	if err := os.WriteFile(file, []byte("gowa contract sample"), 0o644); err != nil {
+		return nil, err
+	}
+
+	cs := NewContractServer(spec)
+	defer cs.Close()
+	c := cs.GowaClient()
+
+	var out []Violation
+	for _, tc := range contractCases {
+		cs.begin(tc.Method)
+		res, err := tc.Call(ctx, c, file)
+		if err != nil {
+			out = append(out, Violation{Case: tc.Method, Detail: "call failed: " + err.Error()})
+			continue
+		}
+		if res == nil || reflect.ValueOf(res).IsNil() {
+			continue
+		}
+		for _, m := range lostFields(cs.sample(), res) {
+			out = append(out, Violation{Case: tc.Method, Detail: fmt.Sprintf("%s: field not decoded into %T", m, res)})
+		}
+	}
+	out = append(cs.Violations(), out...)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Case < out[j].Case })
+	return out, nil
+}
+
+// lostFields compara a amostra enviada pelo stub com o struct decodificado
+// (serializado de volta) e lista os campos que o struct descartou.
+func lostFields(sample, decoded any) []string {
+	b, err := json.Marshal(decoded)
+	if err != nil {
+		return []string{"marshal: " + err.Error()}
+	}
+	var got any
+	if err := json.Unmarshal(b, &got); err != nil {
+		return []string{"unmarshal: " + err.Error()}
+	}
+	var lost []string
+	var walk func(s, g any, at string)
+	walk = func(s, g any, at string) {
+		switch sv := s.(type) {
+		case map[string]any:
+			gm, _ := g.(map[string]any)
+			for _, k := range sortedKeys(sv) {
+				gv, ok := gm[k]
+				if !ok {
+					lost = append(lost, at+"."+k)
+					continue
+				}
+				walk(sv[k], gv, at+"."+k)
+			}
+		case []any:
+			ga, _ := g.([]any)
+			if len(sv) > 0 && len(ga) > 0 {
+				walk(sv[0], ga[0], at+"[0]")
+			}
+		}
+	}
+	walk(sample, got, "response")
+	return lost
+}
@@ -0,0 +1,141 @@
+package gowatest
+
+import (
+	"context"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestContract(t *testing.T) {
+	spec, err := LoadSpec(filepath.Join("..", "..", "doc", "openapi.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	violations, err := CheckClient(context.Background(), spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range violations {
+		t.Errorf("drift: %s", v)
+	}
+}
+
+// TestContractCoverage exige um caso de contrato para cada método do Client
+// que chama a API diretamente (pela camada Raw ou pelos helpers de JSON e
+// multipart). Métodos que só combinam outros métodos públicos, como
+// SearchMessages ou SendLongMessage, ficam cobertos pelos casos deles; os
+// downloads buscam arquivos estáticos, fora do spec.
+func TestContractCoverage(t *testing.T) {
+	direct, all := clientAPIMethods(t, filepath.Join("..", "gowa"))
+	covered := map[string]bool{}
+	for _, tc := range contractCases {
+		if !all[tc.Method] {
+			t.Errorf("contract case %s: no such Client method", tc.Method)
+		}
+		covered[tc.Method] = true
+	}
+	if len(direct) == 0 {
+		t.Fatal("no Client method calls the API; the source analysis is broken")
+	}
+	for _, m := range direct {
+		if !covered[m] {
+			t.Errorf("%s calls the API directly but has no contract case", m)
+		}
+	}
+}
+
+// apiHelpers são os métodos do Client que fazem uma chamada de operação do
+// spec.
+var apiHelpers = map[string]bool{"getJSON": true, "postJSON": true, "postFormFile": true, "send": true}
+
+// clientAPIMethods analisa o código do pacote gowa e retorna os métodos
+// exportados de *Client que chamam a API diretamente, além de todos os
+// métodos exportados. Chamadas a métodos não exportados são seguidas.
+func clientAPIMethods(t *testing.T, dir string) (direct []string, all map[string]bool) {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	methods := map[string]*ast.FuncDecl{}
+	for _, name := range files {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, name, nil, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, d := range f.Decls {
+			fn, ok := d.(*ast.FuncDecl)
+			if !ok || fn.Recv == nil || fn.Body == nil || len(fn.Recv.List) != 1 {
+				continue
+			}
+			if star, ok := fn.Recv.List[0].Type.(*ast.StarExpr); !ok || !isIdent(star.X, "Client") {
+				continue
+			}
+			methods[fn.Name.Name] = fn
+		}
+	}
+
+	memo := map[string]bool{}
+	var callsAPI func(name string) bool
+	callsAPI = func(name string) bool {
+		if v, ok := memo[name]; ok {
+			return v
+		}
+		memo[name] = false // recursão
+		fn := methods[name]
+		recv := ""
+		if names := fn.Recv.List[0].Names; len(names) > 0 {
+			recv = names[0].Name
+		}
+		found := false
+		ast.Inspect(fn.Body, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok || found {
+				return !found
+			}
+			sel, ok := call.Fun.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			switch x := sel.X.(type) {
+			case *ast.CallExpr: // c.Raw().Operação(...)
+				if inner, ok := x.Fun.(*ast.SelectorExpr); ok && isIdent(inner.X, recv) && inner.Sel.Name == "Raw" {
+					found = true
+				}
+			case *ast.Ident:
+				m := sel.Sel.Name
+				if x.Name == recv && (apiHelpers[m] || (!ast.IsExported(m) && methods[m] != nil && callsAPI(m))) {
+					found = true
+				}
+			}
+			return !found
+		})
+		memo[name] = found
+		return found
+	}
+
+	all = map[string]bool{}
+	for name := range methods {
+		if !ast.IsExported(name) {
+			continue
+		}
+		all[name] = true
+		if callsAPI(name) {
+			direct = append(direct, name)
+		}
+	}
+	return direct, all
+}
+
+func isIdent(e ast.Expr, name string) bool {
+	id, ok := e.(*ast.Ident)
+	return ok && name != "" && id.Name == name
+}
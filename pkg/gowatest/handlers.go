@@ -0,0 +1,943 @@
+package gowatest
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+)
+
+func (s *Server) routes(mux *http.ServeMux) {
+	// app
+	mux.HandleFunc("GET /app/login", s.appLogin)
+	mux.HandleFunc("GET /app/login-with-code", s.appLoginWithCode)
+	mux.HandleFunc("GET /app/logout", s.appLogout)
+	mux.HandleFunc("GET /app/reconnect", s.appReconnect)
+	mux.HandleFunc("GET /app/devices", s.appDevices)
+	mux.HandleFunc("GET /statics/images/qrcode/{name}", s.staticQR)
+	mux.HandleFunc("GET /statics/media/{id}", s.staticMedia)
+
+	// user
+	mux.HandleFunc("GET /user/info", s.requireSession(s.userInfo))
+	mux.HandleFunc("GET /user/avatar", s.requireSession(s.userAvatar))
+	mux.HandleFunc("POST /user/avatar", s.requireSession(s.generic("Success change avatar")))
+	mux.HandleFunc("POST /user/pushname", s.requireSession(s.userPushName))
+	mux.HandleFunc("GET /user/my/privacy", s.requireSession(s.userPrivacy))
+	mux.HandleFunc("GET /user/my/groups", s.requireSession(s.userGroups))
+	mux.HandleFunc("GET /user/my/newsletters", s.requireSession(s.userNewsletters))
+	mux.HandleFunc("GET /user/my/contacts", s.requireSession(s.userContacts))
+	mux.HandleFunc("GET /user/check", s.requireSession(s.userCheck))
+	mux.HandleFunc("GET /user/business-profile", s.requireSession(s.userBusinessProfile))
+
+	// send
+	mux.HandleFunc("POST /send/message", s.requireSession(s.sendMessage))
+	for _, kind := range []string{"image", "audio", "file", "video"} {
+		mux.HandleFunc("POST /send/"+kind, s.requireSession(s.sendMedia(kind)))
+	}
+	mux.HandleFunc("POST /send/contact", s.requireSession(s.sendContact))
+	mux.HandleFunc("POST /send/link", s.requireSession(s.sendLink))
+	mux.HandleFunc("POST /send/location", s.requireSession(s.sendLocation))
+	mux.HandleFunc("POST /send/poll", s.requireSession(s.sendPoll))
+	mux.HandleFunc("POST /send/presence", s.requireSession(s.sendPresence))
+	mux.HandleFunc("POST /send/chat-presence", s.requireSession(s.sendChatPresence))
+
+	// message
+	mux.HandleFunc("POST /message/{message_id}/{action}", s.requireSession(s.messageAction))
+
+	// chat
+	mux.HandleFunc("GET /chats", s.requireSession(s.listChats))
+	mux.HandleFunc("GET /chat/{chat_jid}/messages", s.requireSession(s.chatMessages))
+	mux.HandleFunc("POST /chat/{chat_jid}/label", s.requireSession(s.chatLabel))
+	mux.HandleFunc("POST /chat/{chat_jid}/pin", s.requireSession(s.chatPin))
+
+	// group
+	mux.HandleFunc("GET /group/info", s.requireSession(s.groupInfo))
+	mux.HandleFunc("POST /group", s.requireSession(s.groupCreate))
+	mux.HandleFunc("POST /group/participants", s.requireSession(s.groupParticipants("add")))
+	for _, action := range []string{"remove", "promote", "demote"} {
+		mux.HandleFunc("POST /group/participants/"+action, s.requireSession(s.groupParticipants(action)))
+	}
+	mux.HandleFunc("POST /group/join-with-link", s.requireSession(s.groupJoinWithLink))
+	mux.HandleFunc("GET /group/info-from-link", s.requireSession(s.groupInfoFromLink))
+	mux.HandleFunc("GET /group/participant-requests", s.requireSession(s.groupParticipantRequests))
+	mux.HandleFunc("POST /group/participant-requests/approve", s.requireSession(s.groupParticipantRequestsDecide(true)))
+	mux.HandleFunc("POST /group/participant-requests/reject", s.requireSession(s.groupParticipantRequestsDecide(false)))
+	mux.HandleFunc("POST /group/leave", s.requireSession(s.groupLeave))
+	mux.HandleFunc("POST /group/photo", s.requireSession(s.groupPhoto))
+	mux.HandleFunc("POST /group/name", s.requireSession(s.groupSet(func(g *Group, in groupSetRequest) { g.Name = in.Name })))
+	mux.HandleFunc("POST /group/locked", s.requireSession(s.groupSet(func(g *Group, in groupSetRequest) { g.IsLocked = in.Locked })))
+	mux.HandleFunc("POST /group/announce", s.requireSession(s.groupSet(func(g *Group, in groupSetRequest) { g.IsAnnounce = in.Announce })))
+	mux.HandleFunc("POST /group/topic", s.requireSession(s.groupSet(func(g *Group, in groupSetRequest) { g.Topic = in.Topic })))
+	mux.HandleFunc("GET /group/invite-link", s.requireSession(s.groupInviteLink))
+
+	// newsletter
+	mux.HandleFunc("POST /newsletter/unfollow", s.requireSession(s.generic("Success unfollow newsletter")))
+}
+
+// requireSession exige sessão conectada antes de chamar h, como o servidor real.
+func (s *Server) requireSession(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.Session() != gowa.StateConnected {
+			notLoggedIn(w)
+			return
+		}
+		h(w, r)
+	}
+}
+
+func (s *Server) generic(message string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeOK(w, message, nil)
+	}
+}
+
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		badRequest(w, "invalid json body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+func toJID(phone string) string {
+	if phone == "" || strings.Contains(phone, "@") {
+		return phone
+	}
+	return phone + "@s.whatsapp.net"
+}
+
+// --- app
+
+func (s *Server) appLogin(w http.ResponseWriter, r *http.Request) {
+	if s.Session() == gowa.StateConnected {
+		writeError(w, http.StatusInternalServerError, "ALREADY_LOGGED_IN", "you are already logged in")
+		return
+	}
+	s.mu.Lock()
+	id := s.nextID()
+	s.mu.Unlock()
+	writeOK(w, "Success", map[string]any{
+		"qr_duration": 30,
+		"qr_link":     s.URL + "/statics/images/qrcode/scan-qr-" + id + ".png",
+	})
+}
+
+func (s *Server) appLoginWithCode(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("phone") == "" {
+		badRequest(w, "phone: cannot be blank")
+		return
+	}
+	if s.Session() == gowa.StateConnected {
+		writeError(w, http.StatusInternalServerError, "ALREADY_LOGGED_IN", "you are already logged in")
+		return
+	}
+	writeOK(w, "Success", map[string]any{"pair_code": "ABCD-1234"})
+}
+
+func (s *Server) appLogout(w http.ResponseWriter, r *http.Request) {
+	s.SetSession(gowa.StateLoggedOut)
+	writeOK(w, "Success logout", nil)
+}
+
+func (s *Server) appReconnect(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.session == gowa.StateLoggedOut {
+		notLoggedIn(w)
+		return
+	}
+	s.session = gowa.StateConnected
+	writeOK(w, "Reconnect success", nil)
+}
+
+func (s *Server) appDevices(w http.ResponseWriter, r *http.Request) {
+	switch s.Session() {
+	case gowa.StateConnected:
+		writeOK(w, "Fetch device success", []gowa.Device{{Name: "gowatest", Device: s.cfg.Phone + ".0:1@s.whatsapp.net"}})
+	case gowa.StateDisconnected:
+		notLoggedIn(w)
+	default:
+		writeOK(w, "Fetch device success", []gowa.Device{})
+	}
+}
+
+// staticQR serve um PNG com o padrão localizador de um QR de 21 módulos,
+// suficiente para os renderizadores de terminal.
+func (s *Server) staticQR(w http.ResponseWriter, r *http.Request) {
+	const n, scale, border = 21, 4, 4
+	img := image.NewGray(image.Rect(0, 0, (n+2*border)*scale, (n+2*border)*scale))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	finder := func(x0, y0 int) {
+		for y := 0; y < 7; y++ {
+			for x := 0; x < 7; x++ {
+				ring := x == 0 || y == 0 || x == 6 || y == 6
+				core := x >= 2 && x <= 4 && y >= 2 && y <= 4
+				if !ring && !core {
+					continue
+				}
+				for dy := 0; dy < scale; dy++ {
+					for dx := 0; dx < scale; dx++ {
+						img.Set((border+x0+x)*scale+dx, (border+y0+y)*scale+dy, color.Black)
+					}
+				}
+			}
+		}
+	}
+	finder(0, 0)
+	finder(n-7, 0)
+	finder(0, n-7)
+	w.Header().Set("Content-Type", "image/png")
+	png.Encode(w, img)
+}
+
+func (s *Server) staticMedia(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	f, ok := s.media[r.PathValue("id")]
+	s.mu.Unlock()
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(len(f.Data)))
+	w.Write(f.Data)
+}
+
+// --- user
+
+func (s *Server) userInfo(w http.ResponseWriter, r *http.Request) {
+	phone := r.URL.Query().Get("phone")
+	if phone == "" {
+		badRequest(w, "phone: cannot be blank")
+		return
+	}
+	name := ""
+	s.mu.Lock()
+	for _, c := range s.contacts {
+		if c.JID == toJID(phone) {
+			name = c.Name
+		}
+	}
+	s.mu.Unlock()
+	writeOK(w, "Success", map[string]any{"verified_name": name, "status": "", "picture_id": "", "devices": []any{}})
+}
+
+func (s *Server) userAvatar(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("phone") == "" {
+		badRequest(w, "phone: cannot be blank")
+		return
+	}
+	writeOK(w, "Success", map[string]any{"url": "", "id": "", "type": "image"})
+}
+
+func (s *Server) userPushName(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		PushName string `json:"push_name"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.PushName == "" {
+		badRequest(w, "push_name: cannot be blank")
+		return
+	}
+	s.mu.Lock()
+	s.pushName = in.PushName
+	s.mu.Unlock()
+	writeOK(w, "Success change push name", nil)
+}
+
+func (s *Server) userPrivacy(w http.ResponseWriter, r *http.Request) {
+	writeOK(w, "Success get privacy", map[string]any{
+		"group_add": "all", "last_seen": "all", "status": "all", "profile": "all", "read_receipts": "all",
+	})
+}
+
+func (s *Server) userGroups(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	data := make([]map[string]any, 0, len(s.groups))
+	for _, g := range s.sortedGroups() {
+		data = append(data, groupJSON(g))
+	}
+	s.mu.Unlock()
+	writeOK(w, "Success get list groups", map[string]any{"data": data})
+}
+
+func (s *Server) userNewsletters(w http.ResponseWriter, r *http.Request) {
+	writeOK(w, "Success get list newsletter", map[string]any{"data": []any{}})
+}
+
+func (s *Server) userContacts(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	data := append([]Contact{}, s.contacts...)
+	s.mu.Unlock()
+	writeOK(w, "Success get list contacts", map[string]any{"data": data})
+}
+
+func (s *Server) userCheck(w http.ResponseWriter, r *http.Request) {
+	phone := r.URL.Query().Get("phone")
+	if phone == "" {
+		badRequest(w, "phone: cannot be blank")
+		return
+	}
+	s.mu.Lock()
+	off := s.offWA[strings.TrimSuffix(phone, "@s.whatsapp.net")]
+	s.mu.Unlock()
+	writeOK(w, "Success check user", map[string]any{"is_on_whatsapp": !off})
+}
+
+func (s *Server) userBusinessProfile(w http.ResponseWriter, r *http.Request) {
+	phone := r.URL.Query().Get("phone")
+	if phone == "" {
+		badRequest(w, "phone: cannot be blank")
+		return
+	}
+	writeOK(w, "Success get business profile", map[string]any{"jid": toJID(phone), "categories": []any{}, "business_hours": []any{}})
+}
+
+// --- send
+
+func (s *Server) sent(w http.ResponseWriter, feature string, msg gowa.ChatMessage) {
+	msg.SenderJID = s.JID()
+	msg.IsFromMe = true
+	s.mu.Lock()
+	msg = s.addMessage(msg)
+	s.mu.Unlock()
+	writeOK(w, "Success", map[string]any{"message_id": msg.ID, "status": feature + " success"})
+}
+
+func (s *Server) sendMessage(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Phone   string `json:"phone"`
+		Message string `json:"message"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.Phone == "" || in.Message == "" {
+		badRequest(w, "phone and message: cannot be blank")
+		return
+	}
+	s.sent(w, "Send message", gowa.ChatMessage{ChatJID: toJID(in.Phone), Content: in.Message})
+}
+
+// sendMedia aceita multipart (arquivo no campo kind) ou JSON com <kind>_url.
+func (s *Server) sendMedia(kind string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		rec := recorded(r)
+		fields := rec.Form
+		var file *File
+		if fields == nil {
+			var in map[string]any
+			if !decode(w, r, &in) {
+				return
+			}
+			fields = map[string]string{}
+			for k, v := range in {
+				fields[k] = fmt.Sprint(v)
+			}
+			if u := fields[kind+"_url"]; u != "" {
+				file = &File{Filename: u[strings.LastIndex(u, "/")+1:], Data: []byte(u)}
+			}
+		} else if f, ok := rec.Files[kind]; ok {
+			file = &f
+		} else if u := fields[kind+"_url"]; u != "" {
+			file = &File{Filename: u[strings.LastIndex(u, "/")+1:], Data: []byte(u)}
+		}
+		if fields["phone"] == "" || file == nil {
+			badRequest(w, "phone and "+kind+": cannot be blank")
+			return
+		}
+		msg := gowa.ChatMessage{ChatJID: toJID(fields["phone"]), Content: fields["caption"]}
+		mediaType := kind
+		if kind == "file" {
+			mediaType = "document"
+		}
+		s.mu.Lock()
+		s.attachMedia(&msg, mediaType, file.Filename, file.Data)
+		s.mu.Unlock()
+		s.sent(w, "Send "+kind, msg)
+	}
+}
+
+func (s *Server) sendContact(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Phone        string `json:"phone"`
+		ContactName  string `json:"contact_name"`
+		ContactPhone string `json:"contact_phone"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.Phone == "" || in.ContactName == "" || in.ContactPhone == "" {
+		badRequest(w, "phone, contact_name and contact_phone: cannot be blank")
+		return
+	}
+	s.sent(w, "Send contact", gowa.ChatMessage{ChatJID: toJID(in.Phone), Content: in.ContactName + " " + in.ContactPhone})
+}
+
+func (s *Server) sendLink(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Phone   string `json:"phone"`
+		Link    string `json:"link"`
+		Caption string `json:"caption"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.Phone == "" || in.Link == "" {
+		badRequest(w, "phone and link: cannot be blank")
+		return
+	}
+	s.sent(w, "Send link", gowa.ChatMessage{ChatJID: toJID(in.Phone), Content: strings.TrimSpace(in.Caption + " " + in.Link)})
+}
+
+func (s *Server) sendLocation(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Phone     string `json:"phone"`
+		Latitude  string `json:"latitude"`
+		Longitude string `json:"longitude"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.Phone == "" || in.Latitude == "" || in.Longitude == "" {
+		badRequest(w, "phone, latitude and longitude: cannot be blank")
+		return
+	}
+	s.sent(w, "Send location", gowa.ChatMessage{ChatJID: toJID(in.Phone), Content: in.Latitude + "," + in.Longitude})
+}
+
+func (s *Server) sendPoll(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Phone     string   `json:"phone"`
+		Question  string   `json:"question"`
+		Options   []string `json:"options"`
+		MaxAnswer int      `json:"max_answer"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.Phone == "" || in.Question == "" || len(in.Options) == 0 || in.MaxAnswer <= 0 {
+		badRequest(w, "phone, question, options and max_answer: cannot be blank")
+		return
+	}
+	s.sent(w, "Send poll", gowa.ChatMessage{ChatJID: toJID(in.Phone), Content: in.Question})
+}
+
+func (s *Server) sendPresence(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Type string `json:"type"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.Type != "available" && in.Type != "unavailable" {
+		badRequest(w, "type: must be available or unavailable")
+		return
+	}
+	writeOK(w, "Success", map[string]any{"message_id": "", "status": "Send presence success"})
+}
+
+func (s *Server) sendChatPresence(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Phone  string `json:"phone"`
+		Action string `json:"action"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.Phone == "" || (in.Action != "start" && in.Action != "stop") {
+		badRequest(w, "phone and action (start|stop): cannot be blank")
+		return
+	}
+	writeOK(w, "Success", map[string]any{"message_id": "", "status": "Send chat presence success"})
+}
+
+// --- message
+
+func (s *Server) messageAction(w http.ResponseWriter, r *http.Request) {
+	id, action := r.PathValue("message_id"), r.PathValue("action")
+	var in struct {
+		Phone   string `json:"phone"`
+		Emoji   string `json:"emoji"`
+		Message string `json:"message"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.Phone == "" {
+		badRequest(w, "phone: cannot be blank")
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c := s.chats[toJID(in.Phone)]
+	idx := -1
+	if c != nil {
+		for i, m := range c.messages {
+			if m.ID == id {
+				idx = i
+			}
+		}
+	}
+	if idx < 0 {
+		writeError(w, http.StatusNotFound, "404", "message not found")
+		return
+	}
+	ok := func(status string) {
+		writeOK(w, "Success", map[string]any{"message_id": id, "status": status})
+	}
+	switch action {
+	case "revoke", "delete":
+		c.messages = append(c.messages[:idx], c.messages[idx+1:]...)
+		ok(action + " success")
+	case "reaction":
+		if in.Emoji == "" {
+			badRequest(w, "emoji: cannot be blank")
+			return
+		}
+		s.reactions[id] = in.Emoji
+		ok("reaction success")
+	case "update":
+		if in.Message == "" {
+			badRequest(w, "message: cannot be blank")
+			return
+		}
+		c.messages[idx].Content = in.Message
+		c.messages[idx].UpdatedAt = s.cfg.Now().UTC().Format(time.RFC3339)
+		ok("update success")
+	case "read", "star", "unstar":
+		ok(action + " success")
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// --- chat
+
+func (s *Server) listChats(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, offset := pageParams(q.Get("limit"), q.Get("offset"), 25)
+	search := strings.ToLower(q.Get("search"))
+	hasMedia := q.Get("has_media") == "true"
+	s.mu.Lock()
+	var all []gowa.Chat
+	for _, c := range s.chats {
+		if search != "" && !strings.Contains(strings.ToLower(c.chat.Name), search) {
+			continue
+		}
+		if hasMedia && !c.hasMedia() {
+			continue
+		}
+		all = append(all, c.chat)
+	}
+	s.mu.Unlock()
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].LastMessageTime != all[j].LastMessageTime {
+			return all[i].LastMessageTime > all[j].LastMessageTime
+		}
+		return all[i].JID < all[j].JID
+	})
+	writeOK(w, "Success get chat list", map[string]any{
+		"data":       page(all, limit, offset),
+		"pagination": gowa.Pagination{Limit: limit, Offset: offset, Total: len(all)},
+	})
+}
+
+func (c *chatState) hasMedia() bool {
+	for _, m := range c.messages {
+		if m.MediaType != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) chatMessages(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	limit, offset := pageParams(q.Get("limit"), q.Get("offset"), 50)
+	s.mu.Lock()
+	c := s.chats[r.PathValue("chat_jid")]
+	if c == nil {
+		s.mu.Unlock()
+		writeError(w, http.StatusNotFound, "404", "Chat not found")
+		return
+	}
+	info := c.chat
+	var start, end time.Time
+	if v := q.Get("start_time"); v != "" {
+		start, _ = time.Parse(time.RFC3339, v)
+	}
+	if v := q.Get("end_time"); v != "" {
+		end, _ = time.Parse(time.RFC3339, v)
+	}
+	search := strings.ToLower(q.Get("search"))
+	mediaOnly := q.Get("media_only") == "true"
+	var all []gowa.ChatMessage
+	// mais recentes primeiro, como o servidor real
+	for i := len(c.messages) - 1; i >= 0; i-- {
+		m := c.messages[i]
+		ts, _ := time.Parse(time.RFC3339, m.Timestamp)
+		switch {
+		case !start.IsZero() && ts.Before(start),
+			!end.IsZero() && ts.After(end),
+			mediaOnly && m.MediaType == nil,
+			!mediaOnly && q.Get("is_from_me") != "" && strconv.FormatBool(m.IsFromMe) != q.Get("is_from_me"),
+			search != "" && !strings.Contains(strings.ToLower(m.Content), search):
+			continue
+		}
+		all = append(all, m)
+	}
+	s.mu.Unlock()
+	writeOK(w, "Success get chat messages", map[string]any{
+		"data":       page(all, limit, offset),
+		"pagination": gowa.Pagination{Limit: limit, Offset: offset, Total: len(all)},
+		"chat_info":  info,
+	})
+}
+
+func pageParams(limitStr, offsetStr string, def int) (int, int) {
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit <= 0 {
+		limit = def
+	}
+	limit = min(limit, 100)
+	offset, _ := strconv.Atoi(offsetStr)
+	return limit, max(offset, 0)
+}
+
+func page[T any](all []T, limit, offset int) []T {
+	if offset >= len(all) {
+		return []T{}
+	}
+	return all[offset:min(offset+limit, len(all))]
+}
+
+func (s *Server) chatLabel(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		LabelID   string `json:"label_id"`
+		LabelName string `json:"label_name"`
+		Labeled   bool   `json:"labeled"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.LabelID == "" {
+		badRequest(w, "label_id: cannot be blank")
+		return
+	}
+	jid := r.PathValue("chat_jid")
+	s.mu.Lock()
+	c := s.chats[jid]
+	if c != nil {
+		if in.Labeled {
+			c.labels[in.LabelID] = in.LabelName
+		} else {
+			delete(c.labels, in.LabelID)
+		}
+	}
+	s.mu.Unlock()
+	if c == nil {
+		writeError(w, http.StatusNotFound, "404", "Chat not found")
+		return
+	}
+	writeOK(w, "Success", map[string]any{"status": "success", "message": "Chat labeled", "chat_jid": jid, "label_id": in.LabelID, "labeled": in.Labeled})
+}
+
+func (s *Server) chatPin(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Pinned bool `json:"pinned"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+	jid := r.PathValue("chat_jid")
+	s.mu.Lock()
+	c := s.chats[jid]
+	if c != nil {
+		c.pinned = in.Pinned
+	}
+	s.mu.Unlock()
+	if c == nil {
+		writeError(w, http.StatusNotFound, "404", "Chat not found")
+		return
+	}
+	writeOK(w, "Success", map[string]any{"status": "success", "message": "Chat pinned", "chat_jid": jid, "pinned": in.Pinned})
+}
+
+// --- group
+
+func groupJSON(g *Group) map[string]any {
+	parts := make([]map[string]any, len(g.Participants))
+	for i, p := range g.Participants {
+		parts[i] = map[string]any{"JID": p.JID, "IsAdmin": p.IsAdmin, "IsSuperAdmin": p.IsSuperAdmin, "Error": 0}
+	}
+	return map[string]any{
+		"JID":          g.JID,
+		"OwnerJID":     g.OwnerJID,
+		"Name":         g.Name,
+		"Topic":        g.Topic,
+		"IsLocked":     g.IsLocked,
+		"IsAnnounce":   g.IsAnnounce,
+		"GroupCreated": g.GroupCreated.Format(time.RFC3339),
+		"Participants": parts,
+	}
+}
+
+func (s *Server) sortedGroups() []*Group {
+	out := make([]*Group, 0, len(s.groups))
+	for _, g := range s.groups {
+		out = append(out, g)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].JID < out[j].JID })
+	return out
+}
+
+// withGroup busca o grupo sob o lock; responde 404 se não existir.
+func (s *Server) withGroup(w http.ResponseWriter, jid string, fn func(g *Group)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g := s.groups[jid]
+	if g == nil {
+		writeError(w, http.StatusNotFound, "404", "group not found")
+		return
+	}
+	fn(g)
+}
+
+func (s *Server) groupInfo(w http.ResponseWriter, r *http.Request) {
+	s.withGroup(w, r.URL.Query().Get("group_id"), func(g *Group) {
+		writeOK(w, "Success get group info", groupJSON(g))
+	})
+}
+
+func (s *Server) groupCreate(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Title        string   `json:"title"`
+		Participants []string `json:"participants"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+	if in.Title == "" {
+		badRequest(w, "title: cannot be blank")
+		return
+	}
+	s.mu.Lock()
+	s.seq++
+	g := &Group{
+		JID:          fmt.Sprintf("1203630000%010d@g.us", s.seq),
+		OwnerJID:     s.JID(),
+		Name:         in.Title,
+		GroupCreated: s.cfg.Now(),
+		Participants: []Participant{{JID: s.JID(), IsAdmin: true, IsSuperAdmin: true}},
+		InviteCode:   fmt.Sprintf("INV%08d", s.seq),
+	}
+	for _, p := range in.Participants {
+		g.Participants = append(g.Participants, Participant{JID: toJID(p)})
+	}
+	s.groups[g.JID] = g
+	s.mu.Unlock()
+	writeOK(w, "Success create group", map[string]any{"group_id": g.JID})
+}
+
+type groupSetRequest struct {
+	GroupID      string   `json:"group_id"`
+	Participants []string `json:"participants"`
+	Name         string   `json:"name"`
+	Topic        string   `json:"topic"`
+	Locked       bool     `json:"locked"`
+	Announce     bool     `json:"announce"`
+}
+
+func (s *Server) groupParticipants(action string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in groupSetRequest
+		if !decode(w, r, &in) {
+			return
+		}
+		if in.GroupID == "" || len(in.Participants) == 0 {
+			badRequest(w, "group_id and participants: cannot be blank")
+			return
+		}
+		s.withGroup(w, in.GroupID, func(g *Group) {
+			var results []map[string]any
+			for _, p := range in.Participants {
+				jid := toJID(p)
+				idx := -1
+				for i := range g.Participants {
+					if g.Participants[i].JID == jid {
+						idx = i
+					}
+				}
+				status, msg := "success", "Participant "+action+"d"
+				switch {
+				case action == "add" && idx < 0:
+					g.Participants = append(g.Participants, Participant{JID: jid})
+					msg = "Participant added"
+				case action == "add":
+					status, msg = "error", "Participant already in group"
+				case idx < 0:
+					status, msg = "error", "Participant not in group"
+				case action == "remove":
+					g.Participants = append(g.Participants[:idx], g.Participants[idx+1:]...)
+					msg = "Participant removed"
+				case action == "promote":
+					g.Participants[idx].IsAdmin = true
+				case action == "demote":
+					g.Participants[idx].IsAdmin = false
+				}
+				results = append(results, map[string]any{"participant": jid, "status": status, "message": msg})
+			}
+			writeOK(w, "Success", results)
+		})
+	}
+}
+
+func (s *Server) groupByInvite(link string) *Group {
+	code := link[strings.LastIndex(link, "/")+1:]
+	for _, g := range s.groups {
+		if g.InviteCode == code {
+			return g
+		}
+	}
+	return nil
+}
+
+func (s *Server) groupJoinWithLink(w http.ResponseWriter, r *http.Request) {
+	var in struct {
+		Link string `json:"link"`
+	}
+	if !decode(w, r, &in) {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g := s.groupByInvite(in.Link)
+	if g == nil {
+		writeError(w, http.StatusNotFound, "404", "group not found")
+		return
+	}
+	g.Participants = append(g.Participants, Participant{JID: s.JID()})
+	writeOK(w, "Success joined group", map[string]any{"group_id": g.JID})
+}
+
+func (s *Server) groupInfoFromLink(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	g := s.groupByInvite(r.URL.Query().Get("link"))
+	if g == nil {
+		writeError(w, http.StatusNotFound, "404", "group not found")
+		return
+	}
+	writeOK(w, "Success get group info from link", map[string]any{
+		"group_id":          g.JID,
+		"name":              g.Name,
+		"topic":             g.Topic,
+		"created_at":        g.GroupCreated.Format(time.RFC3339),
+		"participant_count": len(g.Participants),
+		"is_locked":         g.IsLocked,
+		"is_announce":       g.IsAnnounce,
+		"is_ephemeral":      false,
+	})
+}
+
+func (s *Server) groupParticipantRequests(w http.ResponseWriter, r *http.Request) {
+	s.withGroup(w, r.URL.Query().Get("group_id"), func(g *Group) {
+		data := make([]map[string]any, len(g.PendingRequests))
+		for i, jid := range g.PendingRequests {
+			data[i] = map[string]any{"jid": jid, "requested_at": g.GroupCreated.Format(time.RFC3339)}
+		}
+		writeOK(w, "Success getting list requested participants", map[string]any{"data": data})
+	})
+}
+
+func (s *Server) groupParticipantRequestsDecide(approve bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in groupSetRequest
+		if !decode(w, r, &in) {
+			return
+		}
+		if in.GroupID == "" || len(in.Participants) == 0 {
+			badRequest(w, "group_id and participants: cannot be blank")
+			return
+		}
+		s.withGroup(w, in.GroupID, func(g *Group) {
+			decided := map[string]bool{}
+			for _, p := range in.Participants {
+				decided[toJID(p)] = true
+			}
+			pending := g.PendingRequests[:0]
+			for _, jid := range g.PendingRequests {
+				switch {
+				case !decided[jid]:
+					pending = append(pending, jid)
+				case approve:
+					g.Participants = append(g.Participants, Participant{JID: jid})
+				}
+			}
+			g.PendingRequests = pending
+			writeOK(w, "Success", nil)
+		})
+	}
+}
+
+func (s *Server) groupLeave(w http.ResponseWriter, r *http.Request) {
+	var in groupSetRequest
+	if !decode(w, r, &in) {
+		return
+	}
+	s.withGroup(w, in.GroupID, func(g *Group) {
+		for i, p := range g.Participants {
+			if p.JID == s.JID() {
+				g.Participants = append(g.Participants[:i], g.Participants[i+1:]...)
+				break
+			}
+		}
+		writeOK(w, "Success leave group", nil)
+	})
+}
+
+func (s *Server) groupPhoto(w http.ResponseWriter, r *http.Request) {
+	s.withGroup(w, recorded(r).Form["group_id"], func(g *Group) {
+		writeOK(w, "Success update group photo", map[string]any{"picture_id": strconv.FormatInt(s.cfg.Now().Unix(), 10), "message": "Success update group photo"})
+	})
+}
+
+func (s *Server) groupSet(apply func(*Group, groupSetRequest)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var in groupSetRequest
+		if !decode(w, r, &in) {
+			return
+		}
+		if in.GroupID == "" {
+			badRequest(w, "group_id: cannot be blank")
+			return
+		}
+		s.withGroup(w, in.GroupID, func(g *Group) {
+			apply(g, in)
+			writeOK(w, "Success", nil)
+		})
+	}
+}
+
+func (s *Server) groupInviteLink(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.withGroup(w, q.Get("group_id"), func(g *Group) {
+		if q.Get("reset") == "true" {
+			s.seq++
+			g.InviteCode = fmt.Sprintf("INV%08d", s.seq)
+		}
+		writeOK(w, "Success get group invite link", map[string]any{"invite_link": "https://chat.whatsapp.com/" + g.InviteCode, "group_id": g.JID})
+	})
+}
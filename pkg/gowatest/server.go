@@ -0,0 +1,465 @@
+// Package gowatest fornece um servidor gowa falso, em processo, para testes de
+// código que usa gowa.Client. Ele implementa os endpoints de doc/openapi.yaml
+// com estado em memória, exige BasicAuth, grava todas as requisições e permite
+// injetar falhas (latência, erros HTTP e sessão deslogada).
+package gowatest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+)
+
+type Config struct {
+	Username string // default "admin"
+	Password string // default "admin"
+	// Phone é o número da sessão falsa, usado como remetente das mensagens
+	// enviadas; default "6280000000000".
+	Phone string
+	// Session é o estado inicial; default gowa.StateConnected.
+	Session gowa.SessionState
+	// Now permite controlar o relógio usado nos timestamps; default time.Now.
+	Now func() time.Time
+}
+
+// Request é uma requisição recebida pelo servidor.
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+	Form   map[string]string // campos multipart, sem arquivos
+	Files  map[string]File   // arquivos multipart por nome de campo
+}
+
+// JSON decodifica o corpo da requisição em v.
+func (r Request) JSON(v any) error {
+	return json.Unmarshal(r.Body, v)
+}
+
+type File struct {
+	Filename string
+	Data     []byte
+}
+
+// Fault altera as respostas das requisições que casarem com Method e Path.
+type Fault struct {
+	Method  string        // vazio casa qualquer método
+	Path    string        // prefixo do path; vazio casa qualquer path
+	Latency time.Duration // atraso antes de responder
+	Status  int           // se != 0, responde com esse status em vez do handler
+	Body    string        // corpo da resposta de erro; default envelope de erro da API
+	Times   int           // quantas vezes aplicar; 0 = sempre
+}
+
+type Group struct {
+	JID             string
+	OwnerJID        string
+	Name            string
+	Topic           string
+	IsLocked        bool
+	IsAnnounce      bool
+	GroupCreated    time.Time
+	Participants    []Participant
+	InviteCode      string
+	PendingRequests []string
+}
+
+type Participant struct {
+	JID          string
+	IsAdmin      bool
+	IsSuperAdmin bool
+}
+
+type Contact struct {
+	JID  string `json:"jid"`
+	Name string `json:"name"`
+}
+
+// Server é o servidor falso. Use URL (do httptest.Server embutido) ou
+// GowaClient para falar com ele; Server.Client() continua sendo o
+// *http.Client do httptest.Server.
+type Server struct {
+	*httptest.Server
+	cfg Config
+
+	mu        sync.Mutex
+	session   gowa.SessionState
+	pushName  string
+	chats     map[string]*chatState
+	groups    map[string]*Group
+	contacts  []Contact
+	offWA     map[string]bool
+	media     map[string]File
+	reactions map[string]string
+	requests  []Request
+	faults    []*Fault
+	seq       int
+}
+
+type chatState struct {
+	chat     gowa.Chat
+	messages []gowa.ChatMessage
+	labels   map[string]string
+	pinned   bool
+}
+
+// NewServer inicia o servidor. Chame Close ao final do teste.
+func NewServer(cfg Config) *Server {
+	if cfg.Username == "" && cfg.Password == "" {
+		cfg.Username, cfg.Password = "admin", "admin"
+	}
+	if cfg.Phone == "" {
+		cfg.Phone = "6280000000000"
+	}
+	if cfg.Session == "" {
+		cfg.Session = gowa.StateConnected
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	s := &Server{
+		cfg:       cfg,
+		session:   cfg.Session,
+		chats:     map[string]*chatState{},
+		groups:    map[string]*Group{},
+		offWA:     map[string]bool{},
+		media:     map[string]File{},
+		reactions: map[string]string{},
+	}
+	s.Server = httptest.NewServer(s.handler())
+	return s
+}
+
+// GowaClient retorna um gowa.Client apontando para o servidor, com as
+// credenciais configuradas e sem retries (as falhas injetadas chegam direto
+// ao teste).
+func (s *Server) GowaClient() *gowa.Client {
+	c, err := gowa.New(gowa.Config{
+		BaseURL:    s.URL,
+		Username:   s.cfg.Username,
+		Password:   s.cfg.Password,
+		HTTPClient: s.Server.Client(),
+		RetryMax:   -1,
+	})
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// JID do número da sessão falsa.
+func (s *Server) JID() string {
+	return s.cfg.Phone + "@s.whatsapp.net"
+}
+
+// SetSession muda o estado da sessão: StateConnected, StateDisconnected
+// (Reconnect restaura) ou StateLoggedOut (exige novo login).
+func (s *Server) SetSession(st gowa.SessionState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.session = st
+}
+
+func (s *Server) Session() gowa.SessionState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.session
+}
+
+// CompleteLogin simula a leitura do QR (ou do código de pareamento).
+func (s *Server) CompleteLogin() {
+	s.SetSession(gowa.StateConnected)
+}
+
+func (s *Server) AddFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests retorna uma cópia de todas as requisições recebidas, em ordem.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// LastRequest retorna a última requisição recebida para path (ou para
+// qualquer path, se vazio).
+func (s *Server) LastRequest(path string) (Request, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := len(s.requests) - 1; i >= 0; i-- {
+		if path == "" || s.requests[i].Path == path {
+			return s.requests[i], true
+		}
+	}
+	return Request{}, false
+}
+
+func (s *Server) ResetRequests() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+}
+
+// AddChat cria ou substitui os metadados de um chat.
+func (s *Server) AddChat(c gowa.Chat) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.chat(c.JID).chat = c
+}
+
+// AddMessage adiciona uma mensagem (recebida ou enviada) ao chat msg.ChatJID,
+// criando o chat se necessário. ID e Timestamp são gerados se vazios.
+func (s *Server) AddMessage(msg gowa.ChatMessage) gowa.ChatMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.addMessage(msg)
+}
+
+// AddMediaMessage adiciona uma mensagem de mídia cujo conteúdo é servido pelo
+// próprio servidor em msg.URL.
+func (s *Server) AddMediaMessage(msg gowa.ChatMessage, mediaType, filename string, data []byte) gowa.ChatMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.attachMedia(&msg, mediaType, filename, data)
+	return s.addMessage(msg)
+}
+
+// Messages retorna as mensagens do chat em ordem cronológica.
+func (s *Server) Messages(chatJID string) []gowa.ChatMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c := s.chats[chatJID]; c != nil {
+		return append([]gowa.ChatMessage(nil), c.messages...)
+	}
+	return nil
+}
+
+// Reaction retorna o emoji reagido na mensagem, se houver.
+func (s *Server) Reaction(messageID string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.reactions[messageID]
+}
+
+func (s *Server) AddGroup(g Group) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g.GroupCreated.IsZero() {
+		g.GroupCreated = s.cfg.Now()
+	}
+	s.groups[g.JID] = &g
+}
+
+// Group retorna uma cópia do grupo.
+func (s *Server) Group(jid string) (Group, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if g := s.groups[jid]; g != nil {
+		cp := *g
+		cp.Participants = append([]Participant(nil), g.Participants...)
+		return cp, true
+	}
+	return Group{}, false
+}
+
+func (s *Server) AddContact(c Contact) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.contacts = append(s.contacts, c)
+}
+
+// SetOnWhatsApp define a resposta de /user/check para phone; por padrão todo
+// número está no WhatsApp.
+func (s *Server) SetOnWhatsApp(phone string, on bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offWA[strings.TrimSuffix(phone, "@s.whatsapp.net")] = !on
+}
+
+func (s *Server) chat(jid string) *chatState {
+	c := s.chats[jid]
+	if c == nil {
+		c = &chatState{chat: gowa.Chat{JID: jid, CreatedAt: s.cfg.Now().UTC().Format(time.RFC3339)}, labels: map[string]string{}}
+		s.chats[jid] = c
+	}
+	return c
+}
+
+func (s *Server) nextID() string {
+	s.seq++
+	return fmt.Sprintf("3EB0%016X", s.seq)
+}
+
+func (s *Server) addMessage(msg gowa.ChatMessage) gowa.ChatMessage {
+	now := s.cfg.Now().UTC().Format(time.RFC3339)
+	if msg.ID == "" {
+		msg.ID = s.nextID()
+	}
+	if msg.Timestamp == "" {
+		msg.Timestamp = now
+	}
+	if msg.CreatedAt == "" {
+		msg.CreatedAt = now
+	}
+	if msg.UpdatedAt == "" {
+		msg.UpdatedAt = msg.CreatedAt
+	}
+	c := s.chat(msg.ChatJID)
+	c.messages = append(c.messages, msg)
+	sort.SliceStable(c.messages, func(i, j int) bool { return c.messages[i].Timestamp < c.messages[j].Timestamp })
+	if msg.Timestamp > c.chat.LastMessageTime {
+		c.chat.LastMessageTime = msg.Timestamp
+	}
+	c.chat.UpdatedAt = now
+	return msg
+}
+
+func (s *Server) attachMedia(msg *gowa.ChatMessage, mediaType, filename string, data []byte) {
+	id := fmt.Sprintf("m%d", len(s.media)+1)
+	s.media[id] = File{Filename: filename, Data: data}
+	u := s.URL + "/statics/media/" + id
+	n := int64(len(data))
+	msg.MediaType, msg.URL, msg.FileLength = &mediaType, &u, &n
+	if filename != "" {
+		msg.Filename = &filename
+	}
+}
+
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+	s.routes(mux)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := s.record(r)
+		if f := s.matchFault(r); f != nil {
+			if f.Latency > 0 {
+				select {
+				case <-time.After(f.Latency):
+				case <-r.Context().Done():
+					return
+				}
+			}
+			if f.Status != 0 {
+				body := f.Body
+				if body == "" {
+					body = fmt.Sprintf(`{"code":"INTERNAL_SERVER_ERROR","message":"injected fault %d","results":null}`, f.Status)
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(f.Status)
+				io.WriteString(w, body)
+				return
+			}
+		}
+		if !strings.HasPrefix(r.URL.Path, "/statics/") {
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != s.cfg.Username || pass != s.cfg.Password {
+				writeError(w, http.StatusUnauthorized, "401", "Unauthorized access")
+				return
+			}
+		}
+		r.Body = io.NopCloser(bytes.NewReader(rec.Body))
+		mux.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), recordKey{}, rec)))
+	})
+}
+
+type recordKey struct{}
+
+// recorded retorna a Request gravada para r, com o multipart já decodificado.
+func recorded(r *http.Request) Request {
+	rec, _ := r.Context().Value(recordKey{}).(Request)
+	return rec
+}
+
+func (s *Server) record(r *http.Request) Request {
+	body, _ := io.ReadAll(r.Body)
+	rec := Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	}
+	if mt, params, err := mime.ParseMediaType(r.Header.Get("Content-Type")); err == nil && mt == "multipart/form-data" {
+		rec.Form, rec.Files = map[string]string{}, map[string]File{}
+		mr := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			p, err := mr.NextPart()
+			if err != nil {
+				break
+			}
+			b, _ := io.ReadAll(p)
+			if p.FileName() != "" {
+				rec.Files[p.FormName()] = File{Filename: p.FileName(), Data: b}
+			} else {
+				rec.Form[p.FormName()] = string(b)
+			}
+		}
+	}
+	s.mu.Lock()
+	s.requests = append(s.requests, rec)
+	s.mu.Unlock()
+	return rec
+}
+
+func (s *Server) matchFault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, f := range s.faults {
+		if (f.Method == "" || f.Method == r.Method) && strings.HasPrefix(r.URL.Path, f.Path) {
+			cp := *f
+			if f.Times > 0 {
+				f.Times--
+				if f.Times == 0 {
+					s.faults = append(s.faults[:i], s.faults[i+1:]...)
+				}
+			}
+			return &cp
+		}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeOK(w http.ResponseWriter, message string, results any) {
+	writeJSON(w, http.StatusOK, map[string]any{"code": "SUCCESS", "message": message, "results": results})
+}
+
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]any{"code": code, "message": message, "results": nil})
+}
+
+func badRequest(w http.ResponseWriter, message string) {
+	writeError(w, http.StatusBadRequest, "400", message)
+}
+
+func notLoggedIn(w http.ResponseWriter) {
+	writeError(w, http.StatusInternalServerError, "INTERNAL_SERVER_ERROR", "you are not loggin")
+}
@@ -0,0 +1,137 @@
+package gowatest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/drksbr/gowa-client/pkg/gowa"
+	"github.com/drksbr/gowa-client/pkg/gowatest"
+)
+
+// apiError extrai o *gowa.APIError de err, falhando o teste se não houver.
+func apiError(t *testing.T, err error) *gowa.APIError {
+	t.Helper()
+	var apiErr *gowa.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %v, want *gowa.APIError", err)
+	}
+	return apiErr
+}
+
+func TestServerFault(t *testing.T) {
+	ctx := context.Background()
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	c := srv.GowaClient()
+
+	srv.AddFault(gowatest.Fault{Method: http.MethodPost, Path: "/send/", Status: http.StatusServiceUnavailable, Times: 2})
+	for i := range 2 {
+		_, err := c.SendMessage(ctx, "5511999990000", "oi")
+		if e := apiError(t, err); e.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("call %d: status = %d, want 503", i, e.StatusCode)
+		}
+	}
+	// esgotado o Times, a falha sai da lista
+	if _, err := c.SendMessage(ctx, "5511999990000", "oi"); err != nil {
+		t.Fatalf("after Times: %v", err)
+	}
+	// Method e Path filtram as requisições afetadas
+	srv.AddFault(gowatest.Fault{Method: http.MethodPost, Path: "/send/", Status: http.StatusBadGateway})
+	if _, err := c.UserInfo(ctx, "5511999990000"); err != nil {
+		t.Errorf("GET /user/info hit a POST /send/ fault: %v", err)
+	}
+	srv.ClearFaults()
+	if _, err := c.SendMessage(ctx, "5511999990000", "oi"); err != nil {
+		t.Errorf("after ClearFaults: %v", err)
+	}
+	if n := len(srv.Messages("5511999990000@s.whatsapp.net")); n != 2 {
+		t.Errorf("stored messages = %d, want 2 (faulted calls do not reach the handler)", n)
+	}
+}
+
+func TestServerAuth(t *testing.T) {
+	ctx := context.Background()
+	srv := gowatest.NewServer(gowatest.Config{Username: "u", Password: "p"})
+	defer srv.Close()
+	for _, tt := range []struct {
+		name, user, pass string
+	}{
+		{"no credentials", "", ""},
+		{"wrong password", "u", "x"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := gowa.New(gowa.Config{BaseURL: srv.URL, Username: tt.user, Password: tt.pass, HTTPClient: srv.Server.Client(), RetryMax: -1})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = c.SendMessage(ctx, "5511999990000", "oi")
+			if e := apiError(t, err); e.StatusCode != http.StatusUnauthorized {
+				t.Errorf("status = %d, want 401", e.StatusCode)
+			}
+		})
+	}
+	if _, err := srv.GowaClient().SendMessage(ctx, "5511999990000", "oi"); err != nil {
+		t.Errorf("configured credentials: %v", err)
+	}
+}
+
+func TestServerSession(t *testing.T) {
+	ctx := context.Background()
+	srv := gowatest.NewServer(gowatest.Config{Session: gowa.StateLoggedOut})
+	defer srv.Close()
+	c := srv.GowaClient()
+
+	_, err := c.SendMessage(ctx, "5511999990000", "oi")
+	if e := apiError(t, err); e.StatusCode != http.StatusInternalServerError || e.Message != "you are not loggin" {
+		t.Errorf("before login: %d %q, want the gowa session error", e.StatusCode, e.Message)
+	}
+	// login não exige sessão
+	if _, err := c.Login(ctx); err != nil {
+		t.Fatalf("Login: %v", err)
+	}
+	srv.CompleteLogin()
+	if _, err := c.SendMessage(ctx, "5511999990000", "oi"); err != nil {
+		t.Errorf("after login: %v", err)
+	}
+}
+
+func TestServerRequests(t *testing.T) {
+	ctx := context.Background()
+	srv := gowatest.NewServer(gowatest.Config{})
+	defer srv.Close()
+	c := srv.GowaClient()
+
+	if _, err := c.SendMessage(ctx, "5511999990000", "oi"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.UserInfo(ctx, "5511999990000"); err != nil {
+		t.Fatal(err)
+	}
+	reqs := srv.Requests()
+	if len(reqs) != 2 || reqs[0].Method != http.MethodPost || reqs[0].Path != "/send/message" || reqs[1].Path != "/user/info" {
+		t.Fatalf("requests = %+v", reqs)
+	}
+	if got := reqs[1].Query.Get("phone"); got != "5511999990000" {
+		t.Errorf("query phone = %q", got)
+	}
+	last, ok := srv.LastRequest("/send/message")
+	if !ok {
+		t.Fatal("LastRequest(/send/message) not found")
+	}
+	var body struct {
+		Phone   string `json:"phone"`
+		Message string `json:"message"`
+	}
+	if err := last.JSON(&body); err != nil || body.Message != "oi" {
+		t.Errorf("body = %+v, %v", body, err)
+	}
+	if _, _, ok := (&http.Request{Header: last.Header}).BasicAuth(); !ok {
+		t.Error("recorded request lost the Authorization header")
+	}
+	srv.ResetRequests()
+	if _, ok := srv.LastRequest(""); ok {
+		t.Error("requests kept after ResetRequests")
+	}
+}